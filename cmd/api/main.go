@@ -0,0 +1,317 @@
+// Command api runs the user management HTTP API.
+//
+// It is configured through environment variables, see package config.
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+	"time"
+
+	"github.com/yourusername/userapi/config"
+	"github.com/yourusername/userapi/internal/adapters/hasher"
+	"github.com/yourusername/userapi/internal/adapters/mailer"
+	"github.com/yourusername/userapi/internal/adapters/repository/mongodb"
+	"github.com/yourusername/userapi/internal/application"
+	httpport "github.com/yourusername/userapi/internal/ports/http"
+	ports "github.com/yourusername/userapi/internal/ports/mailer"
+	"github.com/yourusername/userapi/pkg/auth"
+	"github.com/yourusername/userapi/pkg/breached"
+	"github.com/yourusername/userapi/pkg/encryption"
+	"github.com/yourusername/userapi/pkg/oidc"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Lifetimes of the tokens sent by email and of short-lived login state
+const (
+	passwordResetTTL     = time.Hour
+	verificationTTL      = 24 * time.Hour
+	verificationCooldown = time.Minute
+	magicLinkTTL         = 15 * time.Minute
+	invitationTTL        = 7 * 24 * time.Hour
+	mfaChallengeTTL      = 5 * time.Minute
+	authorizationCodeTTL = time.Minute
+	federatedLoginTTL    = 10 * time.Minute
+)
+
+func main() {
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Invalid configuration: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(cfg.MongoURI))
+	cancel()
+	if err != nil {
+		log.Fatalf("Failed to connect to MongoDB: %v", err)
+	}
+	defer client.Disconnect(context.Background())
+
+	repos, err := newRepositories(client.Database(cfg.MongoDatabase))
+	if err != nil {
+		log.Fatalf("Failed to prepare repositories: %v", err)
+	}
+
+	passwordHasher, err := hasher.New(hasherConfig(cfg.Hashing))
+	if err != nil {
+		log.Fatalf("Invalid password hashing configuration: %v", err)
+	}
+
+	signingKeyCipher, err := encryption.NewCipherFromBase64(cfg.SigningKeyEncryptionKey)
+	if err != nil {
+		log.Fatalf("Invalid signing key encryption key: %v", err)
+	}
+
+	mfaCipher, err := encryption.NewCipherFromBase64(cfg.MFAEncryptionKey)
+	if err != nil {
+		log.Fatalf("Invalid MFA encryption key: %v", err)
+	}
+
+	// Background jobs stop when the server shuts down
+	jobs, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+
+	keyring := auth.NewKeyring(auth.NewHMACKey("default", []byte(cfg.JWTSecret)))
+	jwtAuth := auth.NewJWTAuthWithKeyring(keyring, cfg.AccessTokenExpiry,
+		auth.WithIssuer(cfg.JWTIssuer),
+		auth.WithAudience(cfg.JWTAudience),
+		auth.WithRevocationStore(repos.revocations),
+	)
+
+	keyRotationService := application.NewKeyRotationService(repos.signingKeys, repos.keyEvents, keyring, signingKeyCipher, cfg.AccessTokenExpiry)
+	if err := keyRotationService.LoadKeys(context.Background()); err != nil {
+		log.Fatalf("Failed to load signing keys: %v", err)
+	}
+	go keyRotationService.RunSync(jobs, cfg.KeySyncInterval)
+
+	var mail ports.Mailer = mailer.NewLogMailer(os.Stdout)
+	if cfg.SMTP.Host != "" {
+		mail = mailer.NewSMTPMailer(cfg.SMTP.Host, cfg.SMTP.Port, cfg.SMTP.Username, cfg.SMTP.Password, cfg.SMTP.From)
+	}
+
+	passwordPolicy, err := newPasswordPolicy(cfg.PasswordPolicy)
+	if err != nil {
+		log.Fatalf("Invalid password policy: %v", err)
+	}
+
+	verificationService := application.NewEmailVerificationService(repos.users, repos.verifications, mail, verificationTTL, verificationCooldown, cfg.PublicURL+"/verify-email")
+
+	userOpts := []application.UserOption{
+		application.WithEmailVerifier(verificationService),
+		application.WithPasswordPolicy(passwordPolicy),
+		application.WithPasswordHistory(repos.passwordHistory, cfg.PasswordHistorySize),
+		application.WithExternalIdentities(repos.identities),
+		application.WithGroups(repos.groups),
+		application.WithSessionRevocation(repos.revocations, repos.refreshTokens),
+		application.WithDeletedEmailPolicy(application.DeletedEmailPolicy(cfg.DeletedEmailPolicy)),
+	}
+	userService := application.NewUserService(repos.users, passwordHasher, userOpts...)
+	go userService.RunPurge(jobs, cfg.PurgeInterval, cfg.DeletedUserRetention)
+
+	throttle := application.NewLoginThrottle(repos.loginAttempts, lockoutPolicy(cfg.AccountLockout), lockoutPolicy(cfg.IPLockout))
+	authService := application.NewAuthService(repos.users, passwordHasher, repos.refreshTokens, repos.revocations, jwtAuth, cfg.RefreshTokenExpiry,
+		application.WithEmailVerification(verificationService, application.UnverifiedLoginPolicy(cfg.UnverifiedLoginPolicy)),
+		application.WithMFAChallenges(repos.mfaChallenges, mfaChallengeTTL),
+		application.WithLoginThrottle(throttle),
+		application.WithUserOptions(userOpts...),
+	)
+
+	apiKeyService := application.NewAPIKeyService(repos.apiKeys, repos.users, authService)
+	oauthService := application.NewOAuthService(repos.oauthClients, repos.authorizationCodes, repos.oauthConsents, repos.users, authService, authorizationCodeTTL)
+
+	handlerOpts := []httpport.HandlerOption{
+		httpport.WithTenantService(application.NewTenantService(repos.tenants)),
+		httpport.WithPasswordResetService(application.NewPasswordResetService(repos.users, repos.passwordResets, authService, mail, passwordResetTTL, cfg.PublicURL+"/reset-password")),
+		httpport.WithEmailVerificationService(verificationService),
+		httpport.WithMFAService(application.NewMFAService(repos.users, repos.mfaChallenges, authService, mfaCipher, cfg.JWTIssuer)),
+		httpport.WithAPIKeyService(apiKeyService),
+		httpport.WithOAuthService(oauthService),
+		httpport.WithAuthorizeLoginURL(cfg.PublicURL + "/login"),
+		httpport.WithOIDCService(application.NewOIDCService(userService, jwtAuth)),
+		httpport.WithIntrospectionService(application.NewIntrospectionService(jwtAuth, oauthService, apiKeyService)),
+		httpport.WithMagicLinkService(application.NewMagicLinkService(repos.users, repos.magicLinks, repos.loginAttempts, authService, mail, magicLinkTTL, cfg.PublicURL+"/login/magic")),
+		httpport.WithGroupService(application.NewGroupService(repos.groups, repos.users)),
+		httpport.WithInvitationService(application.NewInvitationService(repos.invitations, repos.users, repos.groups, authService, mail, []byte(cfg.LinkSigningKey), invitationTTL, cfg.PublicURL+"/accept-invitation")),
+		httpport.WithAccountStatusService(application.NewAccountStatusService(repos.users, repos.statusChanges, authService)),
+	}
+	if len(cfg.IdentityProviders) > 0 {
+		handlerOpts = append(handlerOpts, httpport.WithFederationService(application.NewFederationService(identityProviders(cfg.IdentityProviders), repos.identities, repos.loginStates, repos.users, authService, federatedLoginTTL)))
+	}
+	handler := httpport.NewHandler(userService, authService, jwtAuth, handlerOpts...)
+
+	// Start blocks until the process is asked to stop
+	httpport.NewServer(handler, jwtAuth, cfg.HTTPAddr).Start()
+}
+
+// hasherConfig maps the hashing settings onto the password hasher
+func hasherConfig(cfg config.HashingConfig) hasher.Config {
+	params := hasher.DefaultArgon2idParams
+	params.Memory = cfg.Argon2Memory
+	params.Iterations = cfg.Argon2Iterations
+	params.Parallelism = cfg.Argon2Parallelism
+
+	return hasher.Config{
+		Algorithm:  cfg.Algorithm,
+		BcryptCost: cfg.BcryptCost,
+		Argon2id:   params,
+	}
+}
+
+// newPasswordPolicy builds the password policy, loading the breached
+// password list from a file if one is configured
+func newPasswordPolicy(cfg config.PasswordPolicyConfig) (*application.PasswordPolicy, error) {
+	policy := &application.PasswordPolicy{
+		MinLength:          cfg.MinLength,
+		RequireUpper:       cfg.RequireUpper,
+		RequireLower:       cfg.RequireLower,
+		RequireDigit:       cfg.RequireDigit,
+		RequireSymbol:      cfg.RequireSymbol,
+		ForbidPersonalInfo: cfg.ForbidPersonalInfo,
+	}
+
+	switch {
+	case !cfg.CheckBreached:
+	case cfg.BreachedPasswordsFile != "":
+		list, err := breached.LoadFile(cfg.BreachedPasswordsFile)
+		if err != nil {
+			return nil, err
+		}
+		policy.Blocklist = list
+	default:
+		policy.Blocklist = breached.Default()
+	}
+
+	return policy, nil
+}
+
+// lockoutPolicy maps the lockout settings onto the login throttle's policy
+func lockoutPolicy(cfg config.LockoutConfig) application.LockoutPolicy {
+	return application.LockoutPolicy{
+		BackoffAfter:    cfg.BackoffAfter,
+		BaseDelay:       cfg.BaseDelay,
+		LockoutAfter:    cfg.LockoutAfter,
+		LockoutDuration: cfg.LockoutDuration,
+		Window:          cfg.Window,
+	}
+}
+
+// identityProviders creates the configured upstream identity providers
+func identityProviders(configs []config.IdentityProviderConfig) []application.IdentityProvider {
+	providers := make([]application.IdentityProvider, 0, len(configs))
+	for _, c := range configs {
+		providers = append(providers, application.IdentityProvider{
+			Provider: oidc.NewProvider(oidc.Config{
+				Name:         c.Name,
+				Issuer:       c.Issuer,
+				ClientID:     c.ClientID,
+				ClientSecret: c.ClientSecret,
+				RedirectURL:  c.RedirectURL,
+				Scopes:       c.Scopes,
+			}),
+			TenantID:    c.TenantID,
+			CreateUsers: c.CreateUsers,
+			LinkByEmail: *c.LinkByEmail,
+		})
+	}
+	return providers
+}
+
+// repositories holds the MongoDB adapters
+type repositories struct {
+	users              *mongodb.MongoUserRepository
+	tenants            *mongodb.MongoTenantRepository
+	refreshTokens      *mongodb.MongoRefreshTokenRepository
+	revocations        *mongodb.MongoTokenRevocationRepository
+	signingKeys        *mongodb.MongoSigningKeyRepository
+	keyEvents          *mongodb.MongoKeyRotationEventRepository
+	passwordResets     *mongodb.MongoPasswordResetRepository
+	verifications      *mongodb.MongoEmailVerificationRepository
+	mfaChallenges      *mongodb.MongoMFAChallengeRepository
+	loginAttempts      *mongodb.MongoLoginAttemptRepository
+	passwordHistory    *mongodb.MongoPasswordHistoryRepository
+	apiKeys            *mongodb.MongoAPIKeyRepository
+	oauthClients       *mongodb.MongoOAuthClientRepository
+	authorizationCodes *mongodb.MongoAuthorizationCodeRepository
+	oauthConsents      *mongodb.MongoOAuthConsentRepository
+	identities         *mongodb.MongoExternalIdentityRepository
+	loginStates        *mongodb.MongoFederatedLoginStateRepository
+	magicLinks         *mongodb.MongoMagicLinkRepository
+	groups             *mongodb.MongoGroupRepository
+	invitations        *mongodb.MongoInvitationRepository
+	statusChanges      *mongodb.MongoUserStatusChangeRepository
+}
+
+// newRepositories creates the MongoDB adapters and their indexes
+func newRepositories(db *mongo.Database) (*repositories, error) {
+	r := &repositories{}
+
+	var err error
+	if r.users, err = mongodb.NewMongoUserRepository(db); err != nil {
+		return nil, err
+	}
+	if r.tenants, err = mongodb.NewMongoTenantRepository(db); err != nil {
+		return nil, err
+	}
+	if r.refreshTokens, err = mongodb.NewMongoRefreshTokenRepository(db); err != nil {
+		return nil, err
+	}
+	if r.revocations, err = mongodb.NewMongoTokenRevocationRepository(db); err != nil {
+		return nil, err
+	}
+	if r.signingKeys, err = mongodb.NewMongoSigningKeyRepository(db); err != nil {
+		return nil, err
+	}
+	if r.keyEvents, err = mongodb.NewMongoKeyRotationEventRepository(db); err != nil {
+		return nil, err
+	}
+	if r.passwordResets, err = mongodb.NewMongoPasswordResetRepository(db); err != nil {
+		return nil, err
+	}
+	if r.verifications, err = mongodb.NewMongoEmailVerificationRepository(db); err != nil {
+		return nil, err
+	}
+	if r.mfaChallenges, err = mongodb.NewMongoMFAChallengeRepository(db); err != nil {
+		return nil, err
+	}
+	if r.loginAttempts, err = mongodb.NewMongoLoginAttemptRepository(db); err != nil {
+		return nil, err
+	}
+	if r.passwordHistory, err = mongodb.NewMongoPasswordHistoryRepository(db); err != nil {
+		return nil, err
+	}
+	if r.apiKeys, err = mongodb.NewMongoAPIKeyRepository(db); err != nil {
+		return nil, err
+	}
+	if r.oauthClients, err = mongodb.NewMongoOAuthClientRepository(db); err != nil {
+		return nil, err
+	}
+	if r.authorizationCodes, err = mongodb.NewMongoAuthorizationCodeRepository(db); err != nil {
+		return nil, err
+	}
+	if r.oauthConsents, err = mongodb.NewMongoOAuthConsentRepository(db); err != nil {
+		return nil, err
+	}
+	if r.identities, err = mongodb.NewMongoExternalIdentityRepository(db); err != nil {
+		return nil, err
+	}
+	if r.loginStates, err = mongodb.NewMongoFederatedLoginStateRepository(db); err != nil {
+		return nil, err
+	}
+	if r.magicLinks, err = mongodb.NewMongoMagicLinkRepository(db); err != nil {
+		return nil, err
+	}
+	if r.groups, err = mongodb.NewMongoGroupRepository(db); err != nil {
+		return nil, err
+	}
+	if r.invitations, err = mongodb.NewMongoInvitationRepository(db); err != nil {
+		return nil, err
+	}
+	if r.statusChanges, err = mongodb.NewMongoUserStatusChangeRepository(db); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
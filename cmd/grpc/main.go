@@ -0,0 +1,229 @@
+// Command grpc runs the user management gRPC API next to the HTTP API.
+//
+// It reads the same environment variables as cmd/api, see package config,
+// and listens on GRPC_ADDR. Login and the emailed link flows stay on HTTP.
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+	"time"
+
+	"github.com/yourusername/userapi/config"
+	"github.com/yourusername/userapi/internal/adapters/hasher"
+	"github.com/yourusername/userapi/internal/adapters/mailer"
+	"github.com/yourusername/userapi/internal/adapters/repository/mongodb"
+	"github.com/yourusername/userapi/internal/application"
+	grpcport "github.com/yourusername/userapi/internal/ports/grpc"
+	ports "github.com/yourusername/userapi/internal/ports/mailer"
+	"github.com/yourusername/userapi/pkg/auth"
+	"github.com/yourusername/userapi/pkg/breached"
+	"github.com/yourusername/userapi/pkg/encryption"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Lifetimes matching cmd/api, so both servers issue the same tokens
+const (
+	verificationTTL      = 24 * time.Hour
+	verificationCooldown = time.Minute
+	authorizationCodeTTL = time.Minute
+)
+
+func main() {
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Invalid configuration: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(cfg.MongoURI))
+	cancel()
+	if err != nil {
+		log.Fatalf("Failed to connect to MongoDB: %v", err)
+	}
+	defer client.Disconnect(context.Background())
+
+	repos, err := newRepositories(client.Database(cfg.MongoDatabase))
+	if err != nil {
+		log.Fatalf("Failed to prepare repositories: %v", err)
+	}
+
+	passwordHasher, err := hasher.New(hasherConfig(cfg.Hashing))
+	if err != nil {
+		log.Fatalf("Invalid password hashing configuration: %v", err)
+	}
+
+	signingKeyCipher, err := encryption.NewCipherFromBase64(cfg.SigningKeyEncryptionKey)
+	if err != nil {
+		log.Fatalf("Invalid signing key encryption key: %v", err)
+	}
+
+	// Background jobs stop when the server shuts down
+	jobs, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+
+	keyring := auth.NewKeyring(auth.NewHMACKey("default", []byte(cfg.JWTSecret)))
+	jwtAuth := auth.NewJWTAuthWithKeyring(keyring, cfg.AccessTokenExpiry,
+		auth.WithIssuer(cfg.JWTIssuer),
+		auth.WithAudience(cfg.JWTAudience),
+		auth.WithRevocationStore(repos.revocations),
+	)
+
+	keyRotationService := application.NewKeyRotationService(repos.signingKeys, repos.keyEvents, keyring, signingKeyCipher, cfg.AccessTokenExpiry)
+	if err := keyRotationService.LoadKeys(context.Background()); err != nil {
+		log.Fatalf("Failed to load signing keys: %v", err)
+	}
+	go keyRotationService.RunSync(jobs, cfg.KeySyncInterval)
+
+	var mail ports.Mailer = mailer.NewLogMailer(os.Stdout)
+	if cfg.SMTP.Host != "" {
+		mail = mailer.NewSMTPMailer(cfg.SMTP.Host, cfg.SMTP.Port, cfg.SMTP.Username, cfg.SMTP.Password, cfg.SMTP.From)
+	}
+
+	passwordPolicy, err := newPasswordPolicy(cfg.PasswordPolicy)
+	if err != nil {
+		log.Fatalf("Invalid password policy: %v", err)
+	}
+
+	verificationService := application.NewEmailVerificationService(repos.users, repos.verifications, mail, verificationTTL, verificationCooldown, cfg.PublicURL+"/verify-email")
+
+	userOpts := []application.UserOption{
+		application.WithEmailVerifier(verificationService),
+		application.WithPasswordPolicy(passwordPolicy),
+		application.WithPasswordHistory(repos.passwordHistory, cfg.PasswordHistorySize),
+		application.WithExternalIdentities(repos.identities),
+		application.WithGroups(repos.groups),
+		application.WithSessionRevocation(repos.revocations, repos.refreshTokens),
+		application.WithDeletedEmailPolicy(application.DeletedEmailPolicy(cfg.DeletedEmailPolicy)),
+	}
+	userService := application.NewUserService(repos.users, passwordHasher, userOpts...)
+
+	authService := application.NewAuthService(repos.users, passwordHasher, repos.refreshTokens, repos.revocations, jwtAuth, cfg.RefreshTokenExpiry,
+		application.WithEmailVerification(verificationService, application.UnverifiedLoginPolicy(cfg.UnverifiedLoginPolicy)),
+		application.WithUserOptions(userOpts...),
+	)
+
+	apiKeyService := application.NewAPIKeyService(repos.apiKeys, repos.users, authService)
+	oauthService := application.NewOAuthService(repos.oauthClients, repos.authorizationCodes, repos.oauthConsents, repos.users, authService, authorizationCodeTTL)
+
+	handler := grpcport.NewUserServiceHandler(userService, authService,
+		grpcport.WithTenantService(application.NewTenantService(repos.tenants)),
+		grpcport.WithIntrospectionService(application.NewIntrospectionService(jwtAuth, oauthService, apiKeyService)),
+		grpcport.WithGroupService(application.NewGroupService(repos.groups, repos.users)),
+	)
+
+	// Start blocks until the process is asked to stop
+	grpcport.NewServer(handler, jwtAuth, cfg.GRPCAddr).Start()
+}
+
+// hasherConfig maps the hashing settings onto the password hasher
+func hasherConfig(cfg config.HashingConfig) hasher.Config {
+	params := hasher.DefaultArgon2idParams
+	params.Memory = cfg.Argon2Memory
+	params.Iterations = cfg.Argon2Iterations
+	params.Parallelism = cfg.Argon2Parallelism
+
+	return hasher.Config{
+		Algorithm:  cfg.Algorithm,
+		BcryptCost: cfg.BcryptCost,
+		Argon2id:   params,
+	}
+}
+
+// newPasswordPolicy builds the password policy, loading the breached
+// password list from a file if one is configured
+func newPasswordPolicy(cfg config.PasswordPolicyConfig) (*application.PasswordPolicy, error) {
+	policy := &application.PasswordPolicy{
+		MinLength:          cfg.MinLength,
+		RequireUpper:       cfg.RequireUpper,
+		RequireLower:       cfg.RequireLower,
+		RequireDigit:       cfg.RequireDigit,
+		RequireSymbol:      cfg.RequireSymbol,
+		ForbidPersonalInfo: cfg.ForbidPersonalInfo,
+	}
+
+	switch {
+	case !cfg.CheckBreached:
+	case cfg.BreachedPasswordsFile != "":
+		list, err := breached.LoadFile(cfg.BreachedPasswordsFile)
+		if err != nil {
+			return nil, err
+		}
+		policy.Blocklist = list
+	default:
+		policy.Blocklist = breached.Default()
+	}
+
+	return policy, nil
+}
+
+// repositories holds the MongoDB adapters the gRPC methods use
+type repositories struct {
+	users              *mongodb.MongoUserRepository
+	tenants            *mongodb.MongoTenantRepository
+	refreshTokens      *mongodb.MongoRefreshTokenRepository
+	revocations        *mongodb.MongoTokenRevocationRepository
+	signingKeys        *mongodb.MongoSigningKeyRepository
+	keyEvents          *mongodb.MongoKeyRotationEventRepository
+	verifications      *mongodb.MongoEmailVerificationRepository
+	passwordHistory    *mongodb.MongoPasswordHistoryRepository
+	apiKeys            *mongodb.MongoAPIKeyRepository
+	oauthClients       *mongodb.MongoOAuthClientRepository
+	authorizationCodes *mongodb.MongoAuthorizationCodeRepository
+	oauthConsents      *mongodb.MongoOAuthConsentRepository
+	identities         *mongodb.MongoExternalIdentityRepository
+	groups             *mongodb.MongoGroupRepository
+}
+
+// newRepositories creates the MongoDB adapters and their indexes
+func newRepositories(db *mongo.Database) (*repositories, error) {
+	r := &repositories{}
+
+	var err error
+	if r.users, err = mongodb.NewMongoUserRepository(db); err != nil {
+		return nil, err
+	}
+	if r.tenants, err = mongodb.NewMongoTenantRepository(db); err != nil {
+		return nil, err
+	}
+	if r.refreshTokens, err = mongodb.NewMongoRefreshTokenRepository(db); err != nil {
+		return nil, err
+	}
+	if r.revocations, err = mongodb.NewMongoTokenRevocationRepository(db); err != nil {
+		return nil, err
+	}
+	if r.signingKeys, err = mongodb.NewMongoSigningKeyRepository(db); err != nil {
+		return nil, err
+	}
+	if r.keyEvents, err = mongodb.NewMongoKeyRotationEventRepository(db); err != nil {
+		return nil, err
+	}
+	if r.verifications, err = mongodb.NewMongoEmailVerificationRepository(db); err != nil {
+		return nil, err
+	}
+	if r.passwordHistory, err = mongodb.NewMongoPasswordHistoryRepository(db); err != nil {
+		return nil, err
+	}
+	if r.apiKeys, err = mongodb.NewMongoAPIKeyRepository(db); err != nil {
+		return nil, err
+	}
+	if r.oauthClients, err = mongodb.NewMongoOAuthClientRepository(db); err != nil {
+		return nil, err
+	}
+	if r.authorizationCodes, err = mongodb.NewMongoAuthorizationCodeRepository(db); err != nil {
+		return nil, err
+	}
+	if r.oauthConsents, err = mongodb.NewMongoOAuthConsentRepository(db); err != nil {
+		return nil, err
+	}
+	if r.identities, err = mongodb.NewMongoExternalIdentityRepository(db); err != nil {
+		return nil, err
+	}
+	if r.groups, err = mongodb.NewMongoGroupRepository(db); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
@@ -0,0 +1,122 @@
+// Command keyctl manages the JWT signing keys stored in MongoDB.
+//
+// Usage:
+//
+//	keyctl list
+//	keyctl events
+//	keyctl generate [-alg RS256|ES256|EdDSA]
+//	keyctl promote <kid>
+//	keyctl retire <kid>
+//	keyctl encrypt
+//
+// Private keys are encrypted with the base64 encoded 32 byte key given by
+// -encryption-key or SIGNING_KEY_ENCRYPTION_KEY. The API must use the same
+// key. encrypt encrypts keys stored before keys were encrypted at rest.
+//
+// Running API replicas pick up changes on their next key sync.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/yourusername/userapi/internal/adapters/repository/mongodb"
+	"github.com/yourusername/userapi/internal/application"
+	"github.com/yourusername/userapi/pkg/auth"
+	"github.com/yourusername/userapi/pkg/encryption"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func main() {
+	mongoURI := flag.String("mongo-uri", getEnv("MONGODB_URI", "mongodb://localhost:27017"), "MongoDB connection URI")
+	database := flag.String("db", getEnv("MONGODB_DATABASE", "userapi"), "MongoDB database name")
+	maxTokenLifetime := flag.Duration("max-token-lifetime", 24*time.Hour, "longest lifetime of an issued access token")
+	actor := flag.String("actor", os.Getenv("USER"), "operator recorded in the audit log")
+	alg := flag.String("alg", auth.AlgRS256, "algorithm for generated keys")
+	encryptionKey := flag.String("encryption-key", os.Getenv("SIGNING_KEY_ENCRYPTION_KEY"), "base64 encoded 32 byte key that encrypts private keys")
+	flag.Parse()
+
+	if flag.NArg() < 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	cipher, err := encryption.NewCipherFromBase64(*encryptionKey)
+	if err != nil {
+		log.Fatalf("Invalid signing key encryption key: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(*mongoURI))
+	if err != nil {
+		log.Fatalf("Failed to connect to MongoDB: %v", err)
+	}
+	defer client.Disconnect(context.Background())
+
+	db := client.Database(*database)
+	keys, err := mongodb.NewMongoSigningKeyRepository(db)
+	if err != nil {
+		log.Fatalf("Failed to prepare signing key repository: %v", err)
+	}
+	events, err := mongodb.NewMongoKeyRotationEventRepository(db)
+	if err != nil {
+		log.Fatalf("Failed to prepare key rotation event repository: %v", err)
+	}
+
+	service := application.NewKeyRotationService(
+		keys,
+		events,
+		auth.NewKeyring(nil),
+		cipher,
+		*maxTokenLifetime,
+	)
+
+	var result interface{}
+	switch cmd := flag.Arg(0); cmd {
+	case "list":
+		result, err = service.ListKeys(ctx)
+	case "events":
+		result, err = service.ListEvents(ctx)
+	case "generate":
+		result, err = service.GenerateKey(ctx, *alg, *actor)
+	case "promote":
+		result, err = service.PromoteKey(ctx, requireKeyID(), *actor)
+	case "retire":
+		result, err = service.RetireKey(ctx, requireKeyID(), *actor)
+	case "encrypt":
+		result, err = service.EncryptStoredKeys(ctx, *actor)
+	default:
+		log.Fatalf("Unknown command: %s", cmd)
+	}
+
+	if err != nil {
+		log.Fatalf("Command failed: %v", err)
+	}
+
+	out, _ := json.MarshalIndent(result, "", "  ")
+	fmt.Println(string(out))
+}
+
+// requireKeyID returns the key ID argument or exits
+func requireKeyID() string {
+	if flag.NArg() < 2 {
+		log.Fatal("Missing key ID")
+	}
+	return flag.Arg(1)
+}
+
+// getEnv returns an environment variable or a fallback value
+func getEnv(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
@@ -0,0 +1,79 @@
+// Command tenantctl manages the tenants stored in MongoDB.
+//
+// Usage:
+//
+//	tenantctl list
+//	tenantctl create <slug> <name>
+//
+// Unauthenticated requests name their tenant by slug in the X-Tenant header.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/yourusername/userapi/internal/adapters/repository/mongodb"
+	"github.com/yourusername/userapi/internal/application"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func main() {
+	mongoURI := flag.String("mongo-uri", getEnv("MONGODB_URI", "mongodb://localhost:27017"), "MongoDB connection URI")
+	database := flag.String("db", getEnv("MONGODB_DATABASE", "userapi"), "MongoDB database name")
+	flag.Parse()
+
+	if flag.NArg() < 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(*mongoURI))
+	if err != nil {
+		log.Fatalf("Failed to connect to MongoDB: %v", err)
+	}
+	defer client.Disconnect(context.Background())
+
+	tenantRepo, err := mongodb.NewMongoTenantRepository(client.Database(*database))
+	if err != nil {
+		log.Fatalf("Failed to prepare tenant repository: %v", err)
+	}
+	service := application.NewTenantService(tenantRepo)
+
+	var result interface{}
+	switch cmd := flag.Arg(0); cmd {
+	case "list":
+		result, err = service.ListTenants(ctx)
+	case "create":
+		if flag.NArg() < 3 {
+			log.Fatal("Missing tenant slug or name")
+		}
+		result, err = service.CreateTenant(ctx, flag.Arg(1), strings.Join(flag.Args()[2:], " "))
+	default:
+		log.Fatalf("Unknown command: %s", cmd)
+	}
+
+	if err != nil {
+		log.Fatalf("Command failed: %v", err)
+	}
+
+	out, _ := json.MarshalIndent(result, "", "  ")
+	fmt.Println(string(out))
+}
+
+// getEnv returns an environment variable or a fallback value
+func getEnv(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
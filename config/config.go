@@ -0,0 +1,313 @@
+// Package config loads the API configuration from environment variables
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+)
+
+// Config holds the settings of the API server
+type Config struct {
+	HTTPAddr      string
+	GRPCAddr      string
+	MongoURI      string
+	MongoDatabase string
+
+	// JWTSecret signs tokens with HS256 until a managed key is promoted with keyctl
+	JWTSecret          string
+	JWTIssuer          string
+	JWTAudience        string
+	AccessTokenExpiry  time.Duration
+	RefreshTokenExpiry time.Duration
+	// KeySyncInterval is how often key rotations made with keyctl are picked up
+	KeySyncInterval time.Duration
+	// SigningKeyEncryptionKey is the base64 encoded 32 byte key keyctl encrypts private keys with
+	SigningKeyEncryptionKey string
+	// MFAEncryptionKey is the base64 encoded 32 byte key TOTP secrets are encrypted with
+	MFAEncryptionKey string
+	// LinkSigningKey signs emailed invitation links, it defaults to JWTSecret
+	LinkSigningKey string
+
+	// UnverifiedLoginPolicy is "allow", "restrict" or "deny": what logging in
+	// does for users who have not verified their email address yet
+	UnverifiedLoginPolicy string
+
+	// PublicURL is the frontend base URL emailed links point to
+	PublicURL string
+	SMTP      SMTPConfig
+
+	Hashing HashingConfig
+	// AccountLockout throttles failed logins for one email address
+	AccountLockout LockoutConfig
+	// IPLockout throttles failed logins from one client IP
+	IPLockout LockoutConfig
+
+	PasswordPolicy PasswordPolicyConfig
+	// PasswordHistorySize is how many previous passwords cannot be used again, 0 disables the check
+	PasswordHistorySize int
+
+	// DeletedUserRetention is how long deleted users can be restored before they are purged
+	DeletedUserRetention time.Duration
+	// PurgeInterval is how often users past the retention are purged
+	PurgeInterval time.Duration
+	// DeletedEmailPolicy is "reserve" to keep a deleted user's email address
+	// taken until they are purged, or "release" to free it at once
+	DeletedEmailPolicy string
+
+	// IdentityProviders are read from the JSON file named by IDENTITY_PROVIDERS_FILE
+	IdentityProviders []IdentityProviderConfig
+}
+
+// SMTPConfig configures outgoing email. Without a host, emails are logged instead.
+type SMTPConfig struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+}
+
+// HashingConfig selects how new password hashes are made. Hashes made with
+// another algorithm or other costs are upgraded on the next login.
+type HashingConfig struct {
+	// Algorithm is "argon2id" or "bcrypt"
+	Algorithm  string
+	BcryptCost int
+	// Argon2Memory is in KiB
+	Argon2Memory      uint32
+	Argon2Iterations  uint32
+	Argon2Parallelism uint8
+}
+
+// PasswordPolicyConfig sets the rules passwords have to meet when they are set
+type PasswordPolicyConfig struct {
+	MinLength     int
+	RequireUpper  bool
+	RequireLower  bool
+	RequireDigit  bool
+	RequireSymbol bool
+	// ForbidPersonalInfo rejects passwords containing the user's name or email
+	ForbidPersonalInfo bool
+	// CheckBreached rejects passwords on the breached password list
+	CheckBreached bool
+	// BreachedPasswordsFile replaces the bundled breached password list, one password per line
+	BreachedPasswordsFile string
+}
+
+// LockoutConfig configures how failed logins are throttled. From BackoffAfter
+// failures on every further failure doubles the wait, starting at BaseDelay,
+// up to LockoutDuration. From LockoutAfter failures on logins are refused for
+// LockoutDuration.
+type LockoutConfig struct {
+	BackoffAfter    int
+	BaseDelay       time.Duration
+	LockoutAfter    int
+	LockoutDuration time.Duration
+	// Window is how long failures are remembered after the last one
+	Window time.Duration
+}
+
+// IdentityProviderConfig configures an upstream OpenID Connect provider users can sign in with
+type IdentityProviderConfig struct {
+	Name         string   `json:"name"`
+	Issuer       string   `json:"issuer"`
+	ClientID     string   `json:"client_id"`
+	ClientSecret string   `json:"client_secret"`
+	RedirectURL  string   `json:"redirect_url"`
+	Scopes       []string `json:"scopes"`
+	// TenantID is the tenant whose users sign in with the provider
+	TenantID    string `json:"tenant_id"`
+	CreateUsers bool   `json:"create_users"`
+	// LinkByEmail has no default: linking by email lets anyone who can
+	// register an address at the provider take over the local account
+	// with that address, so every provider has to decide
+	LinkByEmail *bool `json:"link_by_email"`
+}
+
+// Load reads the configuration from the environment, using defaults for unset variables
+func Load() (*Config, error) {
+	l := &loader{}
+
+	cfg := &Config{
+		HTTPAddr:      l.string("HTTP_ADDR", ":8080"),
+		GRPCAddr:      l.string("GRPC_ADDR", ":9090"),
+		MongoURI:      l.string("MONGODB_URI", "mongodb://localhost:27017"),
+		MongoDatabase: l.string("MONGODB_DATABASE", "userapi"),
+
+		JWTSecret:               l.string("JWT_SECRET", ""),
+		JWTIssuer:               l.string("JWT_ISSUER", "http://localhost:8080"),
+		JWTAudience:             l.string("JWT_AUDIENCE", "userapi"),
+		AccessTokenExpiry:       l.duration("ACCESS_TOKEN_EXPIRY", 15*time.Minute),
+		RefreshTokenExpiry:      l.duration("REFRESH_TOKEN_EXPIRY", 30*24*time.Hour),
+		KeySyncInterval:         l.duration("KEY_SYNC_INTERVAL", time.Minute),
+		SigningKeyEncryptionKey: l.string("SIGNING_KEY_ENCRYPTION_KEY", ""),
+		MFAEncryptionKey:        l.string("MFA_ENCRYPTION_KEY", ""),
+		LinkSigningKey:          l.string("LINK_SIGNING_KEY", ""),
+
+		UnverifiedLoginPolicy: l.string("UNVERIFIED_LOGIN_POLICY", "restrict"),
+
+		PublicURL: l.string("PUBLIC_URL", "http://localhost:3000"),
+		SMTP: SMTPConfig{
+			Host:     l.string("SMTP_HOST", ""),
+			Port:     l.int("SMTP_PORT", 587),
+			Username: l.string("SMTP_USERNAME", ""),
+			Password: l.string("SMTP_PASSWORD", ""),
+			From:     l.string("SMTP_FROM", "no-reply@localhost"),
+		},
+
+		Hashing: HashingConfig{
+			Algorithm:         l.string("PASSWORD_HASH_ALGORITHM", "argon2id"),
+			BcryptCost:        l.int("BCRYPT_COST", 10),
+			Argon2Memory:      uint32(l.int("ARGON2_MEMORY_KIB", 64*1024)),
+			Argon2Iterations:  uint32(l.int("ARGON2_ITERATIONS", 3)),
+			Argon2Parallelism: uint8(l.int("ARGON2_PARALLELISM", 2)),
+		},
+		AccountLockout: LockoutConfig{
+			BackoffAfter:    l.int("ACCOUNT_LOGIN_BACKOFF_AFTER", 5),
+			BaseDelay:       l.duration("ACCOUNT_LOGIN_BACKOFF_DELAY", time.Second),
+			LockoutAfter:    l.int("ACCOUNT_LOGIN_LOCKOUT_AFTER", 10),
+			LockoutDuration: l.duration("ACCOUNT_LOGIN_LOCKOUT_DURATION", 15*time.Minute),
+			Window:          l.duration("ACCOUNT_LOGIN_FAILURE_WINDOW", 15*time.Minute),
+		},
+		IPLockout: LockoutConfig{
+			BackoffAfter:    l.int("IP_LOGIN_BACKOFF_AFTER", 20),
+			BaseDelay:       l.duration("IP_LOGIN_BACKOFF_DELAY", time.Second),
+			LockoutAfter:    l.int("IP_LOGIN_LOCKOUT_AFTER", 50),
+			LockoutDuration: l.duration("IP_LOGIN_LOCKOUT_DURATION", 15*time.Minute),
+			Window:          l.duration("IP_LOGIN_FAILURE_WINDOW", 15*time.Minute),
+		},
+		PasswordPolicy: PasswordPolicyConfig{
+			MinLength:             l.int("PASSWORD_MIN_LENGTH", 8),
+			RequireUpper:          l.bool("PASSWORD_REQUIRE_UPPER", false),
+			RequireLower:          l.bool("PASSWORD_REQUIRE_LOWER", false),
+			RequireDigit:          l.bool("PASSWORD_REQUIRE_DIGIT", false),
+			RequireSymbol:         l.bool("PASSWORD_REQUIRE_SYMBOL", false),
+			ForbidPersonalInfo:    l.bool("PASSWORD_FORBID_PERSONAL_INFO", true),
+			CheckBreached:         l.bool("PASSWORD_CHECK_BREACHED", true),
+			BreachedPasswordsFile: l.string("BREACHED_PASSWORDS_FILE", ""),
+		},
+		PasswordHistorySize: l.int("PASSWORD_HISTORY_SIZE", 5),
+
+		DeletedUserRetention: l.duration("DELETED_USER_RETENTION", 30*24*time.Hour),
+		PurgeInterval:        l.duration("PURGE_INTERVAL", time.Hour),
+		DeletedEmailPolicy:   l.string("DELETED_EMAIL_POLICY", "reserve"),
+	}
+
+	if l.err != nil {
+		return nil, l.err
+	}
+
+	if path := os.Getenv("IDENTITY_PROVIDERS_FILE"); path != "" {
+		providers, err := loadIdentityProviders(path)
+		if err != nil {
+			return nil, fmt.Errorf("IDENTITY_PROVIDERS_FILE: %w", err)
+		}
+		cfg.IdentityProviders = providers
+	}
+
+	if cfg.JWTSecret == "" {
+		return nil, fmt.Errorf("JWT_SECRET is required")
+	}
+	if policy := cfg.UnverifiedLoginPolicy; policy != "allow" && policy != "restrict" && policy != "deny" {
+		return nil, fmt.Errorf("UNVERIFIED_LOGIN_POLICY must be allow, restrict or deny")
+	}
+	// The backoff is capped at the lockout duration, so without one it never waits
+	if cfg.AccountLockout.LockoutDuration <= 0 {
+		return nil, fmt.Errorf("ACCOUNT_LOGIN_LOCKOUT_DURATION must be positive")
+	}
+	if cfg.IPLockout.LockoutDuration <= 0 {
+		return nil, fmt.Errorf("IP_LOGIN_LOCKOUT_DURATION must be positive")
+	}
+	if cfg.PasswordPolicy.MinLength < 1 {
+		return nil, fmt.Errorf("PASSWORD_MIN_LENGTH must be at least 1")
+	}
+	if cfg.PasswordHistorySize < 0 {
+		return nil, fmt.Errorf("PASSWORD_HISTORY_SIZE must not be negative")
+	}
+	if cfg.DeletedEmailPolicy != "reserve" && cfg.DeletedEmailPolicy != "release" {
+		return nil, fmt.Errorf("DELETED_EMAIL_POLICY must be reserve or release")
+	}
+	if cfg.LinkSigningKey == "" {
+		cfg.LinkSigningKey = cfg.JWTSecret
+	}
+
+	return cfg, nil
+}
+
+// loadIdentityProviders reads a JSON array of providers
+func loadIdentityProviders(path string) ([]IdentityProviderConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var providers []IdentityProviderConfig
+	if err := json.Unmarshal(data, &providers); err != nil {
+		return nil, err
+	}
+
+	for _, provider := range providers {
+		if provider.Name == "" || provider.Issuer == "" || provider.ClientID == "" || provider.RedirectURL == "" || provider.TenantID == "" {
+			return nil, fmt.Errorf("provider %q needs a name, issuer, client_id, redirect_url and tenant_id", provider.Name)
+		}
+		if provider.LinkByEmail == nil {
+			return nil, fmt.Errorf("provider %q must set link_by_email", provider.Name)
+		}
+	}
+
+	return providers, nil
+}
+
+// loader reads typed environment variables and keeps the first parse error
+type loader struct {
+	err error
+}
+
+func (l *loader) string(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+func (l *loader) int(key string, fallback int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+
+	n, err := strconv.Atoi(value)
+	if err != nil && l.err == nil {
+		l.err = fmt.Errorf("%s: %w", key, err)
+	}
+	return n
+}
+
+func (l *loader) bool(key string, fallback bool) bool {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+
+	b, err := strconv.ParseBool(value)
+	if err != nil && l.err == nil {
+		l.err = fmt.Errorf("%s: %w", key, err)
+	}
+	return b
+}
+
+func (l *loader) duration(key string, fallback time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+
+	d, err := time.ParseDuration(value)
+	if err != nil && l.err == nil {
+		l.err = fmt.Errorf("%s: %w", key, err)
+	}
+	return d
+}
@@ -0,0 +1,96 @@
+package hasher
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Supported algorithm names
+const (
+	AlgorithmBcrypt   = "bcrypt"
+	AlgorithmArgon2id = "argon2id"
+)
+
+// Config selects the algorithm and parameters used for new hashes
+type Config struct {
+	// Algorithm is AlgorithmBcrypt or AlgorithmArgon2id
+	Algorithm  string
+	BcryptCost int
+	Argon2id   Argon2idParams
+}
+
+// DefaultConfig hashes new passwords with argon2id
+func DefaultConfig() Config {
+	return Config{
+		Algorithm:  AlgorithmArgon2id,
+		BcryptCost: bcrypt.DefaultCost,
+		Argon2id:   DefaultArgon2idParams,
+	}
+}
+
+// algorithm is a hasher that recognises its own hashes
+type algorithm interface {
+	Hash(password string) (string, error)
+	Verify(password, encoded string) (bool, error)
+	NeedsRehash(encoded string) bool
+	Identifies(encoded string) bool
+}
+
+// PolicyHasher hashes new passwords with the configured algorithm and
+// verifies hashes made by any supported algorithm. Hashes made by another
+// algorithm, or with other parameters, need a rehash.
+type PolicyHasher struct {
+	preferred  algorithm
+	algorithms []algorithm
+}
+
+// New creates a hasher from the given configuration
+func New(cfg Config) (*PolicyHasher, error) {
+	bcryptHasher := NewBcryptHasher(cfg.BcryptCost)
+	argon2idHasher := NewArgon2idHasher(cfg.Argon2id)
+
+	var preferred algorithm
+	switch strings.ToLower(cfg.Algorithm) {
+	case AlgorithmBcrypt:
+		if cfg.BcryptCost < bcrypt.MinCost || cfg.BcryptCost > bcrypt.MaxCost {
+			return nil, fmt.Errorf("bcrypt cost must be between %d and %d", bcrypt.MinCost, bcrypt.MaxCost)
+		}
+		preferred = bcryptHasher
+	case AlgorithmArgon2id:
+		p := cfg.Argon2id
+		if p.Memory == 0 || p.Iterations == 0 || p.Parallelism == 0 || p.SaltLength == 0 || p.KeyLength == 0 {
+			return nil, fmt.Errorf("argon2id parameters must all be set")
+		}
+		preferred = argon2idHasher
+	default:
+		return nil, fmt.Errorf("unsupported password hashing algorithm: %q", cfg.Algorithm)
+	}
+
+	return &PolicyHasher{
+		preferred:  preferred,
+		algorithms: []algorithm{argon2idHasher, bcryptHasher},
+	}, nil
+}
+
+// Hash hashes a password with the configured algorithm
+func (h *PolicyHasher) Hash(password string) (string, error) {
+	return h.preferred.Hash(password)
+}
+
+// Verify reports whether password matches a hash made by any supported algorithm
+func (h *PolicyHasher) Verify(password, encoded string) (bool, error) {
+	for _, a := range h.algorithms {
+		if a.Identifies(encoded) {
+			return a.Verify(password, encoded)
+		}
+	}
+	return false, ErrMalformedHash
+}
+
+// NeedsRehash reports whether encoded was not made by the configured
+// algorithm with the configured parameters
+func (h *PolicyHasher) NeedsRehash(encoded string) bool {
+	return !h.preferred.Identifies(encoded) || h.preferred.NeedsRehash(encoded)
+}
@@ -0,0 +1,168 @@
+package hasher
+
+import (
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+const testPassword = "correct horse battery staple"
+
+// cheapArgon2id keeps the tests fast, it is far too weak for real use
+var cheapArgon2id = Argon2idParams{Memory: 64, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32}
+
+func newTestHasher(t *testing.T, cfg Config) *PolicyHasher {
+	t.Helper()
+
+	h, err := New(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return h
+}
+
+func TestNewRejectsInvalidConfig(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  Config
+	}{
+		{"unknown algorithm", Config{Algorithm: "md5"}},
+		{"bcrypt cost too low", Config{Algorithm: AlgorithmBcrypt, BcryptCost: bcrypt.MinCost - 1}},
+		{"bcrypt cost too high", Config{Algorithm: AlgorithmBcrypt, BcryptCost: bcrypt.MaxCost + 1}},
+		{"argon2id parameter missing", Config{Algorithm: AlgorithmArgon2id, Argon2id: Argon2idParams{Memory: 64, Iterations: 1, Parallelism: 1, SaltLength: 16}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := New(tt.cfg); err == nil {
+				t.Error("New() succeeded, want an error")
+			}
+		})
+	}
+}
+
+func TestDecodeArgon2id(t *testing.T) {
+	valid, err := NewArgon2idHasher(cheapArgon2id).Hash(testPassword)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parts := strings.Split(valid, "$")
+
+	replace := func(i int, value string) string {
+		changed := append([]string(nil), parts...)
+		changed[i] = value
+		return strings.Join(changed, "$")
+	}
+
+	tests := []struct {
+		name    string
+		encoded string
+		wantErr bool
+	}{
+		{name: "valid", encoded: valid},
+		{name: "too few fields", encoded: strings.Join(parts[:5], "$"), wantErr: true},
+		{name: "other algorithm", encoded: replace(1, "argon2i"), wantErr: true},
+		{name: "other version", encoded: replace(2, "v=16"), wantErr: true},
+		{name: "bad parameters", encoded: replace(3, "m=64,t=x,p=1"), wantErr: true},
+		{name: "bad salt", encoded: replace(4, "not base64!"), wantErr: true},
+		{name: "empty hash", encoded: replace(5, ""), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params, salt, key, err := decodeArgon2id(tt.encoded)
+			if tt.wantErr {
+				if err != ErrMalformedHash {
+					t.Fatalf("decodeArgon2id() error = %v, want ErrMalformedHash", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if params != cheapArgon2id || len(salt) != 16 || len(key) != 32 {
+				t.Errorf("decodeArgon2id() = %+v with a %d byte salt and %d byte key", params, len(salt), len(key))
+			}
+		})
+	}
+}
+
+func TestPolicyHasherVerify(t *testing.T) {
+	argon2idHash, err := NewArgon2idHasher(cheapArgon2id).Hash(testPassword)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bcryptHash, err := NewBcryptHasher(bcrypt.MinCost).Hash(testPassword)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Either configuration verifies hashes made by both algorithms
+	h := newTestHasher(t, Config{Algorithm: AlgorithmBcrypt, BcryptCost: bcrypt.MinCost + 1, Argon2id: DefaultArgon2idParams})
+
+	tests := []struct {
+		name     string
+		password string
+		encoded  string
+		want     bool
+		wantErr  error
+	}{
+		{name: "argon2id match", password: testPassword, encoded: argon2idHash, want: true},
+		{name: "argon2id mismatch", password: "wrong", encoded: argon2idHash},
+		{name: "bcrypt match", password: testPassword, encoded: bcryptHash, want: true},
+		{name: "bcrypt mismatch", password: "wrong", encoded: bcryptHash},
+		{name: "unknown format", password: testPassword, encoded: "plaintext", wantErr: ErrMalformedHash},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ok, err := h.Verify(tt.password, tt.encoded)
+			if err != tt.wantErr {
+				t.Fatalf("Verify() error = %v, want %v", err, tt.wantErr)
+			}
+			if ok != tt.want {
+				t.Errorf("Verify() = %v, want %v", ok, tt.want)
+			}
+		})
+	}
+}
+
+func TestPolicyHasherNeedsRehash(t *testing.T) {
+	hash := func(a algorithm) string {
+		encoded, err := a.Hash(testPassword)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return encoded
+	}
+
+	stronger := cheapArgon2id
+	stronger.Iterations++
+
+	argon2idConfig := Config{Algorithm: AlgorithmArgon2id, BcryptCost: bcrypt.MinCost, Argon2id: cheapArgon2id}
+	bcryptConfig := Config{Algorithm: AlgorithmBcrypt, BcryptCost: bcrypt.MinCost, Argon2id: cheapArgon2id}
+
+	tests := []struct {
+		name    string
+		cfg     Config
+		encoded string
+		want    bool
+	}{
+		{"argon2id with current parameters", argon2idConfig, hash(NewArgon2idHasher(cheapArgon2id)), false},
+		{"argon2id with old parameters", argon2idConfig, hash(NewArgon2idHasher(stronger)), true},
+		{"bcrypt when argon2id is configured", argon2idConfig, hash(NewBcryptHasher(bcrypt.MinCost)), true},
+		{"bcrypt with current cost", bcryptConfig, hash(NewBcryptHasher(bcrypt.MinCost)), false},
+		{"bcrypt with old cost", bcryptConfig, hash(NewBcryptHasher(bcrypt.MinCost + 1)), true},
+		{"argon2id when bcrypt is configured", bcryptConfig, hash(NewArgon2idHasher(cheapArgon2id)), true},
+		{"malformed", argon2idConfig, "$argon2id$v=19$garbage", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := newTestHasher(t, tt.cfg).NeedsRehash(tt.encoded); got != tt.want {
+				t.Errorf("NeedsRehash() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	ports "github.com/yourusername/userapi/internal/ports/mailer"
+)
+
+// LogMailer writes messages to a writer instead of sending them.
+// Use it for local development and tests.
+type LogMailer struct {
+	mu  sync.Mutex
+	out io.Writer
+}
+
+// NewLogMailer creates a mailer that writes messages to out
+func NewLogMailer(out io.Writer) *LogMailer {
+	return &LogMailer{out: out}
+}
+
+// NewFileMailer creates a mailer that appends messages to the file at path
+func NewFileMailer(path string) (*LogMailer, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewLogMailer(file), nil
+}
+
+// Send writes a message
+func (m *LogMailer) Send(ctx context.Context, msg ports.Message) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	_, err := fmt.Fprintf(m.out, "--- %s\nTo: %s\nSubject: %s\n\n%s\n\n",
+		time.Now().Format(time.RFC3339), msg.To, msg.Subject, msg.Body)
+	return err
+}
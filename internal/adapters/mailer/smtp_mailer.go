@@ -0,0 +1,59 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+
+	ports "github.com/yourusername/userapi/internal/ports/mailer"
+)
+
+// SMTPMailer sends email through an SMTP server
+type SMTPMailer struct {
+	addr string
+	auth smtp.Auth
+	from string
+}
+
+// NewSMTPMailer creates a new SMTP mailer. Authentication is skipped when username is empty.
+func NewSMTPMailer(host string, port int, username, password, from string) *SMTPMailer {
+	var auth smtp.Auth
+	if username != "" {
+		auth = smtp.PlainAuth("", username, password, host)
+	}
+
+	return &SMTPMailer{
+		addr: net.JoinHostPort(host, fmt.Sprint(port)),
+		auth: auth,
+		from: from,
+	}
+}
+
+// Send delivers a message
+func (m *SMTPMailer) Send(ctx context.Context, msg ports.Message) error {
+	// Reject header injection through the recipient or subject
+	if strings.ContainsAny(msg.To+msg.Subject, "\r\n") {
+		return fmt.Errorf("invalid message header")
+	}
+
+	body := strings.Join([]string{
+		"From: " + m.from,
+		"To: " + msg.To,
+		"Subject: " + msg.Subject,
+		"Date: " + time.Now().Format(time.RFC1123Z),
+		"MIME-Version: 1.0",
+		"Content-Type: text/plain; charset=UTF-8",
+		"",
+		msg.Body,
+	}, "\r\n")
+
+	// net/smtp has no context support, so honour cancellation before dialing
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	return smtp.SendMail(m.addr, m.auth, m.from, []string{msg.To}, []byte(body))
+}
@@ -0,0 +1,67 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/yourusername/userapi/internal/domain"
+)
+
+// LoginAttemptRepository is an in-memory implementation of LoginAttemptRepository.
+// State is local to the process, so it is only suitable for a single replica.
+type LoginAttemptRepository struct {
+	mu       sync.Mutex
+	attempts map[string]domain.LoginAttempts
+}
+
+// NewLoginAttemptRepository creates a new in-memory login attempt repository
+func NewLoginAttemptRepository() *LoginAttemptRepository {
+	return &LoginAttemptRepository{
+		attempts: make(map[string]domain.LoginAttempts),
+	}
+}
+
+// Get returns the failures recorded for key
+func (r *LoginAttemptRepository) Get(ctx context.Context, key string) (*domain.LoginAttempts, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	attempts, ok := r.attempts[key]
+	if !ok || !time.Now().Before(attempts.ExpiresAt) {
+		return &domain.LoginAttempts{Key: key}, nil
+	}
+
+	return &attempts, nil
+}
+
+// RecordFailure adds a failure at now
+func (r *LoginAttemptRepository) RecordFailure(ctx context.Context, key string, now time.Time, ttl time.Duration) (*domain.LoginAttempts, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Drop counters that have expired on their own
+	for k, a := range r.attempts {
+		if !now.Before(a.ExpiresAt) {
+			delete(r.attempts, k)
+		}
+	}
+
+	attempts := r.attempts[key]
+	attempts.Key = key
+	attempts.Failures++
+	attempts.LastFailureAt = now
+	attempts.ExpiresAt = now.Add(ttl)
+	r.attempts[key] = attempts
+
+	return &attempts, nil
+}
+
+// Reset forgets the failures recorded for key
+func (r *LoginAttemptRepository) Reset(ctx context.Context, key string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.attempts, key)
+	return nil
+}
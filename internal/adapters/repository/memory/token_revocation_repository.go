@@ -0,0 +1,66 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// TokenRevocationRepository is an in-memory implementation of TokenRevocationRepository.
+// State is local to the process, so it is only suitable for a single replica.
+type TokenRevocationRepository struct {
+	mu            sync.RWMutex
+	revokedTokens map[string]time.Time
+	watermarks    map[string]time.Time
+}
+
+// NewTokenRevocationRepository creates a new in-memory token revocation repository
+func NewTokenRevocationRepository() *TokenRevocationRepository {
+	return &TokenRevocationRepository{
+		revokedTokens: make(map[string]time.Time),
+		watermarks:    make(map[string]time.Time),
+	}
+}
+
+// Revoke denylists a token ID until it expires
+func (r *TokenRevocationRepository) Revoke(ctx context.Context, tokenID string, expiresAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	// Drop entries for tokens that have expired on their own
+	for id, exp := range r.revokedTokens {
+		if !now.Before(exp) {
+			delete(r.revokedTokens, id)
+		}
+	}
+
+	r.revokedTokens[tokenID] = expiresAt
+	return nil
+}
+
+// IsRevoked reports whether a token ID has been denylisted
+func (r *TokenRevocationRepository) IsRevoked(ctx context.Context, tokenID string) (bool, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	expiresAt, ok := r.revokedTokens[tokenID]
+	return ok && time.Now().Before(expiresAt), nil
+}
+
+// RevokeAllBefore sets the user's watermark
+func (r *TokenRevocationRepository) RevokeAllBefore(ctx context.Context, userID string, before time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.watermarks[userID] = before
+	return nil
+}
+
+// RevokedBefore returns the user's watermark, or the zero time if none is set
+func (r *TokenRevocationRepository) RevokedBefore(ctx context.Context, userID string) (time.Time, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.watermarks[userID], nil
+}
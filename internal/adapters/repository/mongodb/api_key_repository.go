@@ -0,0 +1,128 @@
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"github.com/yourusername/userapi/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoAPIKeyRepository is a MongoDB implementation of APIKeyRepository
+type MongoAPIKeyRepository struct {
+	collection *mongo.Collection
+}
+
+// NewMongoAPIKeyRepository creates a new MongoDB API key repository
+func NewMongoAPIKeyRepository(db *mongo.Database) (*MongoAPIKeyRepository, error) {
+	collection := db.Collection("api_keys")
+
+	indexModels := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "key_hash", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "created_at", Value: -1}},
+		},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := collection.Indexes().CreateMany(ctx, indexModels)
+	if err != nil {
+		return nil, err
+	}
+
+	return &MongoAPIKeyRepository{collection: collection}, nil
+}
+
+// Create stores a new API key
+func (r *MongoAPIKeyRepository) Create(ctx context.Context, key *domain.APIKey) error {
+	if key.ID.IsZero() {
+		key.ID = primitive.NewObjectID()
+	}
+
+	_, err := r.collection.InsertOne(ctx, key)
+	return err
+}
+
+// FindByHash finds an API key by its hash
+func (r *MongoAPIKeyRepository) FindByHash(ctx context.Context, keyHash string) (*domain.APIKey, error) {
+	var key domain.APIKey
+	err := r.collection.FindOne(ctx, bson.M{"key_hash": keyHash}).Decode(&key)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, domain.ErrAPIKeyNotFound
+		}
+		return nil, err
+	}
+
+	return &key, nil
+}
+
+// FindByUserID returns every key of a user, newest first
+func (r *MongoAPIKeyRepository) FindByUserID(ctx context.Context, userID string) ([]*domain.APIKey, error) {
+	objectID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})
+	cursor, err := r.collection.Find(ctx, bson.M{"user_id": objectID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var keys []*domain.APIKey
+	if err := cursor.All(ctx, &keys); err != nil {
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+// Revoke revokes a key owned by userID
+func (r *MongoAPIKeyRepository) Revoke(ctx context.Context, userID, id string) error {
+	userObjectID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return err
+	}
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return domain.ErrAPIKeyNotFound
+	}
+
+	result, err := r.collection.UpdateOne(
+		ctx,
+		bson.M{"_id": objectID, "user_id": userObjectID},
+		// $min keeps the time of the first revocation
+		bson.M{"$min": bson.M{"revoked_at": time.Now()}},
+	)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return domain.ErrAPIKeyNotFound
+	}
+
+	return nil
+}
+
+// TouchLastUsed records when a key was last used
+func (r *MongoAPIKeyRepository) TouchLastUsed(ctx context.Context, id string, at time.Time) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.collection.UpdateOne(ctx, bson.M{"_id": objectID}, bson.M{"$set": bson.M{"last_used_at": at}})
+	return err
+}
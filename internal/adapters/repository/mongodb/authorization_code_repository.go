@@ -0,0 +1,91 @@
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"github.com/yourusername/userapi/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoAuthorizationCodeRepository is a MongoDB implementation of AuthorizationCodeRepository
+type MongoAuthorizationCodeRepository struct {
+	collection *mongo.Collection
+}
+
+// NewMongoAuthorizationCodeRepository creates a new MongoDB authorization code repository
+func NewMongoAuthorizationCodeRepository(db *mongo.Database) (*MongoAuthorizationCodeRepository, error) {
+	collection := db.Collection("oauth_authorization_codes")
+
+	indexModels := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "code_hash", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			// Let MongoDB remove codes once they have expired
+			Keys:    bson.D{{Key: "expires_at", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(0),
+		},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := collection.Indexes().CreateMany(ctx, indexModels)
+	if err != nil {
+		return nil, err
+	}
+
+	return &MongoAuthorizationCodeRepository{collection: collection}, nil
+}
+
+// Create stores a new authorization code
+func (r *MongoAuthorizationCodeRepository) Create(ctx context.Context, code *domain.AuthorizationCode) error {
+	if code.ID.IsZero() {
+		code.ID = primitive.NewObjectID()
+	}
+
+	_, err := r.collection.InsertOne(ctx, code)
+	return err
+}
+
+// FindByHash finds an authorization code by its hash
+func (r *MongoAuthorizationCodeRepository) FindByHash(ctx context.Context, codeHash string) (*domain.AuthorizationCode, error) {
+	var code domain.AuthorizationCode
+	err := r.collection.FindOne(ctx, bson.M{"code_hash": codeHash}).Decode(&code)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, domain.ErrInvalidToken
+		}
+		return nil, err
+	}
+
+	return &code, nil
+}
+
+// MarkUsed atomically redeems an unused code
+func (r *MongoAuthorizationCodeRepository) MarkUsed(ctx context.Context, id string) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	result, err := r.collection.UpdateOne(
+		ctx,
+		bson.M{"_id": objectID, "used_at": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"used_at": time.Now()}},
+	)
+	if err != nil {
+		return err
+	}
+
+	if result.ModifiedCount == 0 {
+		return domain.ErrInvalidToken
+	}
+
+	return nil
+}
@@ -0,0 +1,102 @@
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"github.com/yourusername/userapi/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoEmailVerificationRepository is a MongoDB implementation of EmailVerificationRepository
+type MongoEmailVerificationRepository struct {
+	collection *mongo.Collection
+}
+
+// NewMongoEmailVerificationRepository creates a new MongoDB email verification repository
+func NewMongoEmailVerificationRepository(db *mongo.Database) (*MongoEmailVerificationRepository, error) {
+	collection := db.Collection("email_verification_tokens")
+
+	indexModels := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "token_hash", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "created_at", Value: -1}},
+		},
+		{
+			// Let MongoDB remove tokens once they have expired
+			Keys:    bson.D{{Key: "expires_at", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(0),
+		},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := collection.Indexes().CreateMany(ctx, indexModels)
+	if err != nil {
+		return nil, err
+	}
+
+	return &MongoEmailVerificationRepository{collection: collection}, nil
+}
+
+// Create stores a new verification token
+func (r *MongoEmailVerificationRepository) Create(ctx context.Context, token *domain.EmailVerificationToken) error {
+	if token.ID.IsZero() {
+		token.ID = primitive.NewObjectID()
+	}
+
+	_, err := r.collection.InsertOne(ctx, token)
+	return err
+}
+
+// FindByHash finds a verification token by its hash
+func (r *MongoEmailVerificationRepository) FindByHash(ctx context.Context, tokenHash string) (*domain.EmailVerificationToken, error) {
+	var token domain.EmailVerificationToken
+	err := r.collection.FindOne(ctx, bson.M{"token_hash": tokenHash}).Decode(&token)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, domain.ErrInvalidToken
+		}
+		return nil, err
+	}
+
+	return &token, nil
+}
+
+// FindLatestByUserID returns the most recently issued token of a user
+func (r *MongoEmailVerificationRepository) FindLatestByUserID(ctx context.Context, userID string) (*domain.EmailVerificationToken, error) {
+	objectID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var token domain.EmailVerificationToken
+	opts := options.FindOne().SetSort(bson.D{{Key: "created_at", Value: -1}})
+	err = r.collection.FindOne(ctx, bson.M{"user_id": objectID}, opts).Decode(&token)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, domain.ErrInvalidToken
+		}
+		return nil, err
+	}
+
+	return &token, nil
+}
+
+// DeleteByUserID removes every outstanding token of a user
+func (r *MongoEmailVerificationRepository) DeleteByUserID(ctx context.Context, userID string) error {
+	objectID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.collection.DeleteMany(ctx, bson.M{"user_id": objectID})
+	return err
+}
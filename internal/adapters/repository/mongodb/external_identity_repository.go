@@ -0,0 +1,113 @@
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"github.com/yourusername/userapi/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoExternalIdentityRepository is a MongoDB implementation of ExternalIdentityRepository
+type MongoExternalIdentityRepository struct {
+	collection *mongo.Collection
+}
+
+// NewMongoExternalIdentityRepository creates a new MongoDB external identity repository
+func NewMongoExternalIdentityRepository(db *mongo.Database) (*MongoExternalIdentityRepository, error) {
+	collection := db.Collection("external_identities")
+
+	indexModels := []mongo.IndexModel{
+		{
+			// A provider account can only be linked to one user
+			Keys:    bson.D{{Key: "provider", Value: 1}, {Key: "subject", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "user_id", Value: 1}},
+		},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := collection.Indexes().CreateMany(ctx, indexModels)
+	if err != nil {
+		return nil, err
+	}
+
+	return &MongoExternalIdentityRepository{collection: collection}, nil
+}
+
+// Create links a new external identity
+func (r *MongoExternalIdentityRepository) Create(ctx context.Context, identity *domain.ExternalIdentity) error {
+	if identity.ID.IsZero() {
+		identity.ID = primitive.NewObjectID()
+	}
+
+	_, err := r.collection.InsertOne(ctx, identity)
+	if mongo.IsDuplicateKeyError(err) {
+		return domain.ErrIdentityConflict
+	}
+	return err
+}
+
+// FindBySubject finds the identity of a provider account
+func (r *MongoExternalIdentityRepository) FindBySubject(ctx context.Context, provider, subject string) (*domain.ExternalIdentity, error) {
+	var identity domain.ExternalIdentity
+	err := r.collection.FindOne(ctx, bson.M{"provider": provider, "subject": subject}).Decode(&identity)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, domain.ErrIdentityNotFound
+		}
+		return nil, err
+	}
+
+	return &identity, nil
+}
+
+// FindByUserID returns every identity linked to a user
+func (r *MongoExternalIdentityRepository) FindByUserID(ctx context.Context, userID string) ([]*domain.ExternalIdentity, error) {
+	objectID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	cursor, err := r.collection.Find(ctx, bson.M{"user_id": objectID})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var identities []*domain.ExternalIdentity
+	if err := cursor.All(ctx, &identities); err != nil {
+		return nil, err
+	}
+
+	return identities, nil
+}
+
+// TouchLastLogin records when the identity was last used to sign in
+func (r *MongoExternalIdentityRepository) TouchLastLogin(ctx context.Context, id string, at time.Time) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.collection.UpdateOne(ctx, bson.M{"_id": objectID}, bson.M{"$set": bson.M{"last_login_at": at}})
+	return err
+}
+
+// DeleteByUserID removes every identity linked to a user
+func (r *MongoExternalIdentityRepository) DeleteByUserID(ctx context.Context, userID string) error {
+	objectID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.collection.DeleteMany(ctx, bson.M{"user_id": objectID})
+	return err
+}
@@ -0,0 +1,68 @@
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"github.com/yourusername/userapi/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoFederatedLoginStateRepository is a MongoDB implementation of FederatedLoginStateRepository
+type MongoFederatedLoginStateRepository struct {
+	collection *mongo.Collection
+}
+
+// NewMongoFederatedLoginStateRepository creates a new MongoDB federated login state repository
+func NewMongoFederatedLoginStateRepository(db *mongo.Database) (*MongoFederatedLoginStateRepository, error) {
+	collection := db.Collection("federated_login_states")
+
+	indexModels := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "state_hash", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			// Let MongoDB remove abandoned logins once they have expired
+			Keys:    bson.D{{Key: "expires_at", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(0),
+		},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := collection.Indexes().CreateMany(ctx, indexModels)
+	if err != nil {
+		return nil, err
+	}
+
+	return &MongoFederatedLoginStateRepository{collection: collection}, nil
+}
+
+// Create stores a new login state
+func (r *MongoFederatedLoginStateRepository) Create(ctx context.Context, state *domain.FederatedLoginState) error {
+	if state.ID.IsZero() {
+		state.ID = primitive.NewObjectID()
+	}
+
+	_, err := r.collection.InsertOne(ctx, state)
+	return err
+}
+
+// Consume finds and removes a login state by its hash
+func (r *MongoFederatedLoginStateRepository) Consume(ctx context.Context, stateHash string) (*domain.FederatedLoginState, error) {
+	var state domain.FederatedLoginState
+	err := r.collection.FindOneAndDelete(ctx, bson.M{"state_hash": stateHash}).Decode(&state)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, domain.ErrInvalidToken
+		}
+		return nil, err
+	}
+
+	return &state, nil
+}
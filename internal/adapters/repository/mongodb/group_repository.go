@@ -0,0 +1,245 @@
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"github.com/yourusername/userapi/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoGroupRepository is a MongoDB implementation of GroupRepository.
+// Every query is filtered by the tenant the context is scoped to.
+type MongoGroupRepository struct {
+	collection *mongo.Collection
+}
+
+// NewMongoGroupRepository creates a new MongoDB group repository
+func NewMongoGroupRepository(db *mongo.Database) (*MongoGroupRepository, error) {
+	collection := db.Collection("groups")
+
+	indexModels := []mongo.IndexModel{
+		{
+			// Group names are unique within a tenant
+			Keys:    bson.D{{Key: "tenant_id", Value: 1}, {Key: "name", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "member_ids", Value: 1}},
+		},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := collection.Indexes().CreateMany(ctx, indexModels)
+	if err != nil {
+		return nil, err
+	}
+
+	return &MongoGroupRepository{collection: collection}, nil
+}
+
+// Create adds a new group to the context's tenant
+func (r *MongoGroupRepository) Create(ctx context.Context, group *domain.Group) error {
+	tenantID, err := scopedTenantID(ctx)
+	if err != nil {
+		return err
+	}
+
+	if group.ID.IsZero() {
+		group.ID = primitive.NewObjectID()
+	}
+	group.TenantID = tenantID
+
+	_, err = r.collection.InsertOne(ctx, group)
+	if mongo.IsDuplicateKeyError(err) {
+		return domain.ErrGroupAlreadyExists
+	}
+	return err
+}
+
+// FindByID finds a group by ID
+func (r *MongoGroupRepository) FindByID(ctx context.Context, id string) (*domain.Group, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, domain.ErrGroupNotFound
+	}
+
+	filter, err := scopedFilter(ctx, bson.M{"_id": objectID})
+	if err != nil {
+		return nil, err
+	}
+
+	var group domain.Group
+	err = r.collection.FindOne(ctx, filter).Decode(&group)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, domain.ErrGroupNotFound
+		}
+		return nil, err
+	}
+
+	return &group, nil
+}
+
+// FindAll returns every group of the context's tenant
+func (r *MongoGroupRepository) FindAll(ctx context.Context) ([]*domain.Group, error) {
+	filter, err := scopedFilter(ctx, bson.M{})
+	if err != nil {
+		return nil, err
+	}
+
+	return r.find(ctx, filter)
+}
+
+// FindByMember returns the groups a user is a member of
+func (r *MongoGroupRepository) FindByMember(ctx context.Context, userID string) ([]*domain.Group, error) {
+	objectID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, domain.ErrUserNotFound
+	}
+
+	filter, err := scopedFilter(ctx, bson.M{"member_ids": objectID})
+	if err != nil {
+		return nil, err
+	}
+
+	return r.find(ctx, filter)
+}
+
+// Update saves the group's name and description
+func (r *MongoGroupRepository) Update(ctx context.Context, group *domain.Group) error {
+	filter, err := scopedFilter(ctx, bson.M{"_id": group.ID})
+	if err != nil {
+		return err
+	}
+
+	group.UpdatedAt = time.Now()
+	update := bson.M{"$set": bson.M{
+		"name":        group.Name,
+		"description": group.Description,
+		"updated_at":  group.UpdatedAt,
+	}}
+
+	result, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return domain.ErrGroupAlreadyExists
+		}
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return domain.ErrGroupNotFound
+	}
+
+	return nil
+}
+
+// Delete removes a group
+func (r *MongoGroupRepository) Delete(ctx context.Context, id string) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return domain.ErrGroupNotFound
+	}
+
+	filter, err := scopedFilter(ctx, bson.M{"_id": objectID})
+	if err != nil {
+		return err
+	}
+
+	result, err := r.collection.DeleteOne(ctx, filter)
+	if err != nil {
+		return err
+	}
+
+	if result.DeletedCount == 0 {
+		return domain.ErrGroupNotFound
+	}
+
+	return nil
+}
+
+// AddMember adds a user to a group. Adding a member twice has no effect.
+func (r *MongoGroupRepository) AddMember(ctx context.Context, groupID, userID string) error {
+	return r.updateMembers(ctx, groupID, userID, "$addToSet")
+}
+
+// RemoveMember removes a user from a group
+func (r *MongoGroupRepository) RemoveMember(ctx context.Context, groupID, userID string) error {
+	return r.updateMembers(ctx, groupID, userID, "$pull")
+}
+
+// RemoveMemberFromAll removes a user from every group of the context's tenant
+func (r *MongoGroupRepository) RemoveMemberFromAll(ctx context.Context, userID string) error {
+	objectID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return domain.ErrUserNotFound
+	}
+
+	filter, err := scopedFilter(ctx, bson.M{"member_ids": objectID})
+	if err != nil {
+		return err
+	}
+
+	update := bson.M{
+		"$pull": bson.M{"member_ids": objectID},
+		"$set":  bson.M{"updated_at": time.Now()},
+	}
+
+	_, err = r.collection.UpdateMany(ctx, filter, update)
+	return err
+}
+
+// updateMembers applies operator, $addToSet or $pull, for userID to a group's members
+func (r *MongoGroupRepository) updateMembers(ctx context.Context, groupID, userID, operator string) error {
+	groupObjectID, err := primitive.ObjectIDFromHex(groupID)
+	if err != nil {
+		return domain.ErrGroupNotFound
+	}
+	userObjectID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return domain.ErrUserNotFound
+	}
+
+	filter, err := scopedFilter(ctx, bson.M{"_id": groupObjectID})
+	if err != nil {
+		return err
+	}
+
+	update := bson.M{
+		operator: bson.M{"member_ids": userObjectID},
+		"$set":   bson.M{"updated_at": time.Now()},
+	}
+
+	result, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return domain.ErrGroupNotFound
+	}
+
+	return nil
+}
+
+// find returns the groups matching filter, sorted by name
+func (r *MongoGroupRepository) find(ctx context.Context, filter bson.M) ([]*domain.Group, error) {
+	cursor, err := r.collection.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "name", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	groups := []*domain.Group{}
+	if err := cursor.All(ctx, &groups); err != nil {
+		return nil, err
+	}
+
+	return groups, nil
+}
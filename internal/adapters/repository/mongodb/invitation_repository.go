@@ -0,0 +1,172 @@
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"github.com/yourusername/userapi/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoInvitationRepository is a MongoDB implementation of InvitationRepository.
+// Accepted and revoked invitations are kept as a record of who invited whom.
+type MongoInvitationRepository struct {
+	collection *mongo.Collection
+}
+
+// NewMongoInvitationRepository creates a new MongoDB invitation repository
+func NewMongoInvitationRepository(db *mongo.Database) (*MongoInvitationRepository, error) {
+	collection := db.Collection("invitations")
+
+	indexModels := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "token_hash", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "tenant_id", Value: 1}, {Key: "email", Value: 1}},
+		},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := collection.Indexes().CreateMany(ctx, indexModels)
+	if err != nil {
+		return nil, err
+	}
+
+	return &MongoInvitationRepository{collection: collection}, nil
+}
+
+// Create stores a new invitation to the context's tenant
+func (r *MongoInvitationRepository) Create(ctx context.Context, invitation *domain.Invitation) error {
+	tenantID, err := scopedTenantID(ctx)
+	if err != nil {
+		return err
+	}
+
+	if invitation.ID.IsZero() {
+		invitation.ID = primitive.NewObjectID()
+	}
+	invitation.TenantID = tenantID
+
+	_, err = r.collection.InsertOne(ctx, invitation)
+	return err
+}
+
+// FindByID finds an invitation by ID
+func (r *MongoInvitationRepository) FindByID(ctx context.Context, id string) (*domain.Invitation, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, domain.ErrInvitationNotFound
+	}
+
+	filter, err := scopedFilter(ctx, bson.M{"_id": objectID})
+	if err != nil {
+		return nil, err
+	}
+
+	return r.findOne(ctx, filter, domain.ErrInvitationNotFound)
+}
+
+// FindPending returns the pending invitations of the context's tenant, newest first
+func (r *MongoInvitationRepository) FindPending(ctx context.Context) ([]*domain.Invitation, error) {
+	filter, err := scopedFilter(ctx, pendingFilter(bson.M{}))
+	if err != nil {
+		return nil, err
+	}
+
+	cursor, err := r.collection.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	invitations := []*domain.Invitation{}
+	if err := cursor.All(ctx, &invitations); err != nil {
+		return nil, err
+	}
+
+	return invitations, nil
+}
+
+// FindPendingByEmail returns the pending invitation for an email address
+func (r *MongoInvitationRepository) FindPendingByEmail(ctx context.Context, email string) (*domain.Invitation, error) {
+	filter, err := scopedFilter(ctx, pendingFilter(bson.M{"email": email}))
+	if err != nil {
+		return nil, err
+	}
+
+	return r.findOne(ctx, filter, domain.ErrInvitationNotFound)
+}
+
+// Renew replaces the token of a pending invitation
+func (r *MongoInvitationRepository) Renew(ctx context.Context, id, tokenHash string, expiresAt time.Time) error {
+	update := bson.M{"$set": bson.M{
+		"token_hash": tokenHash,
+		"expires_at": expiresAt,
+		"sent_at":    time.Now(),
+	}}
+
+	return r.updatePending(ctx, id, update, domain.ErrInvitationNotFound)
+}
+
+// MarkAccepted atomically consumes a pending invitation
+func (r *MongoInvitationRepository) MarkAccepted(ctx context.Context, id string, at time.Time) error {
+	return r.updatePending(ctx, id, bson.M{"$set": bson.M{"accepted_at": at}}, domain.ErrInvalidToken)
+}
+
+// Revoke withdraws a pending invitation
+func (r *MongoInvitationRepository) Revoke(ctx context.Context, id string, at time.Time) error {
+	return r.updatePending(ctx, id, bson.M{"$set": bson.M{"revoked_at": at}}, domain.ErrInvitationNotFound)
+}
+
+// updatePending applies update to a pending invitation of the context's
+// tenant, and returns notPending if there is none
+func (r *MongoInvitationRepository) updatePending(ctx context.Context, id string, update bson.M, notPending error) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return notPending
+	}
+
+	filter, err := scopedFilter(ctx, pendingFilter(bson.M{"_id": objectID}))
+	if err != nil {
+		return err
+	}
+
+	result, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+
+	if result.ModifiedCount == 0 {
+		return notPending
+	}
+
+	return nil
+}
+
+// findOne decodes the invitation matching filter, or returns notFound
+func (r *MongoInvitationRepository) findOne(ctx context.Context, filter bson.M, notFound error) (*domain.Invitation, error) {
+	var invitation domain.Invitation
+	err := r.collection.FindOne(ctx, filter).Decode(&invitation)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, notFound
+		}
+		return nil, err
+	}
+
+	return &invitation, nil
+}
+
+// pendingFilter restricts filter to invitations that were neither accepted nor revoked
+func pendingFilter(filter bson.M) bson.M {
+	filter["accepted_at"] = bson.M{"$exists": false}
+	filter["revoked_at"] = bson.M{"$exists": false}
+	return filter
+}
@@ -0,0 +1,89 @@
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"github.com/yourusername/userapi/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoLoginAttemptRepository is a MongoDB implementation of LoginAttemptRepository
+type MongoLoginAttemptRepository struct {
+	collection *mongo.Collection
+}
+
+// NewMongoLoginAttemptRepository creates a new MongoDB login attempt repository
+func NewMongoLoginAttemptRepository(db *mongo.Database) (*MongoLoginAttemptRepository, error) {
+	collection := db.Collection("login_attempts")
+
+	// Let MongoDB remove counters once they have expired
+	indexModel := mongo.IndexModel{
+		Keys:    bson.D{{Key: "expires_at", Value: 1}},
+		Options: options.Index().SetExpireAfterSeconds(0),
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := collection.Indexes().CreateOne(ctx, indexModel)
+	if err != nil {
+		return nil, err
+	}
+
+	return &MongoLoginAttemptRepository{collection: collection}, nil
+}
+
+// Get returns the failures recorded for key
+func (r *MongoLoginAttemptRepository) Get(ctx context.Context, key string) (*domain.LoginAttempts, error) {
+	var attempts domain.LoginAttempts
+	err := r.collection.FindOne(ctx, bson.M{
+		"_id":        key,
+		"expires_at": bson.M{"$gt": time.Now()},
+	}).Decode(&attempts)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return &domain.LoginAttempts{Key: key}, nil
+		}
+		return nil, err
+	}
+
+	return &attempts, nil
+}
+
+// RecordFailure adds a failure at now. The count restarts from one when the
+// stored counter has expired but the TTL monitor has not removed it yet.
+func (r *MongoLoginAttemptRepository) RecordFailure(ctx context.Context, key string, now time.Time, ttl time.Duration) (*domain.LoginAttempts, error) {
+	update := mongo.Pipeline{
+		{{Key: "$set", Value: bson.D{
+			{Key: "failures", Value: bson.D{{Key: "$cond", Value: bson.A{
+				bson.D{{Key: "$gt", Value: bson.A{"$expires_at", now}}},
+				bson.D{{Key: "$add", Value: bson.A{"$failures", 1}}},
+				1,
+			}}}},
+			{Key: "last_failure_at", Value: now},
+			{Key: "expires_at", Value: now.Add(ttl)},
+		}}},
+	}
+
+	var attempts domain.LoginAttempts
+	err := r.collection.FindOneAndUpdate(
+		ctx,
+		bson.M{"_id": key},
+		update,
+		options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After),
+	).Decode(&attempts)
+	if err != nil {
+		return nil, err
+	}
+
+	return &attempts, nil
+}
+
+// Reset forgets the failures recorded for key
+func (r *MongoLoginAttemptRepository) Reset(ctx context.Context, key string) error {
+	_, err := r.collection.DeleteOne(ctx, bson.M{"_id": key})
+	return err
+}
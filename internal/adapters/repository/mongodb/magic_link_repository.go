@@ -0,0 +1,93 @@
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"github.com/yourusername/userapi/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoMagicLinkRepository is a MongoDB implementation of MagicLinkRepository
+type MongoMagicLinkRepository struct {
+	collection *mongo.Collection
+}
+
+// NewMongoMagicLinkRepository creates a new MongoDB magic link repository
+func NewMongoMagicLinkRepository(db *mongo.Database) (*MongoMagicLinkRepository, error) {
+	collection := db.Collection("magic_link_tokens")
+
+	indexModels := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "token_hash", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			// Let MongoDB remove tokens once they have expired
+			Keys:    bson.D{{Key: "expires_at", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(0),
+		},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := collection.Indexes().CreateMany(ctx, indexModels)
+	if err != nil {
+		return nil, err
+	}
+
+	return &MongoMagicLinkRepository{collection: collection}, nil
+}
+
+// Create stores a new login link token
+func (r *MongoMagicLinkRepository) Create(ctx context.Context, token *domain.MagicLinkToken) error {
+	if token.ID.IsZero() {
+		token.ID = primitive.NewObjectID()
+	}
+
+	_, err := r.collection.InsertOne(ctx, token)
+	return err
+}
+
+// FindByHash finds a login link token by its hash
+func (r *MongoMagicLinkRepository) FindByHash(ctx context.Context, tokenHash string) (*domain.MagicLinkToken, error) {
+	var token domain.MagicLinkToken
+	err := r.collection.FindOne(ctx, bson.M{"token_hash": tokenHash}).Decode(&token)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, domain.ErrInvalidToken
+		}
+		return nil, err
+	}
+
+	return &token, nil
+}
+
+// MarkUsed atomically consumes an unused token
+func (r *MongoMagicLinkRepository) MarkUsed(ctx context.Context, id string) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	filter := bson.M{
+		"_id":     objectID,
+		"used_at": bson.M{"$exists": false},
+	}
+	update := bson.M{"$set": bson.M{"used_at": time.Now()}}
+
+	result, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+
+	if result.ModifiedCount == 0 {
+		return domain.ErrInvalidToken
+	}
+
+	return nil
+}
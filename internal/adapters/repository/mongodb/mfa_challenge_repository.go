@@ -0,0 +1,103 @@
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"github.com/yourusername/userapi/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoMFAChallengeRepository is a MongoDB implementation of MFAChallengeRepository
+type MongoMFAChallengeRepository struct {
+	collection *mongo.Collection
+}
+
+// NewMongoMFAChallengeRepository creates a new MongoDB MFA challenge repository
+func NewMongoMFAChallengeRepository(db *mongo.Database) (*MongoMFAChallengeRepository, error) {
+	collection := db.Collection("mfa_challenges")
+
+	indexModels := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "token_hash", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			// Let MongoDB remove challenges once they have expired
+			Keys:    bson.D{{Key: "expires_at", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(0),
+		},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := collection.Indexes().CreateMany(ctx, indexModels)
+	if err != nil {
+		return nil, err
+	}
+
+	return &MongoMFAChallengeRepository{collection: collection}, nil
+}
+
+// Create stores a new challenge
+func (r *MongoMFAChallengeRepository) Create(ctx context.Context, challenge *domain.MFAChallenge) error {
+	if challenge.ID.IsZero() {
+		challenge.ID = primitive.NewObjectID()
+	}
+
+	_, err := r.collection.InsertOne(ctx, challenge)
+	return err
+}
+
+// FindByHash finds a challenge by its hash
+func (r *MongoMFAChallengeRepository) FindByHash(ctx context.Context, tokenHash string) (*domain.MFAChallenge, error) {
+	var challenge domain.MFAChallenge
+	err := r.collection.FindOne(ctx, bson.M{"token_hash": tokenHash}).Decode(&challenge)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, domain.ErrInvalidToken
+		}
+		return nil, err
+	}
+
+	return &challenge, nil
+}
+
+// IncrementAttempts records a failed code and returns the new attempt count
+func (r *MongoMFAChallengeRepository) IncrementAttempts(ctx context.Context, id string) (int, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return 0, err
+	}
+
+	var challenge domain.MFAChallenge
+	err = r.collection.FindOneAndUpdate(
+		ctx,
+		bson.M{"_id": objectID},
+		bson.M{"$inc": bson.M{"attempts": 1}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&challenge)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return 0, domain.ErrInvalidToken
+		}
+		return 0, err
+	}
+
+	return challenge.Attempts, nil
+}
+
+// Delete removes a challenge
+func (r *MongoMFAChallengeRepository) Delete(ctx context.Context, id string) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.collection.DeleteOne(ctx, bson.M{"_id": objectID})
+	return err
+}
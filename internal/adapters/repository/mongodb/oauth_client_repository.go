@@ -0,0 +1,94 @@
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"github.com/yourusername/userapi/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoOAuthClientRepository is a MongoDB implementation of OAuthClientRepository.
+// Client IDs are unique across tenants, so FindByClientID is not scoped.
+type MongoOAuthClientRepository struct {
+	collection *mongo.Collection
+}
+
+// NewMongoOAuthClientRepository creates a new MongoDB OAuth client repository
+func NewMongoOAuthClientRepository(db *mongo.Database) (*MongoOAuthClientRepository, error) {
+	collection := db.Collection("oauth_clients")
+
+	indexModels := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "client_id", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "tenant_id", Value: 1}},
+		},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := collection.Indexes().CreateMany(ctx, indexModels)
+	if err != nil {
+		return nil, err
+	}
+
+	return &MongoOAuthClientRepository{collection: collection}, nil
+}
+
+// Create stores a new client in the context's tenant
+func (r *MongoOAuthClientRepository) Create(ctx context.Context, client *domain.OAuthClient) error {
+	tenantID, err := scopedTenantID(ctx)
+	if err != nil {
+		return err
+	}
+
+	if client.ID.IsZero() {
+		client.ID = primitive.NewObjectID()
+	}
+	client.TenantID = tenantID
+
+	_, err = r.collection.InsertOne(ctx, client)
+	return err
+}
+
+// FindByClientID finds a client by its public client ID
+func (r *MongoOAuthClientRepository) FindByClientID(ctx context.Context, clientID string) (*domain.OAuthClient, error) {
+	var client domain.OAuthClient
+	err := r.collection.FindOne(ctx, bson.M{"client_id": clientID}).Decode(&client)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, domain.ErrOAuthClientNotFound
+		}
+		return nil, err
+	}
+
+	return &client, nil
+}
+
+// FindAll returns every client of the context's tenant
+func (r *MongoOAuthClientRepository) FindAll(ctx context.Context) ([]*domain.OAuthClient, error) {
+	filter, err := scopedFilter(ctx, bson.M{})
+	if err != nil {
+		return nil, err
+	}
+
+	cursor, err := r.collection.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var clients []*domain.OAuthClient
+	if err := cursor.All(ctx, &clients); err != nil {
+		return nil, err
+	}
+
+	return clients, nil
+}
@@ -0,0 +1,74 @@
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"github.com/yourusername/userapi/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoOAuthConsentRepository is a MongoDB implementation of OAuthConsentRepository
+type MongoOAuthConsentRepository struct {
+	collection *mongo.Collection
+}
+
+// NewMongoOAuthConsentRepository creates a new MongoDB OAuth consent repository
+func NewMongoOAuthConsentRepository(db *mongo.Database) (*MongoOAuthConsentRepository, error) {
+	collection := db.Collection("oauth_consents")
+
+	indexModel := mongo.IndexModel{
+		Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "client_id", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := collection.Indexes().CreateOne(ctx, indexModel)
+	if err != nil {
+		return nil, err
+	}
+
+	return &MongoOAuthConsentRepository{collection: collection}, nil
+}
+
+// Find returns the consent of a user for a client
+func (r *MongoOAuthConsentRepository) Find(ctx context.Context, userID, clientID string) (*domain.OAuthConsent, error) {
+	objectID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var consent domain.OAuthConsent
+	err = r.collection.FindOne(ctx, bson.M{"user_id": objectID, "client_id": clientID}).Decode(&consent)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, domain.ErrConsentNotFound
+		}
+		return nil, err
+	}
+
+	return &consent, nil
+}
+
+// Save creates or replaces the consent of a user for a client
+func (r *MongoOAuthConsentRepository) Save(ctx context.Context, consent *domain.OAuthConsent) error {
+	update := bson.M{
+		"$set": bson.M{
+			"scopes":     consent.Scopes,
+			"granted_at": consent.GrantedAt,
+		},
+	}
+
+	_, err := r.collection.UpdateOne(
+		ctx,
+		bson.M{"user_id": consent.UserID, "client_id": consent.ClientID},
+		update,
+		options.Update().SetUpsert(true),
+	)
+	return err
+}
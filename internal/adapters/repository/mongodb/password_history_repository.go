@@ -0,0 +1,120 @@
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"github.com/yourusername/userapi/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoPasswordHistoryRepository is a MongoDB implementation of PasswordHistoryRepository
+type MongoPasswordHistoryRepository struct {
+	collection *mongo.Collection
+}
+
+// NewMongoPasswordHistoryRepository creates a new MongoDB password history repository
+func NewMongoPasswordHistoryRepository(db *mongo.Database) (*MongoPasswordHistoryRepository, error) {
+	collection := db.Collection("password_history")
+
+	indexModel := mongo.IndexModel{
+		Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "created_at", Value: -1}},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := collection.Indexes().CreateOne(ctx, indexModel)
+	if err != nil {
+		return nil, err
+	}
+
+	return &MongoPasswordHistoryRepository{collection: collection}, nil
+}
+
+// Add stores a new history entry
+func (r *MongoPasswordHistoryRepository) Add(ctx context.Context, entry *domain.PasswordHistoryEntry) error {
+	if entry.ID.IsZero() {
+		entry.ID = primitive.NewObjectID()
+	}
+
+	_, err := r.collection.InsertOne(ctx, entry)
+	return err
+}
+
+// FindRecent returns up to limit entries of a user, newest first
+func (r *MongoPasswordHistoryRepository) FindRecent(ctx context.Context, userID string, limit int) ([]*domain.PasswordHistoryEntry, error) {
+	objectID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}}).
+		SetLimit(int64(limit))
+
+	cursor, err := r.collection.Find(ctx, bson.M{"user_id": objectID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var entries []*domain.PasswordHistoryEntry
+	if err := cursor.All(ctx, &entries); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// Prune removes all but the newest keep entries of a user
+func (r *MongoPasswordHistoryRepository) Prune(ctx context.Context, userID string, keep int) error {
+	objectID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return err
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}}).
+		SetSkip(int64(keep)).
+		SetProjection(bson.M{"_id": 1})
+
+	cursor, err := r.collection.Find(ctx, bson.M{"user_id": objectID}, opts)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	var stale []struct {
+		ID primitive.ObjectID `bson:"_id"`
+	}
+	if err := cursor.All(ctx, &stale); err != nil {
+		return err
+	}
+
+	if len(stale) == 0 {
+		return nil
+	}
+
+	ids := make([]primitive.ObjectID, 0, len(stale))
+	for _, s := range stale {
+		ids = append(ids, s.ID)
+	}
+
+	_, err = r.collection.DeleteMany(ctx, bson.M{"_id": bson.M{"$in": ids}})
+	return err
+}
+
+// DeleteByUserID removes every entry of a user
+func (r *MongoPasswordHistoryRepository) DeleteByUserID(ctx context.Context, userID string) error {
+	objectID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.collection.DeleteMany(ctx, bson.M{"user_id": objectID})
+	return err
+}
@@ -0,0 +1,107 @@
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"github.com/yourusername/userapi/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoPasswordResetRepository is a MongoDB implementation of PasswordResetRepository
+type MongoPasswordResetRepository struct {
+	collection *mongo.Collection
+}
+
+// NewMongoPasswordResetRepository creates a new MongoDB password reset repository
+func NewMongoPasswordResetRepository(db *mongo.Database) (*MongoPasswordResetRepository, error) {
+	collection := db.Collection("password_reset_tokens")
+
+	indexModels := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "token_hash", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "user_id", Value: 1}},
+		},
+		{
+			// Let MongoDB remove tokens once they have expired
+			Keys:    bson.D{{Key: "expires_at", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(0),
+		},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := collection.Indexes().CreateMany(ctx, indexModels)
+	if err != nil {
+		return nil, err
+	}
+
+	return &MongoPasswordResetRepository{collection: collection}, nil
+}
+
+// Create stores a new reset token
+func (r *MongoPasswordResetRepository) Create(ctx context.Context, token *domain.PasswordResetToken) error {
+	if token.ID.IsZero() {
+		token.ID = primitive.NewObjectID()
+	}
+
+	_, err := r.collection.InsertOne(ctx, token)
+	return err
+}
+
+// FindByHash finds a reset token by its hash
+func (r *MongoPasswordResetRepository) FindByHash(ctx context.Context, tokenHash string) (*domain.PasswordResetToken, error) {
+	var token domain.PasswordResetToken
+	err := r.collection.FindOne(ctx, bson.M{"token_hash": tokenHash}).Decode(&token)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, domain.ErrInvalidToken
+		}
+		return nil, err
+	}
+
+	return &token, nil
+}
+
+// MarkUsed atomically consumes an unused token
+func (r *MongoPasswordResetRepository) MarkUsed(ctx context.Context, id string) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	filter := bson.M{
+		"_id":     objectID,
+		"used_at": bson.M{"$exists": false},
+	}
+	update := bson.M{"$set": bson.M{"used_at": time.Now()}}
+
+	result, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+
+	if result.ModifiedCount == 0 {
+		return domain.ErrInvalidToken
+	}
+
+	return nil
+}
+
+// DeleteByUserID removes every outstanding token of a user
+func (r *MongoPasswordResetRepository) DeleteByUserID(ctx context.Context, userID string) error {
+	objectID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.collection.DeleteMany(ctx, bson.M{"user_id": objectID})
+	return err
+}
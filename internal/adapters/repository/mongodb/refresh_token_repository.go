@@ -0,0 +1,130 @@
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"github.com/yourusername/userapi/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoRefreshTokenRepository is a MongoDB implementation of RefreshTokenRepository
+type MongoRefreshTokenRepository struct {
+	collection *mongo.Collection
+}
+
+// NewMongoRefreshTokenRepository creates a new MongoDB refresh token repository
+func NewMongoRefreshTokenRepository(db *mongo.Database) (*MongoRefreshTokenRepository, error) {
+	collection := db.Collection("refresh_tokens")
+
+	indexModels := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "token_hash", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "family_id", Value: 1}},
+		},
+		{
+			Keys: bson.D{{Key: "user_id", Value: 1}},
+		},
+		{
+			// Let MongoDB remove tokens once they have expired
+			Keys:    bson.D{{Key: "expires_at", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(0),
+		},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := collection.Indexes().CreateMany(ctx, indexModels)
+	if err != nil {
+		return nil, err
+	}
+
+	return &MongoRefreshTokenRepository{collection: collection}, nil
+}
+
+// Create stores a new refresh token
+func (r *MongoRefreshTokenRepository) Create(ctx context.Context, token *domain.RefreshToken) error {
+	if token.ID.IsZero() {
+		token.ID = primitive.NewObjectID()
+	}
+
+	_, err := r.collection.InsertOne(ctx, token)
+	return err
+}
+
+// FindByHash finds a refresh token by its hash
+func (r *MongoRefreshTokenRepository) FindByHash(ctx context.Context, tokenHash string) (*domain.RefreshToken, error) {
+	var token domain.RefreshToken
+	err := r.collection.FindOne(ctx, bson.M{"token_hash": tokenHash}).Decode(&token)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, domain.ErrRefreshTokenNotFound
+		}
+		return nil, err
+	}
+
+	return &token, nil
+}
+
+// MarkRotated atomically flags an active token as rotated
+func (r *MongoRefreshTokenRepository) MarkRotated(ctx context.Context, id string) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	filter := bson.M{
+		"_id":        objectID,
+		"rotated_at": bson.M{"$exists": false},
+		"revoked_at": bson.M{"$exists": false},
+	}
+	update := bson.M{"$set": bson.M{"rotated_at": time.Now()}}
+
+	result, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+
+	// Another request got there first
+	if result.ModifiedCount == 0 {
+		return domain.ErrRefreshTokenReused
+	}
+
+	return nil
+}
+
+// RevokeFamily revokes every token that shares the given family
+func (r *MongoRefreshTokenRepository) RevokeFamily(ctx context.Context, familyID string) error {
+	filter := bson.M{
+		"family_id":  familyID,
+		"revoked_at": bson.M{"$exists": false},
+	}
+	update := bson.M{"$set": bson.M{"revoked_at": time.Now()}}
+
+	_, err := r.collection.UpdateMany(ctx, filter, update)
+	return err
+}
+
+// RevokeAllForUser revokes every refresh token belonging to a user
+func (r *MongoRefreshTokenRepository) RevokeAllForUser(ctx context.Context, userID string) error {
+	objectID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return err
+	}
+
+	filter := bson.M{
+		"user_id":    objectID,
+		"revoked_at": bson.M{"$exists": false},
+	}
+	update := bson.M{"$set": bson.M{"revoked_at": time.Now()}}
+
+	_, err = r.collection.UpdateMany(ctx, filter, update)
+	return err
+}
@@ -0,0 +1,137 @@
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"github.com/yourusername/userapi/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoSigningKeyRepository is a MongoDB implementation of SigningKeyRepository
+type MongoSigningKeyRepository struct {
+	collection *mongo.Collection
+}
+
+// NewMongoSigningKeyRepository creates a new MongoDB signing key repository
+func NewMongoSigningKeyRepository(db *mongo.Database) (*MongoSigningKeyRepository, error) {
+	collection := db.Collection("signing_keys")
+
+	indexModel := mongo.IndexModel{
+		Keys: bson.D{{Key: "status", Value: 1}, {Key: "created_at", Value: 1}},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := collection.Indexes().CreateOne(ctx, indexModel)
+	if err != nil {
+		return nil, err
+	}
+
+	return &MongoSigningKeyRepository{collection: collection}, nil
+}
+
+// Create stores a new signing key
+func (r *MongoSigningKeyRepository) Create(ctx context.Context, key *domain.SigningKey) error {
+	_, err := r.collection.InsertOne(ctx, key)
+	return err
+}
+
+// FindByID finds a signing key by its key ID
+func (r *MongoSigningKeyRepository) FindByID(ctx context.Context, id string) (*domain.SigningKey, error) {
+	var key domain.SigningKey
+	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&key)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, domain.ErrSigningKeyNotFound
+		}
+		return nil, err
+	}
+
+	return &key, nil
+}
+
+// FindUnretired retrieves every key that may still sign or verify tokens
+func (r *MongoSigningKeyRepository) FindUnretired(ctx context.Context) ([]*domain.SigningKey, error) {
+	filter := bson.M{"status": bson.M{"$ne": domain.SigningKeyRetired}}
+	cursor, err := r.collection.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var keys []*domain.SigningKey
+	if err := cursor.All(ctx, &keys); err != nil {
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+// Update updates a signing key
+func (r *MongoSigningKeyRepository) Update(ctx context.Context, key *domain.SigningKey) error {
+	result, err := r.collection.ReplaceOne(ctx, bson.M{"_id": key.ID}, key)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return domain.ErrSigningKeyNotFound
+	}
+
+	return nil
+}
+
+// MongoKeyRotationEventRepository is a MongoDB implementation of KeyRotationEventRepository
+type MongoKeyRotationEventRepository struct {
+	collection *mongo.Collection
+}
+
+// NewMongoKeyRotationEventRepository creates a new MongoDB key rotation audit repository
+func NewMongoKeyRotationEventRepository(db *mongo.Database) (*MongoKeyRotationEventRepository, error) {
+	collection := db.Collection("key_rotation_events")
+
+	indexModel := mongo.IndexModel{
+		Keys: bson.D{{Key: "occurred_at", Value: 1}},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := collection.Indexes().CreateOne(ctx, indexModel)
+	if err != nil {
+		return nil, err
+	}
+
+	return &MongoKeyRotationEventRepository{collection: collection}, nil
+}
+
+// Create records a key rotation event
+func (r *MongoKeyRotationEventRepository) Create(ctx context.Context, event *domain.KeyRotationEvent) error {
+	if event.ID.IsZero() {
+		event.ID = primitive.NewObjectID()
+	}
+
+	_, err := r.collection.InsertOne(ctx, event)
+	return err
+}
+
+// FindAll retrieves the audit log, oldest first
+func (r *MongoKeyRotationEventRepository) FindAll(ctx context.Context) ([]*domain.KeyRotationEvent, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{}, options.Find().SetSort(bson.D{{Key: "occurred_at", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var events []*domain.KeyRotationEvent
+	if err := cursor.All(ctx, &events); err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
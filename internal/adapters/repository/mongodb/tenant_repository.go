@@ -0,0 +1,99 @@
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"github.com/yourusername/userapi/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoTenantRepository is a MongoDB implementation of TenantRepository
+type MongoTenantRepository struct {
+	collection *mongo.Collection
+}
+
+// NewMongoTenantRepository creates a new MongoDB tenant repository
+func NewMongoTenantRepository(db *mongo.Database) (*MongoTenantRepository, error) {
+	collection := db.Collection("tenants")
+
+	indexModel := mongo.IndexModel{
+		Keys:    bson.D{{Key: "slug", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := collection.Indexes().CreateOne(ctx, indexModel)
+	if err != nil {
+		return nil, err
+	}
+
+	return &MongoTenantRepository{collection: collection}, nil
+}
+
+// Create stores a new tenant
+func (r *MongoTenantRepository) Create(ctx context.Context, tenant *domain.Tenant) error {
+	if tenant.ID.IsZero() {
+		tenant.ID = primitive.NewObjectID()
+	}
+
+	_, err := r.collection.InsertOne(ctx, tenant)
+	if mongo.IsDuplicateKeyError(err) {
+		return domain.ErrTenantAlreadyExists
+	}
+	return err
+}
+
+// FindByID finds a tenant by ID
+func (r *MongoTenantRepository) FindByID(ctx context.Context, id string) (*domain.Tenant, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, domain.ErrTenantNotFound
+	}
+
+	var tenant domain.Tenant
+	err = r.collection.FindOne(ctx, bson.M{"_id": objectID}).Decode(&tenant)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, domain.ErrTenantNotFound
+		}
+		return nil, err
+	}
+
+	return &tenant, nil
+}
+
+// FindBySlug finds a tenant by the slug requests name it with
+func (r *MongoTenantRepository) FindBySlug(ctx context.Context, slug string) (*domain.Tenant, error) {
+	var tenant domain.Tenant
+	err := r.collection.FindOne(ctx, bson.M{"slug": slug}).Decode(&tenant)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, domain.ErrTenantNotFound
+		}
+		return nil, err
+	}
+
+	return &tenant, nil
+}
+
+// FindAll returns every tenant
+func (r *MongoTenantRepository) FindAll(ctx context.Context) ([]*domain.Tenant, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{}, options.Find().SetSort(bson.D{{Key: "slug", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var tenants []*domain.Tenant
+	if err := cursor.All(ctx, &tenants); err != nil {
+		return nil, err
+	}
+
+	return tenants, nil
+}
@@ -0,0 +1,101 @@
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoTokenRevocationRepository is a MongoDB implementation of TokenRevocationRepository
+type MongoTokenRevocationRepository struct {
+	revokedTokens *mongo.Collection
+	watermarks    *mongo.Collection
+}
+
+// revokedToken is a denylisted token ID
+type revokedToken struct {
+	TokenID   string    `bson:"_id"`
+	ExpiresAt time.Time `bson:"expires_at"`
+}
+
+// revocationWatermark holds the per-user "tokens issued before" time
+type revocationWatermark struct {
+	UserID        string    `bson:"_id"`
+	RevokedBefore time.Time `bson:"revoked_before"`
+}
+
+// NewMongoTokenRevocationRepository creates a new MongoDB token revocation repository
+func NewMongoTokenRevocationRepository(db *mongo.Database) (*MongoTokenRevocationRepository, error) {
+	revokedTokens := db.Collection("revoked_tokens")
+
+	// Denylist entries are useless once the token would have expired anyway
+	indexModel := mongo.IndexModel{
+		Keys:    bson.D{{Key: "expires_at", Value: 1}},
+		Options: options.Index().SetExpireAfterSeconds(0),
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := revokedTokens.Indexes().CreateOne(ctx, indexModel)
+	if err != nil {
+		return nil, err
+	}
+
+	return &MongoTokenRevocationRepository{
+		revokedTokens: revokedTokens,
+		watermarks:    db.Collection("token_watermarks"),
+	}, nil
+}
+
+// Revoke denylists a token ID until it expires
+func (r *MongoTokenRevocationRepository) Revoke(ctx context.Context, tokenID string, expiresAt time.Time) error {
+	_, err := r.revokedTokens.ReplaceOne(
+		ctx,
+		bson.M{"_id": tokenID},
+		revokedToken{TokenID: tokenID, ExpiresAt: expiresAt},
+		options.Replace().SetUpsert(true),
+	)
+	return err
+}
+
+// IsRevoked reports whether a token ID has been denylisted
+func (r *MongoTokenRevocationRepository) IsRevoked(ctx context.Context, tokenID string) (bool, error) {
+	count, err := r.revokedTokens.CountDocuments(ctx, bson.M{
+		"_id":        tokenID,
+		"expires_at": bson.M{"$gt": time.Now()},
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+// RevokeAllBefore sets the user's watermark
+func (r *MongoTokenRevocationRepository) RevokeAllBefore(ctx context.Context, userID string, before time.Time) error {
+	_, err := r.watermarks.ReplaceOne(
+		ctx,
+		bson.M{"_id": userID},
+		revocationWatermark{UserID: userID, RevokedBefore: before},
+		options.Replace().SetUpsert(true),
+	)
+	return err
+}
+
+// RevokedBefore returns the user's watermark, or the zero time if none is set
+func (r *MongoTokenRevocationRepository) RevokedBefore(ctx context.Context, userID string) (time.Time, error) {
+	var watermark revocationWatermark
+	err := r.watermarks.FindOne(ctx, bson.M{"_id": userID}).Decode(&watermark)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return time.Time{}, nil
+		}
+		return time.Time{}, err
+	}
+
+	return watermark.RevokedBefore, nil
+}
@@ -2,48 +2,75 @@ package mongodb
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"github.com/yourusername/userapi/internal/domain"
+	"github.com/yourusername/userapi/internal/ports/repository"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
-// MongoUserRepository is a MongoDB implementation of UserRepository
+// MongoUserRepository is a MongoDB implementation of UserRepository.
+// Every query is filtered by the tenant the context is scoped to.
 type MongoUserRepository struct {
 	collection *mongo.Collection
 }
 
 // NewMongoUserRepository creates a new MongoDB user repository
-func NewMongoUserRepository(db *mongo.Database) *MongoUserRepository {
+func NewMongoUserRepository(db *mongo.Database) (*MongoUserRepository, error) {
 	collection := db.Collection("users")
 	
-	// Create unique index for email field
-	indexModel := mongo.IndexModel{
-		Keys:    bson.D{{Key: "email", Value: 1}},
-		Options: options.Index().SetUnique(true),
+	indexModels := []mongo.IndexModel{
+		{
+			// Email addresses are unique among the users of a tenant that are
+			// not deleted. Deleted users differ by when they were deleted.
+			Keys:    bson.D{{Key: "tenant_id", Value: 1}, {Key: "email", Value: 1}, {Key: "deleted_at", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "deleted_at", Value: 1}},
+			Options: options.Index().SetSparse(true),
+		},
 	}
 	
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 	
-	_, err := collection.Indexes().CreateOne(ctx, indexModel)
+	// Older email indexes would reject an address that is used in another
+	// tenant, or by a deleted user
+	for _, name := range []string{"email_1", "tenant_id_1_email_1"} {
+		if _, err := collection.Indexes().DropOne(ctx, name); err != nil && !isIndexNotFound(err) {
+			return nil, err
+		}
+	}
+	
+	_, err := collection.Indexes().CreateMany(ctx, indexModels)
 	if err != nil {
-		panic(err) // In real app, handle this better
+		return nil, err
 	}
 	
-	return &MongoUserRepository{collection: collection}
+	return &MongoUserRepository{collection: collection}, nil
 }
 
-// Create adds a new user to the database
+// Create adds a new user to the context's tenant
 func (r *MongoUserRepository) Create(ctx context.Context, user *domain.User) error {
+	tenantID, err := scopedTenantID(ctx)
+	if err != nil {
+		return err
+	}
+	
 	if user.ID.IsZero() {
 		user.ID = primitive.NewObjectID()
 	}
+	user.TenantID = tenantID
 	
-	_, err := r.collection.InsertOne(ctx, user)
+	_, err = r.collection.InsertOne(ctx, user)
+	if mongo.IsDuplicateKeyError(err) {
+		return domain.ErrEmailAlreadyExists
+	}
 	return err
 }
 
@@ -54,8 +81,16 @@ func (r *MongoUserRepository) FindByID(ctx context.Context, id string) (*domain.
 		return nil, err
 	}
 	
+	// IDs of records the service issued itself may point into any tenant
+	filter := withoutDeleted(ctx, bson.M{"_id": objectID})
+	if !repository.IsAcrossTenants(ctx) {
+		if filter, err = scopedFilter(ctx, filter); err != nil {
+			return nil, err
+		}
+	}
+	
 	var user domain.User
-	err = r.collection.FindOne(ctx, bson.M{"_id": objectID}).Decode(&user)
+	err = r.collection.FindOne(ctx, filter).Decode(&user)
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
 			return nil, domain.ErrUserNotFound
@@ -68,8 +103,13 @@ func (r *MongoUserRepository) FindByID(ctx context.Context, id string) (*domain.
 
 // FindByEmail finds a user by email
 func (r *MongoUserRepository) FindByEmail(ctx context.Context, email string) (*domain.User, error) {
+	filter, err := scopedFilter(ctx, withoutDeleted(ctx, bson.M{"email": email}))
+	if err != nil {
+		return nil, err
+	}
+	
 	var user domain.User
-	err := r.collection.FindOne(ctx, bson.M{"email": email}).Decode(&user)
+	err = r.collection.FindOne(ctx, filter).Decode(&user)
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
 			return nil, domain.ErrUserNotFound
@@ -80,9 +120,14 @@ func (r *MongoUserRepository) FindByEmail(ctx context.Context, email string) (*d
 	return &user, nil
 }
 
-// FindAll retrieves all users
+// FindAll retrieves all users of the context's tenant
 func (r *MongoUserRepository) FindAll(ctx context.Context) ([]*domain.User, error) {
-	cursor, err := r.collection.Find(ctx, bson.M{})
+	filter, err := scopedFilter(ctx, withoutDeleted(ctx, bson.M{}))
+	if err != nil {
+		return nil, err
+	}
+	
+	cursor, err := r.collection.Find(ctx, filter)
 	if err != nil {
 		return nil, err
 	}
@@ -98,18 +143,100 @@ func (r *MongoUserRepository) FindAll(ctx context.Context) ([]*domain.User, erro
 
 // Update updates a user
 func (r *MongoUserRepository) Update(ctx context.Context, user *domain.User) error {
-	_, err := r.collection.ReplaceOne(ctx, bson.M{"_id": user.ID}, user)
-	return err
+	tenantID, err := scopedTenantID(ctx)
+	if err != nil {
+		return err
+	}
+	
+	// Users never move between tenants
+	if user.TenantID != tenantID {
+		return domain.ErrUserNotFound
+	}
+	
+	// A user deleted since they were read must stay deleted
+	filter := bson.M{"_id": user.ID, "tenant_id": tenantID, "deleted_at": bson.M{"$exists": false}}
+	
+	result, err := r.collection.ReplaceOne(ctx, filter, user)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return domain.ErrEmailAlreadyExists
+		}
+		return err
+	}
+	
+	if result.MatchedCount == 0 {
+		return domain.ErrUserNotFound
+	}
+	
+	return nil
 }
 
-// Delete removes a user
+// Delete marks a user as deleted
 func (r *MongoUserRepository) Delete(ctx context.Context, id string) error {
+	return r.setDeleted(ctx, id, false, bson.M{"$set": bson.M{"deleted_at": time.Now()}})
+}
+	
+// Restore undoes Delete
+func (r *MongoUserRepository) Restore(ctx context.Context, id string) error {
+	return r.setDeleted(ctx, id, true, bson.M{"$unset": bson.M{"deleted_at": ""}})
+}
+	
+// setDeleted applies update to a user that is deleted or not as given
+func (r *MongoUserRepository) setDeleted(ctx context.Context, id string, deleted bool, update bson.M) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return domain.ErrUserNotFound
+	}
+	
+	filter, err := scopedFilter(ctx, bson.M{"_id": objectID, "deleted_at": bson.M{"$exists": deleted}})
+	if err != nil {
+		return err
+	}
+	
+	result, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return domain.ErrEmailAlreadyExists
+		}
+		return err
+	}
+	
+	if result.MatchedCount == 0 {
+		return domain.ErrUserNotFound
+	}
+	
+	return nil
+}
+	
+// FindDeletedBefore returns the users of every tenant deleted before the given time
+func (r *MongoUserRepository) FindDeletedBefore(ctx context.Context, before time.Time) ([]*domain.User, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{"deleted_at": bson.M{"$lt": before}})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+	
+	var users []*domain.User
+	if err := cursor.All(ctx, &users); err != nil {
+		return nil, err
+	}
+	
+	return users, nil
+}
+	
+// Purge removes a deleted user
+func (r *MongoUserRepository) Purge(ctx context.Context, id string) error {
 	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return domain.ErrUserNotFound
+	}
+	
+	filter, err := scopedFilter(ctx, bson.M{"_id": objectID, "deleted_at": bson.M{"$exists": true}})
 	if err != nil {
 		return err
 	}
 	
-	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": objectID})
+	result, err := r.collection.DeleteOne(ctx, filter)
 	if err != nil {
 		return err
 	}
@@ -121,7 +248,54 @@ func (r *MongoUserRepository) Delete(ctx context.Context, id string) error {
 	return nil
 }
 
-// Count returns the total number of users
+// Count returns the number of users in the context's tenant
 func (r *MongoUserRepository) Count(ctx context.Context) (int64, error) {
-	return r.collection.CountDocuments(ctx, bson.M{})
+	filter, err := scopedFilter(ctx, withoutDeleted(ctx, bson.M{}))
+	if err != nil {
+		return 0, err
+	}
+	
+	return r.collection.CountDocuments(ctx, filter)
+}
+
+// scopedTenantID returns the tenant the context is scoped to
+func scopedTenantID(ctx context.Context) (primitive.ObjectID, error) {
+	tenantID, ok := repository.TenantFromContext(ctx)
+	if !ok {
+		return primitive.NilObjectID, domain.ErrTenantRequired
+	}
+	
+	objectID, err := primitive.ObjectIDFromHex(tenantID)
+	if err != nil {
+		return primitive.NilObjectID, domain.ErrTenantNotFound
+	}
+	
+	return objectID, nil
+}
+
+// scopedFilter restricts filter to the context's tenant. Without a tenant it
+// fails instead of matching the users of every tenant.
+func scopedFilter(ctx context.Context, filter bson.M) (bson.M, error) {
+	tenantID, err := scopedTenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	
+	filter["tenant_id"] = tenantID
+	return filter, nil
+}
+
+// withoutDeleted hides deleted users from filter unless the context includes them
+func withoutDeleted(ctx context.Context, filter bson.M) bson.M {
+	if !repository.IncludesDeleted(ctx) {
+		filter["deleted_at"] = bson.M{"$exists": false}
+	}
+	return filter
+}
+
+// isIndexNotFound reports whether dropping an index failed only because
+// the index or its collection does not exist
+func isIndexNotFound(err error) bool {
+	var cmdErr mongo.CommandError
+	return errors.As(err, &cmdErr) && (cmdErr.Code == 26 || cmdErr.Code == 27) // NamespaceNotFound, IndexNotFound
 }
@@ -0,0 +1,74 @@
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"github.com/yourusername/userapi/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoUserStatusChangeRepository is a MongoDB implementation of UserStatusChangeRepository
+type MongoUserStatusChangeRepository struct {
+	collection *mongo.Collection
+}
+
+// NewMongoUserStatusChangeRepository creates a new MongoDB user status audit repository
+func NewMongoUserStatusChangeRepository(db *mongo.Database) (*MongoUserStatusChangeRepository, error) {
+	collection := db.Collection("user_status_changes")
+
+	indexModels := []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "occurred_at", Value: 1}},
+		},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := collection.Indexes().CreateMany(ctx, indexModels)
+	if err != nil {
+		return nil, err
+	}
+
+	return &MongoUserStatusChangeRepository{collection: collection}, nil
+}
+
+// Create records a status change
+func (r *MongoUserStatusChangeRepository) Create(ctx context.Context, change *domain.UserStatusChange) error {
+	if change.ID.IsZero() {
+		change.ID = primitive.NewObjectID()
+	}
+
+	_, err := r.collection.InsertOne(ctx, change)
+	return err
+}
+
+// FindByUserID retrieves a user's status changes, oldest first
+func (r *MongoUserStatusChangeRepository) FindByUserID(ctx context.Context, userID string) ([]*domain.UserStatusChange, error) {
+	objectID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, domain.ErrUserNotFound
+	}
+
+	filter, err := scopedFilter(ctx, bson.M{"user_id": objectID})
+	if err != nil {
+		return nil, err
+	}
+
+	cursor, err := r.collection.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "occurred_at", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	changes := []*domain.UserStatusChange{}
+	if err := cursor.All(ctx, &changes); err != nil {
+		return nil, err
+	}
+
+	return changes, nil
+}
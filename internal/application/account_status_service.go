@@ -0,0 +1,99 @@
+package application
+
+import (
+	"context"
+	"time"
+
+	"github.com/yourusername/userapi/internal/domain"
+	"github.com/yourusername/userapi/internal/ports/repository"
+)
+
+// AccountStatusService lets administrators block and unblock users. Every
+// transition is recorded with who made it and why.
+type AccountStatusService struct {
+	userRepo    repository.UserRepository
+	changeRepo  repository.UserStatusChangeRepository
+	authService *AuthService
+}
+
+// NewAccountStatusService creates a new account status service
+func NewAccountStatusService(userRepo repository.UserRepository, changeRepo repository.UserStatusChangeRepository, authService *AuthService) *AccountStatusService {
+	return &AccountStatusService{
+		userRepo:    userRepo,
+		changeRepo:  changeRepo,
+		authService: authService,
+	}
+}
+
+// SuspendUser blocks a user until an administrator reactivates them
+func (s *AccountStatusService) SuspendUser(ctx context.Context, id, reason string) (*domain.User, error) {
+	return s.changeStatus(ctx, id, reason, (*domain.User).Suspend)
+}
+
+// LockUser blocks a user whose account may be compromised
+func (s *AccountStatusService) LockUser(ctx context.Context, id, reason string) (*domain.User, error) {
+	return s.changeStatus(ctx, id, reason, (*domain.User).Lock)
+}
+
+// DeactivateUser blocks a user whose account is no longer used
+func (s *AccountStatusService) DeactivateUser(ctx context.Context, id, reason string) (*domain.User, error) {
+	return s.changeStatus(ctx, id, reason, (*domain.User).Deactivate)
+}
+
+// ReactivateUser lets a blocked user sign in again
+func (s *AccountStatusService) ReactivateUser(ctx context.Context, id, reason string) (*domain.User, error) {
+	return s.changeStatus(ctx, id, reason, (*domain.User).Reactivate)
+}
+
+// ListStatusChanges returns a user's status changes, oldest first
+func (s *AccountStatusService) ListStatusChanges(ctx context.Context, id string) ([]*domain.UserStatusChange, error) {
+	if err := Authorize(ctx, ActionChangeStatus, ""); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.userRepo.FindByID(ctx, id); err != nil {
+		return nil, err
+	}
+
+	return s.changeRepo.FindByUserID(ctx, id)
+}
+
+// changeStatus applies transition to a user and records it. A user who is
+// no longer active loses every session, so their tokens stop working at once.
+func (s *AccountStatusService) changeStatus(ctx context.Context, id, reason string, transition func(*domain.User, time.Time) error) (*domain.User, error) {
+	if err := Authorize(ctx, ActionChangeStatus, ""); err != nil {
+		return nil, err
+	}
+
+	// Administrators cannot block themselves, so the last one cannot lock everyone out
+	principal, _ := PrincipalFromContext(ctx)
+	if principal.UserID == id {
+		return nil, domain.ErrForbidden
+	}
+
+	user, err := s.userRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	from := user.Status
+	if err := transition(user, time.Now()); err != nil {
+		return nil, err
+	}
+
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return nil, err
+	}
+
+	if err := s.changeRepo.Create(ctx, domain.NewUserStatusChange(user, from, reason, principal.UserID)); err != nil {
+		return nil, err
+	}
+
+	if !user.IsActive() {
+		if err := s.authService.revokeSessions(ctx, id); err != nil {
+			return nil, err
+		}
+	}
+
+	return user, nil
+}
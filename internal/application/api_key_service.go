@@ -0,0 +1,156 @@
+package application
+
+import (
+	"context"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/yourusername/userapi/internal/domain"
+	"github.com/yourusername/userapi/internal/ports/repository"
+	"github.com/yourusername/userapi/pkg/auth"
+)
+
+// lastUsedResolution limits how often a key's last-used time is written
+const lastUsedResolution = time.Minute
+
+// APIKeyService manages personal API keys and authenticates requests made with them
+type APIKeyService struct {
+	apiKeyRepo  repository.APIKeyRepository
+	userRepo    repository.UserRepository
+	authService *AuthService
+}
+
+// NewAPIKeyService creates a new API key service. authService decides which
+// scopes a key's owner currently has.
+func NewAPIKeyService(apiKeyRepo repository.APIKeyRepository, userRepo repository.UserRepository, authService *AuthService) *APIKeyService {
+	return &APIKeyService{
+		apiKeyRepo:  apiKeyRepo,
+		userRepo:    userRepo,
+		authService: authService,
+	}
+}
+
+// Create creates a key for the user and returns it together with the plain
+// key, which is not stored and cannot be shown again
+func (s *APIKeyService) Create(ctx context.Context, userID, name string, scopes []string, expiresAt *time.Time) (*domain.APIKey, string, error) {
+	if err := Authorize(ctx, ActionManageAPIKeys, userID); err != nil {
+		return nil, "", err
+	}
+
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	for _, scope := range scopes {
+		if !containsScope(DefaultScopes, scope) {
+			return nil, "", domain.ErrInvalidScope
+		}
+	}
+
+	plain, prefix, err := auth.GenerateAPIKey()
+	if err != nil {
+		return nil, "", err
+	}
+
+	key := &domain.APIKey{
+		UserID:    user.ID,
+		Name:      name,
+		Prefix:    prefix,
+		KeyHash:   auth.HashOpaqueToken(plain),
+		Scopes:    scopes,
+		ExpiresAt: expiresAt,
+		CreatedAt: time.Now(),
+	}
+	if err := s.apiKeyRepo.Create(ctx, key); err != nil {
+		return nil, "", err
+	}
+
+	return key, plain, nil
+}
+
+// List returns every key of the user, including revoked and expired ones
+func (s *APIKeyService) List(ctx context.Context, userID string) ([]*domain.APIKey, error) {
+	if err := Authorize(ctx, ActionManageAPIKeys, userID); err != nil {
+		return nil, err
+	}
+
+	return s.apiKeyRepo.FindByUserID(ctx, userID)
+}
+
+// Revoke revokes one of the user's keys
+func (s *APIKeyService) Revoke(ctx context.Context, userID, keyID string) error {
+	if err := Authorize(ctx, ActionManageAPIKeys, userID); err != nil {
+		return err
+	}
+
+	return s.apiKeyRepo.Revoke(ctx, userID, keyID)
+}
+
+// Authenticate checks a plain API key and returns the claims a JWT for its
+// owner would carry, limited to the key's scopes
+func (s *APIKeyService) Authenticate(ctx context.Context, plain string) (*auth.Claims, error) {
+	if !strings.HasPrefix(plain, auth.APIKeyPrefix) {
+		return nil, domain.ErrInvalidToken
+	}
+
+	key, err := s.apiKeyRepo.FindByHash(ctx, auth.HashOpaqueToken(plain))
+	if err != nil {
+		if err == domain.ErrAPIKeyNotFound {
+			return nil, domain.ErrInvalidToken
+		}
+		return nil, err
+	}
+
+	now := time.Now()
+	if !key.IsUsable(now) {
+		return nil, domain.ErrInvalidToken
+	}
+
+	_, user, err := findIssuedUser(ctx, s.userRepo, key.UserID.Hex())
+	if err != nil {
+		if err == domain.ErrUserNotFound {
+			return nil, domain.ErrInvalidToken
+		}
+		return nil, err
+	}
+
+	if !user.IsActive() {
+		return nil, domain.ErrInvalidToken
+	}
+
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) >= lastUsedResolution {
+		if err := s.apiKeyRepo.TouchLastUsed(ctx, key.ID.Hex(), now); err != nil {
+			log.Printf("Failed to record API key use: %v", err)
+		}
+	}
+
+	// A key never grants more than its owner currently has
+	var requested []string
+	if len(key.Scopes) > 0 {
+		requested = key.Scopes
+	}
+	granted := s.authService.grantedScopes(user, requested)
+
+	claims := &auth.Claims{
+		UserID:   user.ID.Hex(),
+		TenantID: user.TenantID.Hex(),
+		Email:    user.Email,
+		Role:     string(user.Role.RoleOrDefault()),
+		Scope:    strings.Join(granted, " "),
+	}
+	claims.Subject = claims.UserID
+
+	return claims, nil
+}
+
+// containsScope reports whether scope is in scopes
+func containsScope(scopes []string, scope string) bool {
+	for _, s := range scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
@@ -2,54 +2,464 @@ package application
 
 import (
 	"context"
+	"log"
+	"strings"
 	"time"
 
 	"github.com/yourusername/userapi/internal/domain"
+	"github.com/yourusername/userapi/internal/ports/hasher"
 	"github.com/yourusername/userapi/internal/ports/repository"
 	"github.com/yourusername/userapi/pkg/auth"
-	"golang.org/x/crypto/bcrypt"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 // AuthService handles authentication logic
 type AuthService struct {
-	userRepo repository.UserRepository
-	jwtAuth  *auth.JWTAuth
+	userRepo           repository.UserRepository
+	hasher             hasher.PasswordHasher
+	refreshTokenRepo   repository.RefreshTokenRepository
+	revocationRepo     repository.TokenRevocationRepository
+	jwtAuth            *auth.JWTAuth
+	refreshTokenExpiry time.Duration
+	verifier           *EmailVerificationService
+	unverifiedPolicy   UnverifiedLoginPolicy
+	mfaChallengeRepo   repository.MFAChallengeRepository
+	mfaChallengeTTL    time.Duration
+	throttle           *LoginThrottle
+	userOpts           []UserOption
+}
+
+// AuthOption enables optional AuthService behaviour
+type AuthOption func(*AuthService)
+
+// WithEmailVerification sends a verification link on registration and applies
+// policy when a user with an unverified email logs in
+func WithEmailVerification(verifier *EmailVerificationService, policy UnverifiedLoginPolicy) AuthOption {
+	return func(s *AuthService) {
+		s.verifier = verifier
+		s.unverifiedPolicy = policy
+	}
+}
+
+// WithMFAChallenges makes Login answer with a short-lived challenge instead of
+// tokens when the user has MFA enabled
+func WithMFAChallenges(challengeRepo repository.MFAChallengeRepository, ttl time.Duration) AuthOption {
+	return func(s *AuthService) {
+		s.mfaChallengeRepo = challengeRepo
+		s.mfaChallengeTTL = ttl
+	}
+}
+
+// WithLoginThrottle slows down and locks out repeated failed logins
+func WithLoginThrottle(throttle *LoginThrottle) AuthOption {
+	return func(s *AuthService) {
+		s.throttle = throttle
+	}
+}
+
+// WithUserOptions applies opts to the UserService used for registration and
+// password changes, e.g. WithPasswordPolicy
+func WithUserOptions(opts ...UserOption) AuthOption {
+	return func(s *AuthService) {
+		s.userOpts = opts
+	}
+}
+
+// TokenPair is the result of a successful login or token refresh
+type TokenPair struct {
+	AccessToken  string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+}
+
+// LoginResult is the result of a password login. When MFARequired is set the
+// tokens are empty and MFAToken has to be exchanged together with a code.
+type LoginResult struct {
+	*TokenPair
+	MFARequired bool   `json:"mfa_required,omitempty"`
+	MFAToken    string `json:"mfa_token,omitempty"`
 }
 
 // NewAuthService creates a new authentication service
-func NewAuthService(userRepo repository.UserRepository, jwtAuth *auth.JWTAuth) *AuthService {
-	return &AuthService{
-		userRepo: userRepo,
-		jwtAuth:  jwtAuth,
+func NewAuthService(userRepo repository.UserRepository, passwordHasher hasher.PasswordHasher, refreshTokenRepo repository.RefreshTokenRepository, revocationRepo repository.TokenRevocationRepository, jwtAuth *auth.JWTAuth, refreshTokenExpiry time.Duration, opts ...AuthOption) *AuthService {
+	s := &AuthService{
+		userRepo:           userRepo,
+		hasher:             passwordHasher,
+		refreshTokenRepo:   refreshTokenRepo,
+		revocationRepo:     revocationRepo,
+		jwtAuth:            jwtAuth,
+		refreshTokenExpiry: refreshTokenExpiry,
+		unverifiedPolicy:   UnverifiedLoginAllow,
+	}
+
+	for _, opt := range opts {
+		opt(s)
 	}
+
+	return s
 }
 
 // Register registers a new user
 func (s *AuthService) Register(ctx context.Context, name, email, password string) (*domain.User, error) {
 	// Use UserService to create user
-	userService := NewUserService(s.userRepo)
-	return userService.CreateUser(ctx, name, email, password)
+	userService := s.userService()
+	user, err := userService.CreateUser(ctx, name, email, password)
+	if err != nil {
+		return nil, err
+	}
+
+	// The user can ask for a new link, so a mail failure does not fail registration
+	if s.verifier != nil {
+		if err := s.verifier.SendVerification(ctx, user); err != nil {
+			log.Printf("Failed to send verification email: %v", err)
+		}
+	}
+
+	return user, nil
 }
 
-// Login authenticates a user and returns a JWT token
-func (s *AuthService) Login(ctx context.Context, email, password string) (string, error) {
+// Login authenticates a user and returns a JWT token and a refresh token,
+// or an MFA challenge if the user has a second factor enabled.
+// clientIP is used to throttle failed logins and may be empty.
+func (s *AuthService) Login(ctx context.Context, email, password, clientIP string) (*LoginResult, error) {
+	if s.throttle != nil {
+		if err := s.throttle.Check(ctx, email, clientIP); err != nil {
+			return nil, err
+		}
+	}
+
 	// Find user by email
 	user, err := s.userRepo.FindByEmail(ctx, email)
 	if err != nil {
-		return "", domain.ErrInvalidCredentials
+		return nil, s.loginFailed(ctx, email, clientIP)
+	}
+
+	// Users without a password can only sign in with their identity provider
+	if !user.HasPassword() {
+		return nil, s.loginFailed(ctx, email, clientIP)
 	}
 
 	// Compare password
-	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password))
+	ok, err := s.hasher.Verify(password, user.Password)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, s.loginFailed(ctx, email, clientIP)
+	}
+
+	s.upgradePasswordHash(ctx, user, password)
+
+	result, err := s.completeLogin(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+
+	// Failures are only cleared once the second factor was presented as well,
+	// see MFAService.CompleteLogin
+	if !result.MFARequired {
+		if err := s.loginSucceeded(ctx, email); err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+// completeLogin finishes a login once the user has proven who they are,
+// by password or at an identity provider
+func (s *AuthService) completeLogin(ctx context.Context, user *domain.User) (*LoginResult, error) {
+	if !user.IsActive() {
+		return nil, domain.ErrAccountInactive
+	}
+
+	if !user.IsEmailVerified() && s.unverifiedPolicy == UnverifiedLoginDeny {
+		return nil, domain.ErrEmailNotVerified
+	}
+
+	if user.IsMFAEnabled() && s.mfaChallengeRepo != nil {
+		mfaToken, err := s.createMFAChallenge(ctx, user)
+		if err != nil {
+			return nil, err
+		}
+		return &LoginResult{MFARequired: true, MFAToken: mfaToken}, nil
+	}
+
+	// Every login starts a new refresh token family
+	pair, err := s.issueTokenPair(ctx, user, primitive.NewObjectID().Hex())
+	if err != nil {
+		return nil, err
+	}
+
+	return &LoginResult{TokenPair: pair}, nil
+}
+
+// Refresh exchanges a refresh token for a new token pair.
+// Presenting a token that was already rotated revokes its whole family.
+func (s *AuthService) Refresh(ctx context.Context, refreshToken string) (*TokenPair, error) {
+	return s.refresh(ctx, refreshToken, "")
+}
+
+// RefreshForClient is Refresh for a refresh token issued to an OAuth client
+func (s *AuthService) RefreshForClient(ctx context.Context, refreshToken, clientID string) (*TokenPair, error) {
+	return s.refresh(ctx, refreshToken, clientID)
+}
+
+// refresh rotates a refresh token issued to clientID, which is empty for
+// tokens issued by this service's own login
+func (s *AuthService) refresh(ctx context.Context, refreshToken, clientID string) (*TokenPair, error) {
+	stored, err := s.refreshTokenRepo.FindByHash(ctx, auth.HashOpaqueToken(refreshToken))
 	if err != nil {
-		return "", domain.ErrInvalidCredentials
+		if err == domain.ErrRefreshTokenNotFound {
+			return nil, domain.ErrInvalidToken
+		}
+		return nil, err
+	}
+
+	if stored.ClientID != clientID {
+		return nil, domain.ErrInvalidToken
+	}
+
+	if stored.IsRevoked() || stored.IsExpired(time.Now()) {
+		return nil, domain.ErrInvalidToken
+	}
+
+	if stored.IsRotated() {
+		return nil, s.revokeFamily(ctx, stored.FamilyID)
+	}
+
+	if err := s.refreshTokenRepo.MarkRotated(ctx, stored.ID.Hex()); err != nil {
+		if err == domain.ErrRefreshTokenReused {
+			return nil, s.revokeFamily(ctx, stored.FamilyID)
+		}
+		return nil, err
+	}
+
+	ctx, user, err := findIssuedUser(ctx, s.userRepo, stored.UserID.Hex())
+	if err != nil {
+		if err == domain.ErrUserNotFound {
+			return nil, domain.ErrInvalidToken
+		}
+		return nil, err
+	}
+
+	return s.issueTokens(ctx, user, stored.FamilyID, stored.ClientID, stored.Scopes)
+}
+
+// Logout revokes the access token described by claims and, if given,
+// the refresh token family it was issued with
+func (s *AuthService) Logout(ctx context.Context, claims *auth.Claims, refreshToken string) error {
+	if claims.ID != "" && claims.ExpiresAt != nil {
+		if err := s.revocationRepo.Revoke(ctx, claims.ID, claims.ExpiresAt.Time); err != nil {
+			return err
+		}
+	}
+
+	if refreshToken == "" {
+		return nil
+	}
+
+	stored, err := s.refreshTokenRepo.FindByHash(ctx, auth.HashOpaqueToken(refreshToken))
+	if err != nil {
+		if err == domain.ErrRefreshTokenNotFound {
+			return nil
+		}
+		return err
+	}
+
+	// Never let one user revoke another user's session
+	if stored.UserID.Hex() != claims.UserID {
+		return nil
+	}
+
+	return s.refreshTokenRepo.RevokeFamily(ctx, stored.FamilyID)
+}
+
+// RevokeAllSessions invalidates every access and refresh token issued to a user so far
+func (s *AuthService) RevokeAllSessions(ctx context.Context, userID string) error {
+	if err := Authorize(ctx, ActionRevokeSessions, userID); err != nil {
+		return err
+	}
+
+	if _, err := s.userRepo.FindByID(ctx, userID); err != nil {
+		return err
+	}
+
+	return s.revokeSessions(ctx, userID)
+}
+
+// ChangePassword changes the caller's password and revokes every other session.
+// The returned token pair keeps the current client signed in.
+func (s *AuthService) ChangePassword(ctx context.Context, userID, currentPassword, newPassword string) (*TokenPair, error) {
+	userService := s.userService()
+	if err := userService.ChangePassword(ctx, userID, currentPassword, newPassword); err != nil {
+		return nil, err
+	}
+
+	if err := s.revokeSessions(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.issueTokenPair(ctx, user, primitive.NewObjectID().Hex())
+}
+
+// DeleteAccount revokes every session of a user and then deletes them
+func (s *AuthService) DeleteAccount(ctx context.Context, userID string) error {
+	if err := Authorize(ctx, ActionDeleteUser, userID); err != nil {
+		return err
+	}
+
+	// DeleteUser revokes the user's sessions
+	userService := s.userService()
+	return userService.DeleteUser(ctx, userID)
+}
+
+// revokeSessions moves the user's revocation watermark to now and revokes their refresh tokens.
+// Tokens issued later in the same second stay valid, see auth.JWTAuth.
+func (s *AuthService) revokeSessions(ctx context.Context, userID string) error {
+	if err := s.revocationRepo.RevokeAllBefore(ctx, userID, time.Now()); err != nil {
+		return err
+	}
+
+	return s.refreshTokenRepo.RevokeAllForUser(ctx, userID)
+}
+
+// issueTokenPair generates an access token and stores a new refresh token in the given family
+func (s *AuthService) issueTokenPair(ctx context.Context, user *domain.User, familyID string) (*TokenPair, error) {
+	return s.issueTokens(ctx, user, familyID, "", nil)
+}
+
+// issueTokens generates an access token and stores a new refresh token in the
+// given family. clientID and requestedScopes are set for OAuth clients.
+func (s *AuthService) issueTokens(ctx context.Context, user *domain.User, familyID, clientID string, requestedScopes []string) (*TokenPair, error) {
+	// A user blocked after a login started, e.g. during an MFA challenge or
+	// between refreshes, gets no new tokens
+	if !user.IsActive() {
+		return nil, domain.ErrInvalidToken
 	}
 
 	// Generate JWT token
-	token, err := s.jwtAuth.GenerateToken(user.ID.Hex(), user.Email)
+	accessToken, err := s.jwtAuth.GenerateToken(&auth.Claims{
+		UserID:   user.ID.Hex(),
+		TenantID: user.TenantID.Hex(),
+		Email:    user.Email,
+		Role:     string(user.Role.RoleOrDefault()),
+		Scope:    strings.Join(s.grantedScopes(user, requestedScopes), " "),
+		ClientID: clientID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	refreshToken, err := auth.GenerateOpaqueToken()
+	if err != nil {
+		return nil, err
+	}
+
+	stored := domain.NewRefreshToken(user.ID, familyID, auth.HashOpaqueToken(refreshToken), s.refreshTokenExpiry)
+	stored.ClientID = clientID
+	stored.Scopes = requestedScopes
+	if err := s.refreshTokenRepo.Create(ctx, stored); err != nil {
+		return nil, err
+	}
+
+	return &TokenPair{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+	}, nil
+}
+
+// grantedScopes returns the requested scopes the user currently has, or all
+// of them if none were requested. Requested OpenID scopes are always granted.
+func (s *AuthService) grantedScopes(user *domain.User, requested []string) []string {
+	available := s.scopesFor(user)
+	if requested == nil {
+		return available
+	}
+
+	granted := []string{}
+	for _, scope := range requested {
+		if containsScope(available, scope) || containsScope(OpenIDScopes, scope) {
+			granted = append(granted, scope)
+		}
+	}
+	return granted
+}
+
+// userService returns a UserService sharing this service's repositories, hasher and user options
+func (s *AuthService) userService() *UserService {
+	opts := append([]UserOption{WithSessionRevocation(s.revocationRepo, s.refreshTokenRepo)}, s.userOpts...)
+	return NewUserService(s.userRepo, s.hasher, opts...)
+}
+
+// upgradePasswordHash rehashes a correct password made with an outdated
+// algorithm or cost. A failure is logged and the login goes ahead.
+func (s *AuthService) upgradePasswordHash(ctx context.Context, user *domain.User, password string) {
+	if !s.hasher.NeedsRehash(user.Password) {
+		return
+	}
+
+	hashedPassword, err := s.hasher.Hash(password)
+	if err != nil {
+		log.Printf("Failed to rehash password: %v", err)
+		return
+	}
+
+	user.Password = hashedPassword
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		log.Printf("Failed to store rehashed password: %v", err)
+	}
+}
+
+// loginFailed counts a failed login and returns the error to report
+func (s *AuthService) loginFailed(ctx context.Context, email, clientIP string) error {
+	if s.throttle != nil {
+		if err := s.throttle.RecordFailure(ctx, email, clientIP); err != nil {
+			return err
+		}
+	}
+	return domain.ErrInvalidCredentials
+}
+
+// loginSucceeded clears the account's failed logins
+func (s *AuthService) loginSucceeded(ctx context.Context, email string) error {
+	if s.throttle == nil {
+		return nil
+	}
+	return s.throttle.RecordSuccess(ctx, email)
+}
+
+// createMFAChallenge stores a new MFA challenge and returns its opaque token
+func (s *AuthService) createMFAChallenge(ctx context.Context, user *domain.User) (string, error) {
+	mfaToken, err := auth.GenerateOpaqueToken()
 	if err != nil {
 		return "", err
 	}
 
-	return token, nil
+	challenge := domain.NewMFAChallenge(user.ID, auth.HashOpaqueToken(mfaToken), s.mfaChallengeTTL)
+	if err := s.mfaChallengeRepo.Create(ctx, challenge); err != nil {
+		return "", err
+	}
+
+	return mfaToken, nil
+}
+
+// scopesFor returns the scopes a user's access token is granted
+func (s *AuthService) scopesFor(user *domain.User) []string {
+	if !user.IsEmailVerified() && s.unverifiedPolicy == UnverifiedLoginRestrict {
+		return RestrictedScopes
+	}
+	return DefaultScopes
+}
+
+// revokeFamily revokes a refresh token family after reuse was detected
+func (s *AuthService) revokeFamily(ctx context.Context, familyID string) error {
+	if err := s.refreshTokenRepo.RevokeFamily(ctx, familyID); err != nil {
+		return err
+	}
+	return domain.ErrRefreshTokenReused
 }
@@ -0,0 +1,152 @@
+package application
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/yourusername/userapi/internal/adapters/hasher"
+	"github.com/yourusername/userapi/internal/adapters/repository/memory"
+	"github.com/yourusername/userapi/internal/domain"
+	"github.com/yourusername/userapi/internal/ports/repository"
+	"github.com/yourusername/userapi/pkg/auth"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestLoginUpgradesPasswordHash(t *testing.T) {
+	const (
+		email    = "ada@example.com"
+		password = "correct horse battery staple"
+	)
+
+	// Users created while bcrypt was configured log in after switching to argon2id
+	oldHasher, err := hasher.New(hasher.Config{Algorithm: hasher.AlgorithmBcrypt, BcryptCost: bcrypt.MinCost})
+	if err != nil {
+		t.Fatal(err)
+	}
+	newHasher, err := hasher.New(hasher.Config{
+		Algorithm: hasher.AlgorithmArgon2id,
+		Argon2id:  hasher.Argon2idParams{Memory: 64, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	oldHash, err := oldHasher.Hash(password)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	users := newUserStore()
+	user := domain.NewUser("Ada Lovelace", email, oldHash)
+	user.MarkEmailVerified(time.Now())
+	ctx := repository.WithTenant(context.Background(), primitive.NewObjectID().Hex())
+	if err := users.Create(ctx, user); err != nil {
+		t.Fatal(err)
+	}
+
+	revocations := memory.NewTokenRevocationRepository()
+	jwtAuth := auth.NewJWTAuth("test-secret", time.Minute, auth.WithRevocationStore(revocations))
+	authService := NewAuthService(users, newHasher, newRefreshTokenStore(), revocations, jwtAuth, time.Hour)
+
+	if _, err := authService.Login(ctx, email, "wrong password", ""); err != domain.ErrInvalidCredentials {
+		t.Fatalf("Login() with a wrong password error = %v, want ErrInvalidCredentials", err)
+	}
+	if stored, _ := users.FindByEmail(ctx, email); stored.Password != oldHash {
+		t.Fatal("a failed login changed the stored hash")
+	}
+
+	if _, err := authService.Login(ctx, email, password, ""); err != nil {
+		t.Fatal(err)
+	}
+	stored, err := users.FindByEmail(ctx, email)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if newHasher.NeedsRehash(stored.Password) {
+		t.Fatalf("stored hash %q was not upgraded", stored.Password)
+	}
+
+	// The upgraded hash still logs in
+	if _, err := authService.Login(ctx, email, password, ""); err != nil {
+		t.Fatalf("Login() with the upgraded hash: %v", err)
+	}
+}
+
+func TestRefreshTokenReuseRevokesTheFamily(t *testing.T) {
+	const (
+		email    = "ada@example.com"
+		password = "correct horse battery staple"
+	)
+
+	passwordHasher, err := hasher.New(hasher.Config{Algorithm: hasher.AlgorithmBcrypt, BcryptCost: bcrypt.MinCost})
+	if err != nil {
+		t.Fatal(err)
+	}
+	hashedPassword, err := passwordHasher.Hash(password)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		// replay is the index of the already rotated token presented again, -1 for none
+		replay int
+		// wantLatest is the error refreshing the newest token of the family afterwards
+		wantLatest error
+	}{
+		{name: "rotation without reuse", replay: -1},
+		{name: "first token replayed", replay: 0, wantLatest: domain.ErrInvalidToken},
+		{name: "middle token replayed", replay: 1, wantLatest: domain.ErrInvalidToken},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			users := newUserStore()
+			user := domain.NewUser("Ada Lovelace", email, hashedPassword)
+			ctx := repository.WithTenant(context.Background(), primitive.NewObjectID().Hex())
+			if err := users.Create(ctx, user); err != nil {
+				t.Fatal(err)
+			}
+
+			revocations := memory.NewTokenRevocationRepository()
+			jwtAuth := auth.NewJWTAuth("test-secret", time.Minute, auth.WithRevocationStore(revocations))
+			authService := NewAuthService(users, passwordHasher, newRefreshTokenStore(), revocations, jwtAuth, time.Hour)
+
+			login := func() string {
+				result, err := authService.Login(ctx, email, password, "")
+				if err != nil {
+					t.Fatal(err)
+				}
+				return result.RefreshToken
+			}
+
+			// Rotate twice: tokens[2] is the only one still valid
+			tokens := []string{login()}
+			for i := 0; i < 2; i++ {
+				pair, err := authService.Refresh(context.Background(), tokens[i])
+				if err != nil {
+					t.Fatalf("Refresh() of token %d: %v", i, err)
+				}
+				tokens = append(tokens, pair.RefreshToken)
+			}
+			otherSession := login()
+
+			if tt.replay >= 0 {
+				if _, err := authService.Refresh(context.Background(), tokens[tt.replay]); err != domain.ErrRefreshTokenReused {
+					t.Fatalf("Refresh() of a rotated token error = %v, want ErrRefreshTokenReused", err)
+				}
+			}
+
+			if _, err := authService.Refresh(context.Background(), tokens[2]); err != tt.wantLatest {
+				t.Errorf("Refresh() of the newest token error = %v, want %v", err, tt.wantLatest)
+			}
+
+			// Another login of the same user is a separate family
+			if _, err := authService.Refresh(context.Background(), otherSession); err != nil {
+				t.Errorf("Refresh() of another session: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package application
+
+import (
+	"context"
+
+	"github.com/yourusername/userapi/internal/domain"
+)
+
+// Principal is the authenticated caller of an application operation
+type Principal struct {
+	UserID string
+	Email  string
+	Role   domain.Role
+}
+
+// principalKey is the context key for the current Principal
+type principalKey struct{}
+
+// WithPrincipal returns a context carrying the authenticated caller
+func WithPrincipal(ctx context.Context, principal *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+// PrincipalFromContext returns the authenticated caller, if any
+func PrincipalFromContext(ctx context.Context) (*Principal, bool) {
+	principal, ok := ctx.Value(principalKey{}).(*Principal)
+	return principal, ok && principal != nil
+}
+
+// Action is an operation subject to authorization
+type Action string
+
+// Authorized actions on users
+const (
+	ActionListUsers      Action = "users:list"
+	ActionReadUser       Action = "users:read"
+	ActionCreateUser     Action = "users:create"
+	ActionUpdateUser     Action = "users:update"
+	ActionDeleteUser     Action = "users:delete"
+	ActionRestoreUser    Action = "users:restore"
+	ActionChangeRole     Action = "users:change-role"
+	ActionChangePassword Action = "users:change-password"
+	ActionRevokeSessions Action = "users:revoke-sessions"
+	ActionManageMFA      Action = "users:manage-mfa"
+	ActionManageAPIKeys  Action = "users:manage-api-keys"
+	ActionChangeStatus   Action = "users:change-status"
+)
+
+// Authorized actions on OAuth clients and tokens
+const (
+	ActionManageOAuthClients Action = "oauth:manage-clients"
+	ActionIntrospectTokens   Action = "tokens:introspect"
+)
+
+// Authorized actions on groups
+const (
+	ActionReadGroups   Action = "groups:read"
+	ActionManageGroups Action = "groups:manage"
+)
+
+// Authorized actions on invitations
+const (
+	ActionManageInvitations Action = "invitations:manage"
+)
+
+// policy lists the actions each role may perform on any user.
+// Actions not listed here are only allowed on the caller's own account.
+var policy = map[domain.Role]map[Action]bool{
+	domain.RoleAdmin: {
+		ActionListUsers:          true,
+		ActionReadUser:           true,
+		ActionCreateUser:         true,
+		ActionUpdateUser:         true,
+		ActionDeleteUser:         true,
+		ActionRestoreUser:        true,
+		ActionChangeRole:         true,
+		ActionChangeStatus:       true,
+		ActionRevokeSessions:     true,
+		ActionManageOAuthClients: true,
+		ActionIntrospectTokens:   true,
+		ActionReadGroups:         true,
+		ActionManageGroups:       true,
+		ActionManageInvitations:  true,
+	},
+	domain.RoleSupport: {
+		ActionListUsers:      true,
+		ActionReadUser:       true,
+		ActionRevokeSessions: true,
+		ActionReadGroups:     true,
+	},
+	domain.RoleMember: {},
+}
+
+// selfService lists the actions any user may perform on their own account
+var selfService = map[Action]bool{
+	ActionReadUser:       true,
+	ActionUpdateUser:     true,
+	ActionDeleteUser:     true,
+	ActionChangePassword: true,
+	ActionRevokeSessions: true,
+	ActionManageMFA:      true,
+	ActionManageAPIKeys:  true,
+}
+
+// Authorize checks that the caller in ctx may perform action on the target user.
+// targetUserID is empty for actions that do not concern a single user.
+func Authorize(ctx context.Context, action Action, targetUserID string) error {
+	principal, ok := PrincipalFromContext(ctx)
+	if !ok {
+		return domain.ErrUnauthenticated
+	}
+
+	if policy[principal.Role.RoleOrDefault()][action] {
+		return nil
+	}
+
+	if targetUserID != "" && targetUserID == principal.UserID && selfService[action] {
+		return nil
+	}
+
+	return domain.ErrForbidden
+}
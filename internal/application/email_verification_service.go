@@ -0,0 +1,142 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/yourusername/userapi/internal/domain"
+	"github.com/yourusername/userapi/internal/ports/mailer"
+	"github.com/yourusername/userapi/internal/ports/repository"
+	"github.com/yourusername/userapi/pkg/auth"
+)
+
+// UnverifiedLoginPolicy decides what Login does for users with an unverified email
+type UnverifiedLoginPolicy string
+
+// Unverified login policies
+const (
+	// UnverifiedLoginAllow issues a normal token
+	UnverifiedLoginAllow UnverifiedLoginPolicy = "allow"
+	// UnverifiedLoginRestrict issues a token limited to RestrictedScopes
+	UnverifiedLoginRestrict UnverifiedLoginPolicy = "restrict"
+	// UnverifiedLoginDeny refuses to log the user in
+	UnverifiedLoginDeny UnverifiedLoginPolicy = "deny"
+)
+
+// EmailVerificationService confirms that users own their email address
+type EmailVerificationService struct {
+	userRepo         repository.UserRepository
+	verificationRepo repository.EmailVerificationRepository
+	mailer           mailer.Mailer
+	tokenTTL         time.Duration
+	resendCooldown   time.Duration
+	verifyURL        string
+}
+
+// NewEmailVerificationService creates a new email verification service.
+// verifyURL is the page that receives the token as a "token" query parameter.
+func NewEmailVerificationService(userRepo repository.UserRepository, verificationRepo repository.EmailVerificationRepository, mailer mailer.Mailer, tokenTTL, resendCooldown time.Duration, verifyURL string) *EmailVerificationService {
+	return &EmailVerificationService{
+		userRepo:         userRepo,
+		verificationRepo: verificationRepo,
+		mailer:           mailer,
+		tokenTTL:         tokenTTL,
+		resendCooldown:   resendCooldown,
+		verifyURL:        verifyURL,
+	}
+}
+
+// ResendCooldown returns the minimum time between two verification emails
+func (s *EmailVerificationService) ResendCooldown() time.Duration {
+	return s.resendCooldown
+}
+
+// SendVerification emails a verification link for the user's current address.
+// Links sent earlier stop working.
+func (s *EmailVerificationService) SendVerification(ctx context.Context, user *domain.User) error {
+	token, err := auth.GenerateOpaqueToken()
+	if err != nil {
+		return err
+	}
+
+	if err := s.verificationRepo.DeleteByUserID(ctx, user.ID.Hex()); err != nil {
+		return err
+	}
+
+	stored := domain.NewEmailVerificationToken(user.ID, user.Email, auth.HashOpaqueToken(token), s.tokenTTL)
+	if err := s.verificationRepo.Create(ctx, stored); err != nil {
+		return err
+	}
+
+	return s.mailer.Send(ctx, mailer.Message{
+		To:      user.Email,
+		Subject: "Confirm your email address",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nPlease confirm your email address by opening the link below. It expires in %s.\n\n%s",
+			user.Name, s.tokenTTL, linkWithToken(s.verifyURL, token),
+		),
+	})
+}
+
+// Verify consumes a verification token and marks the address as verified
+func (s *EmailVerificationService) Verify(ctx context.Context, token string) (*domain.User, error) {
+	stored, err := s.verificationRepo.FindByHash(ctx, auth.HashOpaqueToken(token))
+	if err != nil {
+		return nil, err
+	}
+
+	if stored.IsExpired(time.Now()) {
+		return nil, domain.ErrInvalidToken
+	}
+
+	ctx, user, err := findIssuedUser(ctx, s.userRepo, stored.UserID.Hex())
+	if err != nil {
+		if err == domain.ErrUserNotFound {
+			return nil, domain.ErrInvalidToken
+		}
+		return nil, err
+	}
+
+	// The user changed their address after this link was sent
+	if user.Email != stored.Email {
+		return nil, domain.ErrInvalidToken
+	}
+
+	user.MarkEmailVerified(time.Now())
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return nil, err
+	}
+
+	if err := s.verificationRepo.DeleteByUserID(ctx, user.ID.Hex()); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// Resend sends a new verification link to the caller, at most once per cooldown
+func (s *EmailVerificationService) Resend(ctx context.Context, userID string) error {
+	if err := Authorize(ctx, ActionUpdateUser, userID); err != nil {
+		return err
+	}
+
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if user.IsEmailVerified() {
+		return domain.ErrEmailAlreadyVerified
+	}
+
+	latest, err := s.verificationRepo.FindLatestByUserID(ctx, userID)
+	if err != nil && err != domain.ErrInvalidToken {
+		return err
+	}
+	if latest != nil && time.Since(latest.CreatedAt) < s.resendCooldown {
+		return domain.ErrVerificationCooldown
+	}
+
+	return s.SendVerification(ctx, user)
+}
@@ -0,0 +1,223 @@
+package application
+
+import (
+	"context"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/yourusername/userapi/internal/domain"
+	"github.com/yourusername/userapi/internal/ports/repository"
+	"github.com/yourusername/userapi/pkg/auth"
+	"github.com/yourusername/userapi/pkg/oidc"
+)
+
+// IdentityProvider is an upstream OpenID Connect provider users can sign in with
+type IdentityProvider struct {
+	Provider *oidc.Provider
+	// TenantID is the tenant whose users sign in with the provider
+	TenantID string
+	// CreateUsers creates an account on first sign in when none exists for the email address
+	CreateUsers bool
+	// LinkByEmail links a first sign in to an existing account with the same
+	// email address. Only enable it for providers that own their users'
+	// email domains, or anyone able to register that address there could
+	// take over the account.
+	LinkByEmail bool
+}
+
+// FederatedLogin is returned when a user is sent to an identity provider.
+// State has to come back with the callback.
+type FederatedLogin struct {
+	AuthURL string
+	State   string
+}
+
+// FederationService signs users in with upstream OpenID Connect providers,
+// linking the provider account to a local user
+type FederationService struct {
+	providers    map[string]IdentityProvider
+	identityRepo repository.ExternalIdentityRepository
+	stateRepo    repository.FederatedLoginStateRepository
+	userRepo     repository.UserRepository
+	authService  *AuthService
+	stateTTL     time.Duration
+}
+
+// NewFederationService creates a new federation service. stateTTL is how long
+// the user has to complete the login at the provider.
+func NewFederationService(providers []IdentityProvider, identityRepo repository.ExternalIdentityRepository, stateRepo repository.FederatedLoginStateRepository, userRepo repository.UserRepository, authService *AuthService, stateTTL time.Duration) *FederationService {
+	byName := make(map[string]IdentityProvider, len(providers))
+	for _, provider := range providers {
+		byName[provider.Provider.Name()] = provider
+	}
+
+	return &FederationService{
+		providers:    byName,
+		identityRepo: identityRepo,
+		stateRepo:    stateRepo,
+		userRepo:     userRepo,
+		authService:  authService,
+		stateTTL:     stateTTL,
+	}
+}
+
+// Providers returns the names of the configured providers
+func (s *FederationService) Providers() []string {
+	names := make([]string, 0, len(s.providers))
+	for name := range s.providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// BeginLogin starts a login at the named provider
+func (s *FederationService) BeginLogin(ctx context.Context, providerName string) (*FederatedLogin, error) {
+	provider, ok := s.providers[providerName]
+	if !ok {
+		return nil, domain.ErrUnknownProvider
+	}
+
+	state, err := auth.GenerateOpaqueToken()
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := auth.GenerateOpaqueToken()
+	if err != nil {
+		return nil, err
+	}
+	verifier, err := auth.GenerateOpaqueToken()
+	if err != nil {
+		return nil, err
+	}
+
+	authURL, err := provider.Provider.AuthCodeURL(ctx, state, nonce, auth.PKCEChallenge(verifier))
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	stored := &domain.FederatedLoginState{
+		StateHash:    auth.HashOpaqueToken(state),
+		Provider:     providerName,
+		Nonce:        nonce,
+		CodeVerifier: verifier,
+		ExpiresAt:    now.Add(s.stateTTL),
+		CreatedAt:    now,
+	}
+	if err := s.stateRepo.Create(ctx, stored); err != nil {
+		return nil, err
+	}
+
+	return &FederatedLogin{AuthURL: authURL, State: state}, nil
+}
+
+// CompleteLogin handles the provider's callback. The returned result is a
+// token pair, or an MFA challenge if the user enabled a second factor here.
+func (s *FederationService) CompleteLogin(ctx context.Context, providerName, state, code string) (*LoginResult, error) {
+	provider, ok := s.providers[providerName]
+	if !ok {
+		return nil, domain.ErrUnknownProvider
+	}
+
+	stored, err := s.stateRepo.Consume(ctx, auth.HashOpaqueToken(state))
+	if err != nil {
+		return nil, err
+	}
+	if stored.Provider != providerName || stored.IsExpired(time.Now()) {
+		return nil, domain.ErrInvalidToken
+	}
+
+	rawIDToken, err := provider.Provider.Exchange(ctx, code, stored.CodeVerifier)
+	if err != nil {
+		return nil, err
+	}
+
+	claims, err := provider.Provider.VerifyIDToken(ctx, rawIDToken, stored.Nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	// Users are looked up, linked and created in the provider's tenant only
+	ctx = repository.WithTenant(ctx, provider.TenantID)
+
+	user, err := s.resolveUser(ctx, provider, claims)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.authService.completeLogin(ctx, user)
+}
+
+// resolveUser returns the user linked to the provider account, linking or
+// creating one on first sign in
+func (s *FederationService) resolveUser(ctx context.Context, provider IdentityProvider, claims *auth.IDTokenClaims) (*domain.User, error) {
+	providerName := provider.Provider.Name()
+
+	identity, err := s.identityRepo.FindBySubject(ctx, providerName, claims.Subject)
+	if err == nil {
+		if err := s.identityRepo.TouchLastLogin(ctx, identity.ID.Hex(), time.Now()); err != nil {
+			log.Printf("Failed to record federated login: %v", err)
+		}
+		user, err := s.userRepo.FindByID(ctx, identity.UserID.Hex())
+		if err == domain.ErrUserNotFound {
+			// The user was deleted and their identities await the purge
+			return nil, domain.ErrAccountInactive
+		}
+		return user, err
+	}
+	if err != domain.ErrIdentityNotFound {
+		return nil, err
+	}
+
+	// An unverified address proves nothing about who owns the account
+	if claims.Email == "" || claims.EmailVerified == nil || !*claims.EmailVerified {
+		return nil, domain.ErrEmailNotVerified
+	}
+
+	user, err := s.userRepo.FindByEmail(ctx, claims.Email)
+	if err == domain.ErrUserNotFound {
+		if !provider.CreateUsers {
+			return nil, domain.ErrSignupNotAllowed
+		}
+		user, err = s.createUser(ctx, claims)
+		if err != nil {
+			return nil, err
+		}
+	} else if err != nil {
+		return nil, err
+	} else if !provider.LinkByEmail {
+		return nil, domain.ErrIdentityConflict
+	}
+
+	if err := s.identityRepo.Create(ctx, domain.NewExternalIdentity(user.ID, providerName, claims.Subject, claims.Email)); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// createUser creates an account without a password for a first sign in.
+// The provider verified the email address, so it counts as verified here.
+func (s *FederationService) createUser(ctx context.Context, claims *auth.IDTokenClaims) (*domain.User, error) {
+	name := claims.Name
+	if name == "" {
+		name = strings.SplitN(claims.Email, "@", 2)[0]
+	}
+
+	// The address may still be reserved for a deleted user
+	if err := s.authService.userService().checkEmailAvailable(ctx, claims.Email); err != nil {
+		return nil, err
+	}
+
+	user := domain.NewUser(name, claims.Email, "")
+	user.MarkEmailVerified(time.Now())
+
+	if err := s.userRepo.Create(ctx, user); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
@@ -0,0 +1,128 @@
+package application
+
+import (
+	"context"
+
+	"github.com/yourusername/userapi/internal/domain"
+	"github.com/yourusername/userapi/internal/ports/repository"
+)
+
+// GroupService organizes the users of a tenant into groups
+type GroupService struct {
+	groupRepo repository.GroupRepository
+	userRepo  repository.UserRepository
+}
+
+// NewGroupService creates a new group service
+func NewGroupService(groupRepo repository.GroupRepository, userRepo repository.UserRepository) *GroupService {
+	return &GroupService{
+		groupRepo: groupRepo,
+		userRepo:  userRepo,
+	}
+}
+
+// CreateGroup creates a group without members
+func (s *GroupService) CreateGroup(ctx context.Context, name, description string) (*domain.Group, error) {
+	if err := Authorize(ctx, ActionManageGroups, ""); err != nil {
+		return nil, err
+	}
+
+	group := domain.NewGroup(name, description)
+	if err := s.groupRepo.Create(ctx, group); err != nil {
+		return nil, err
+	}
+
+	return group, nil
+}
+
+// GetGroup retrieves a group by ID
+func (s *GroupService) GetGroup(ctx context.Context, id string) (*domain.Group, error) {
+	if err := Authorize(ctx, ActionReadGroups, ""); err != nil {
+		return nil, err
+	}
+
+	return s.groupRepo.FindByID(ctx, id)
+}
+
+// ListGroups retrieves all groups
+func (s *GroupService) ListGroups(ctx context.Context) ([]*domain.Group, error) {
+	if err := Authorize(ctx, ActionReadGroups, ""); err != nil {
+		return nil, err
+	}
+
+	return s.groupRepo.FindAll(ctx)
+}
+
+// UpdateGroup renames a group and replaces its description
+func (s *GroupService) UpdateGroup(ctx context.Context, id, name, description string) (*domain.Group, error) {
+	if err := Authorize(ctx, ActionManageGroups, ""); err != nil {
+		return nil, err
+	}
+
+	group, err := s.groupRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	group.Name = name
+	group.Description = description
+	if err := s.groupRepo.Update(ctx, group); err != nil {
+		return nil, err
+	}
+
+	return group, nil
+}
+
+// DeleteGroup deletes a group. Its members are not affected.
+func (s *GroupService) DeleteGroup(ctx context.Context, id string) error {
+	if err := Authorize(ctx, ActionManageGroups, ""); err != nil {
+		return err
+	}
+
+	return s.groupRepo.Delete(ctx, id)
+}
+
+// AddMember adds a user to a group and returns the updated group
+func (s *GroupService) AddMember(ctx context.Context, groupID, userID string) (*domain.Group, error) {
+	if err := Authorize(ctx, ActionManageGroups, ""); err != nil {
+		return nil, err
+	}
+
+	// Only users of the same tenant can be added
+	if _, err := s.userRepo.FindByID(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	if err := s.groupRepo.AddMember(ctx, groupID, userID); err != nil {
+		return nil, err
+	}
+
+	return s.groupRepo.FindByID(ctx, groupID)
+}
+
+// RemoveMember removes a user from a group and returns the updated group
+func (s *GroupService) RemoveMember(ctx context.Context, groupID, userID string) (*domain.Group, error) {
+	if err := Authorize(ctx, ActionManageGroups, ""); err != nil {
+		return nil, err
+	}
+
+	if err := s.groupRepo.RemoveMember(ctx, groupID, userID); err != nil {
+		return nil, err
+	}
+
+	return s.groupRepo.FindByID(ctx, groupID)
+}
+
+// ListUserGroups returns the groups a user is a member of. Like the user
+// itself, users can read their own groups.
+func (s *GroupService) ListUserGroups(ctx context.Context, userID string) ([]*domain.Group, error) {
+	if err := Authorize(ctx, ActionReadUser, userID); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.userRepo.FindByID(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	return s.groupRepo.FindByMember(ctx, userID)
+}
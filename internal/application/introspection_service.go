@@ -0,0 +1,150 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/yourusername/userapi/internal/domain"
+	"github.com/yourusername/userapi/pkg/auth"
+)
+
+// TokenIntrospection describes a token (RFC 7662 section 2.2). An inactive
+// token only carries Active, so nothing is revealed about why it was rejected.
+type TokenIntrospection struct {
+	Active    bool     `json:"active"`
+	Scope     string   `json:"scope,omitempty"`
+	ClientID  string   `json:"client_id,omitempty"`
+	Username  string   `json:"username,omitempty"`
+	TenantID  string   `json:"tenant_id,omitempty"`
+	TokenType string   `json:"token_type,omitempty"`
+	ExpiresAt int64    `json:"exp,omitempty"`
+	IssuedAt  int64    `json:"iat,omitempty"`
+	Subject   string   `json:"sub,omitempty"`
+	Audience  []string `json:"aud,omitempty"`
+	Issuer    string   `json:"iss,omitempty"`
+	TokenID   string   `json:"jti,omitempty"`
+}
+
+// IntrospectionCaller holds the credentials of a service asking about a token.
+// Either ClientID and ClientSecret of a confidential OAuth client, or APIKey is set.
+type IntrospectionCaller struct {
+	ClientID     string
+	ClientSecret string
+	APIKey       string
+}
+
+// IntrospectionService lets other services check tokens they cannot verify
+// locally. API keys can be introspected as well as access tokens.
+type IntrospectionService struct {
+	jwtAuth       *auth.JWTAuth
+	oauthService  *OAuthService
+	apiKeyService *APIKeyService
+}
+
+// NewIntrospectionService creates a new introspection service. oauthService
+// or apiKeyService may be nil if callers never authenticate that way.
+func NewIntrospectionService(jwtAuth *auth.JWTAuth, oauthService *OAuthService, apiKeyService *APIKeyService) *IntrospectionService {
+	return &IntrospectionService{
+		jwtAuth:       jwtAuth,
+		oauthService:  oauthService,
+		apiKeyService: apiKeyService,
+	}
+}
+
+// Introspect authenticates the caller and describes token. A token that fails
+// any of the checks of JWTAuth.ValidateToken, including revocation, is inactive.
+// So is a token of another tenant than the caller's, to keep its user private.
+func (s *IntrospectionService) Introspect(ctx context.Context, caller IntrospectionCaller, token string) (*TokenIntrospection, error) {
+	callerTenantID, err := s.authenticateCaller(ctx, caller)
+	if err != nil {
+		return nil, err
+	}
+
+	var claims *auth.Claims
+	if strings.HasPrefix(token, auth.APIKeyPrefix) && s.apiKeyService != nil {
+		claims, err = s.apiKeyService.Authenticate(ctx, token)
+		if err != nil && err != domain.ErrInvalidToken {
+			return nil, err
+		}
+	} else {
+		// When in doubt, e.g. if the revocation store cannot be reached,
+		// the token is reported inactive
+		claims, err = s.jwtAuth.ValidateToken(ctx, token)
+	}
+	if err != nil || claims.TenantID != callerTenantID {
+		return &TokenIntrospection{}, nil
+	}
+
+	introspection := &TokenIntrospection{
+		Active:    true,
+		Scope:     claims.Scope,
+		ClientID:  claims.ClientID,
+		Username:  claims.Email,
+		TenantID:  claims.TenantID,
+		TokenType: "Bearer",
+		Subject:   claims.Subject,
+		Audience:  claims.Audience,
+		Issuer:    claims.Issuer,
+		TokenID:   claims.ID,
+	}
+	if introspection.Subject == "" {
+		introspection.Subject = claims.UserID
+	}
+	if claims.ExpiresAt != nil {
+		introspection.ExpiresAt = claims.ExpiresAt.Unix()
+	}
+	if claims.IssuedAt != nil {
+		introspection.IssuedAt = claims.IssuedAt.Unix()
+	}
+
+	return introspection, nil
+}
+
+// authenticateCaller accepts confidential OAuth clients, and API keys of
+// users allowed to introspect tokens. It returns the caller's tenant.
+func (s *IntrospectionService) authenticateCaller(ctx context.Context, caller IntrospectionCaller) (string, error) {
+	if caller.APIKey != "" {
+		if s.apiKeyService == nil {
+			return "", domain.ErrUnauthenticated
+		}
+
+		claims, err := s.apiKeyService.Authenticate(ctx, caller.APIKey)
+		if err != nil {
+			if err == domain.ErrInvalidToken {
+				return "", domain.ErrUnauthenticated
+			}
+			return "", err
+		}
+
+		if err := Authorize(WithPrincipal(ctx, &Principal{
+			UserID: claims.UserID,
+			Email:  claims.Email,
+			Role:   domain.Role(claims.Role),
+		}), ActionIntrospectTokens, ""); err != nil {
+			return "", err
+		}
+
+		return claims.TenantID, nil
+	}
+
+	if s.oauthService == nil || caller.ClientID == "" {
+		return "", domain.ErrUnauthenticated
+	}
+
+	client, err := s.oauthService.authenticateClient(ctx, caller.ClientID, caller.ClientSecret)
+	if err != nil {
+		var oauthErr *domain.OAuthError
+		if errors.As(err, &oauthErr) {
+			return "", domain.ErrUnauthenticated
+		}
+		return "", err
+	}
+
+	// A public client has no secret, so it proves nothing about the caller
+	if client.Public {
+		return "", domain.ErrUnauthenticated
+	}
+
+	return client.TenantID.Hex(), nil
+}
@@ -0,0 +1,249 @@
+package application
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/yourusername/userapi/internal/domain"
+	"github.com/yourusername/userapi/internal/ports/mailer"
+	"github.com/yourusername/userapi/internal/ports/repository"
+	"github.com/yourusername/userapi/pkg/auth"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// InvitationService lets administrators invite people to a tenant instead of
+// creating accounts for them. The invitee chooses their own password.
+type InvitationService struct {
+	invitationRepo repository.InvitationRepository
+	userRepo       repository.UserRepository
+	groupRepo      repository.GroupRepository
+	authService    *AuthService
+	mailer         mailer.Mailer
+	linkKey        []byte
+	tokenTTL       time.Duration
+	acceptURL      string
+}
+
+// NewInvitationService creates a new invitation service. groupRepo may be nil
+// if groups are not used, invitations then cannot name groups.
+// linkKey signs the emailed links. acceptURL is the frontend page that
+// receives the token as a "token" query parameter.
+func NewInvitationService(invitationRepo repository.InvitationRepository, userRepo repository.UserRepository, groupRepo repository.GroupRepository, authService *AuthService, mailer mailer.Mailer, linkKey []byte, tokenTTL time.Duration, acceptURL string) *InvitationService {
+	return &InvitationService{
+		invitationRepo: invitationRepo,
+		userRepo:       userRepo,
+		groupRepo:      groupRepo,
+		authService:    authService,
+		mailer:         mailer,
+		linkKey:        linkKey,
+		tokenTTL:       tokenTTL,
+		acceptURL:      acceptURL,
+	}
+}
+
+// Invite emails an invitation to join the caller's tenant with role and
+// groups. An empty role invites a member.
+func (s *InvitationService) Invite(ctx context.Context, email string, role domain.Role, groupIDs []string) (*domain.Invitation, error) {
+	if err := Authorize(ctx, ActionManageInvitations, ""); err != nil {
+		return nil, err
+	}
+
+	role = role.RoleOrDefault()
+	if !role.IsValid() {
+		return nil, domain.ErrInvalidRole
+	}
+
+	if err := s.authService.userService().checkEmailAvailable(ctx, email); err != nil {
+		return nil, err
+	}
+
+	// A second invitation would leave two working links, the existing one can be resent
+	if _, err := s.invitationRepo.FindPendingByEmail(ctx, email); err == nil {
+		return nil, domain.ErrInvitationPending
+	} else if err != domain.ErrInvitationNotFound {
+		return nil, err
+	}
+
+	groups, err := s.findGroups(ctx, groupIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	var invitedBy primitive.ObjectID
+	if principal, ok := PrincipalFromContext(ctx); ok {
+		invitedBy, _ = primitive.ObjectIDFromHex(principal.UserID)
+	}
+
+	token, err := auth.GenerateOpaqueToken()
+	if err != nil {
+		return nil, err
+	}
+
+	invitation := domain.NewInvitation(email, role, groups, invitedBy, auth.HashOpaqueToken(token), s.tokenTTL)
+	if err := s.invitationRepo.Create(ctx, invitation); err != nil {
+		return nil, err
+	}
+
+	s.sendInvitation(ctx, invitation, token)
+	return invitation, nil
+}
+
+// ListPending returns the invitations of the caller's tenant that were
+// neither accepted nor revoked, including expired ones that can be resent
+func (s *InvitationService) ListPending(ctx context.Context) ([]*domain.Invitation, error) {
+	if err := Authorize(ctx, ActionManageInvitations, ""); err != nil {
+		return nil, err
+	}
+
+	return s.invitationRepo.FindPending(ctx)
+}
+
+// Resend emails a new link for a pending invitation and restarts its
+// expiry. Links sent before stop working.
+func (s *InvitationService) Resend(ctx context.Context, id string) (*domain.Invitation, error) {
+	if err := Authorize(ctx, ActionManageInvitations, ""); err != nil {
+		return nil, err
+	}
+
+	token, err := auth.GenerateOpaqueToken()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.invitationRepo.Renew(ctx, id, auth.HashOpaqueToken(token), time.Now().Add(s.tokenTTL)); err != nil {
+		return nil, err
+	}
+
+	invitation, err := s.invitationRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	s.sendInvitation(ctx, invitation, token)
+	return invitation, nil
+}
+
+// Revoke withdraws a pending invitation so its link stops working
+func (s *InvitationService) Revoke(ctx context.Context, id string) error {
+	if err := Authorize(ctx, ActionManageInvitations, ""); err != nil {
+		return err
+	}
+
+	return s.invitationRepo.Revoke(ctx, id, time.Now())
+}
+
+// Accept consumes an invitation and creates the invitee's account with the
+// role and groups the administrator chose. The email address counts as
+// verified since the invitation was delivered to it.
+func (s *InvitationService) Accept(ctx context.Context, token, name, password string) (*domain.User, error) {
+	invitation, err := s.findByToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	ctx = repository.WithTenant(ctx, invitation.TenantID.Hex())
+
+	// A rejected password or an account created in the meantime leaves the
+	// invitation pending
+	user, err := s.authService.userService().newUser(ctx, name, invitation.Email, password)
+	if err != nil {
+		return nil, err
+	}
+	user.Role = invitation.Role
+	user.MarkEmailVerified(time.Now())
+
+	// A concurrent accept of the same invitation fails here, on the unique email address
+	if err := s.userRepo.Create(ctx, user); err != nil {
+		return nil, err
+	}
+
+	// The account exists and the email address is taken, so the invitation
+	// cannot be used again even if it stays pending
+	if err := s.invitationRepo.MarkAccepted(ctx, invitation.ID.Hex(), time.Now()); err != nil {
+		log.Printf("Failed to mark invitation %s accepted: %v", invitation.ID.Hex(), err)
+	}
+
+	for _, groupID := range invitation.GroupIDs {
+		// A group deleted since the invitation was sent is skipped
+		err := s.groupRepo.AddMember(ctx, groupID.Hex(), user.ID.Hex())
+		if err != nil && err != domain.ErrGroupNotFound {
+			return nil, err
+		}
+	}
+
+	return user, nil
+}
+
+// findByToken returns the usable invitation an emailed token was issued for.
+// The signed token names the invitation and its tenant, its secret part has
+// to match the stored hash so resent links replace earlier ones.
+func (s *InvitationService) findByToken(ctx context.Context, token string) (*domain.Invitation, error) {
+	values, err := auth.VerifyLink(s.linkKey, token, time.Now())
+	if err != nil || len(values) != 3 {
+		return nil, domain.ErrInvalidToken
+	}
+	id, tenantID, secret := values[0], values[1], values[2]
+
+	// The account is created in the tenant the invitation was issued for
+	if requested, ok := repository.TenantFromContext(ctx); ok && requested != tenantID {
+		return nil, domain.ErrInvalidToken
+	}
+
+	invitation, err := s.invitationRepo.FindByID(repository.WithTenant(ctx, tenantID), id)
+	if err == domain.ErrInvitationNotFound {
+		return nil, domain.ErrInvalidToken
+	} else if err != nil {
+		return nil, err
+	}
+
+	if subtle.ConstantTimeCompare([]byte(auth.HashOpaqueToken(secret)), []byte(invitation.TokenHash)) != 1 || !invitation.IsUsable(time.Now()) {
+		return nil, domain.ErrInvalidToken
+	}
+
+	return invitation, nil
+}
+
+// linkToken signs the invitation's id, tenant and expiry together with the secret token
+func (s *InvitationService) linkToken(invitation *domain.Invitation, token string) string {
+	return auth.SignLink(s.linkKey, invitation.ExpiresAt, invitation.ID.Hex(), invitation.TenantID.Hex(), token)
+}
+
+// findGroups checks that every group exists in the caller's tenant
+func (s *InvitationService) findGroups(ctx context.Context, groupIDs []string) ([]primitive.ObjectID, error) {
+	if len(groupIDs) == 0 {
+		return nil, nil
+	}
+	if s.groupRepo == nil {
+		return nil, domain.ErrGroupNotFound
+	}
+
+	groups := make([]primitive.ObjectID, 0, len(groupIDs))
+	for _, id := range groupIDs {
+		group, err := s.groupRepo.FindByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		groups = append(groups, group.ID)
+	}
+
+	return groups, nil
+}
+
+// sendInvitation emails the invitation link. The administrator can resend
+// the invitation, so a mail failure is only logged.
+func (s *InvitationService) sendInvitation(ctx context.Context, invitation *domain.Invitation, token string) {
+	msg := mailer.Message{
+		To:      invitation.Email,
+		Subject: "You have been invited",
+		Body: fmt.Sprintf(
+			"Hi,\n\nYou have been invited to create an account. Use the link below to choose your name and password. It expires in %s and can only be used once.\n\n%s\n\nIf you were not expecting this, you can ignore this email.",
+			s.tokenTTL, linkWithToken(s.acceptURL, s.linkToken(invitation, token)),
+		),
+	}
+
+	if err := s.mailer.Send(ctx, msg); err != nil {
+		log.Printf("Failed to send invitation email: %v", err)
+	}
+}
@@ -0,0 +1,244 @@
+package application
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/yourusername/userapi/internal/domain"
+	"github.com/yourusername/userapi/internal/ports/repository"
+	"github.com/yourusername/userapi/pkg/auth"
+	"github.com/yourusername/userapi/pkg/encryption"
+)
+
+// KeyRotationService manages the lifecycle of JWT signing keys.
+//
+// A rotation is: generate a key (published for verification only), promote it
+// once downstream JWKS caches have picked it up, then retire the previous key
+// after the longest token lifetime has passed.
+//
+// Private keys are encrypted before they are stored, so read access to the
+// database is not enough to forge tokens.
+type KeyRotationService struct {
+	keyRepo          repository.SigningKeyRepository
+	eventRepo        repository.KeyRotationEventRepository
+	keyring          *auth.Keyring
+	cipher           *encryption.Cipher
+	maxTokenLifetime time.Duration
+}
+
+// NewKeyRotationService creates a new key rotation service
+func NewKeyRotationService(keyRepo repository.SigningKeyRepository, eventRepo repository.KeyRotationEventRepository, keyring *auth.Keyring, cipher *encryption.Cipher, maxTokenLifetime time.Duration) *KeyRotationService {
+	return &KeyRotationService{
+		keyRepo:          keyRepo,
+		eventRepo:        eventRepo,
+		keyring:          keyring,
+		cipher:           cipher,
+		maxTokenLifetime: maxTokenLifetime,
+	}
+}
+
+// LoadKeys replaces the keyring's managed keys with the stored ones
+func (s *KeyRotationService) LoadKeys(ctx context.Context) error {
+	stored, err := s.keyRepo.FindUnretired(ctx)
+	if err != nil {
+		return err
+	}
+
+	var (
+		active       *auth.SigningKey
+		verification []*auth.SigningKey
+	)
+	for _, record := range stored {
+		privateKeyPEM, err := s.privateKeyPEM(record)
+		if err != nil {
+			return err
+		}
+
+		key, err := auth.ParsePrivateKeyPEM(record.ID, record.Algorithm, privateKeyPEM)
+		if err != nil {
+			return err
+		}
+
+		if record.Status == domain.SigningKeyActive {
+			active = key
+		} else {
+			verification = append(verification, key)
+		}
+	}
+
+	s.keyring.SetManagedKeys(active, verification)
+	return nil
+}
+
+// RunSync reloads the keys on every tick so rotations made elsewhere are picked up
+func (s *KeyRotationService) RunSync(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.LoadKeys(ctx); err != nil {
+				log.Printf("Failed to reload signing keys: %v", err)
+			}
+		}
+	}
+}
+
+// GenerateKey creates a new pending key that verifies but does not sign yet
+func (s *KeyRotationService) GenerateKey(ctx context.Context, algorithm, actor string) (*domain.SigningKey, error) {
+	key, err := auth.GenerateSigningKey(algorithm)
+	if err != nil {
+		return nil, err
+	}
+
+	privateKeyPEM, err := key.PrivateKeyPEM()
+	if err != nil {
+		return nil, err
+	}
+
+	encrypted, err := s.cipher.Encrypt(privateKeyPEM)
+	if err != nil {
+		return nil, err
+	}
+
+	record := domain.NewSigningKey(key.ID, algorithm, encrypted)
+	if err := s.keyRepo.Create(ctx, record); err != nil {
+		return nil, err
+	}
+
+	if err := s.record(ctx, record.ID, domain.KeyGenerated, actor); err != nil {
+		return nil, err
+	}
+
+	return record, s.LoadKeys(ctx)
+}
+
+// PromoteKey makes a key the active signing key and demotes the current one
+func (s *KeyRotationService) PromoteKey(ctx context.Context, id, actor string) (*domain.SigningKey, error) {
+	record, err := s.keyRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	stored, err := s.keyRepo.FindUnretired(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if err := record.Activate(now); err != nil {
+		return nil, err
+	}
+
+	// Demote the current key first so there is never more than one active key
+	for _, current := range stored {
+		if current.ID == record.ID || current.Status != domain.SigningKeyActive {
+			continue
+		}
+
+		if err := current.Deactivate(now); err != nil {
+			return nil, err
+		}
+		if err := s.keyRepo.Update(ctx, current); err != nil {
+			return nil, err
+		}
+		if err := s.record(ctx, current.ID, domain.KeyDeactivated, actor); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.keyRepo.Update(ctx, record); err != nil {
+		return nil, err
+	}
+
+	if err := s.record(ctx, record.ID, domain.KeyPromoted, actor); err != nil {
+		return nil, err
+	}
+
+	return record, s.LoadKeys(ctx)
+}
+
+// RetireKey removes a key from verification once its tokens can no longer be valid
+func (s *KeyRotationService) RetireKey(ctx context.Context, id, actor string) (*domain.SigningKey, error) {
+	record, err := s.keyRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := record.Retire(time.Now(), s.maxTokenLifetime); err != nil {
+		return nil, err
+	}
+
+	if err := s.keyRepo.Update(ctx, record); err != nil {
+		return nil, err
+	}
+
+	if err := s.record(ctx, record.ID, domain.KeyRetired, actor); err != nil {
+		return nil, err
+	}
+
+	return record, s.LoadKeys(ctx)
+}
+
+// EncryptStoredKeys encrypts the private keys that were stored before keys
+// were encrypted at rest, and returns the keys it changed
+func (s *KeyRotationService) EncryptStoredKeys(ctx context.Context, actor string) ([]*domain.SigningKey, error) {
+	stored, err := s.keyRepo.FindUnretired(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	encrypted := []*domain.SigningKey{}
+	for _, record := range stored {
+		if record.IsEncrypted() {
+			continue
+		}
+
+		ciphertext, err := s.cipher.Encrypt([]byte(record.PrivateKeyPEM))
+		if err != nil {
+			return nil, err
+		}
+
+		record.EncryptedPrivateKey = ciphertext
+		record.PrivateKeyPEM = ""
+		if err := s.keyRepo.Update(ctx, record); err != nil {
+			return nil, err
+		}
+		if err := s.record(ctx, record.ID, domain.KeyEncrypted, actor); err != nil {
+			return nil, err
+		}
+
+		encrypted = append(encrypted, record)
+	}
+
+	return encrypted, nil
+}
+
+// ListKeys retrieves every key that is not retired
+func (s *KeyRotationService) ListKeys(ctx context.Context) ([]*domain.SigningKey, error) {
+	return s.keyRepo.FindUnretired(ctx)
+}
+
+// ListEvents retrieves the key rotation audit log
+func (s *KeyRotationService) ListEvents(ctx context.Context) ([]*domain.KeyRotationEvent, error) {
+	return s.eventRepo.FindAll(ctx)
+}
+
+// privateKeyPEM returns the PEM encoded private key of a stored key
+func (s *KeyRotationService) privateKeyPEM(record *domain.SigningKey) ([]byte, error) {
+	if !record.IsEncrypted() {
+		// Stored before keys were encrypted; EncryptStoredKeys fixes that
+		return []byte(record.PrivateKeyPEM), nil
+	}
+
+	return s.cipher.Decrypt(record.EncryptedPrivateKey)
+}
+
+// record writes an audit event for a key change
+func (s *KeyRotationService) record(ctx context.Context, keyID string, action domain.KeyRotationAction, actor string) error {
+	return s.eventRepo.Create(ctx, domain.NewKeyRotationEvent(keyID, action, actor))
+}
@@ -0,0 +1,16 @@
+package application
+
+import "net/url"
+
+// linkWithToken adds a "token" query parameter to a frontend URL sent by email
+func linkWithToken(base, token string) string {
+	u, err := url.Parse(base)
+	if err != nil {
+		return base + "?token=" + url.QueryEscape(token)
+	}
+
+	query := u.Query()
+	query.Set("token", token)
+	u.RawQuery = query.Encode()
+	return u.String()
+}
@@ -0,0 +1,161 @@
+package application
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/yourusername/userapi/internal/domain"
+	"github.com/yourusername/userapi/internal/ports/repository"
+)
+
+// LockoutPolicy decides how long a key has to wait after failed logins.
+// Past BackoffAfter failures every further failure doubles the wait, starting
+// at BaseDelay, up to LockoutDuration. From LockoutAfter failures on the key is
+// locked for LockoutDuration.
+type LockoutPolicy struct {
+	BackoffAfter    int
+	BaseDelay       time.Duration
+	LockoutAfter    int
+	LockoutDuration time.Duration
+	// Window is how long failures are remembered after the last one
+	Window time.Duration
+}
+
+var (
+	// DefaultAccountLockoutPolicy applies to failed logins for one email address
+	DefaultAccountLockoutPolicy = LockoutPolicy{
+		BackoffAfter:    5,
+		BaseDelay:       time.Second,
+		LockoutAfter:    10,
+		LockoutDuration: 15 * time.Minute,
+		Window:          15 * time.Minute,
+	}
+	// DefaultIPLockoutPolicy applies to failed logins from one client IP, which
+	// may be shared by many users behind a NAT
+	DefaultIPLockoutPolicy = LockoutPolicy{
+		BackoffAfter:    20,
+		BaseDelay:       time.Second,
+		LockoutAfter:    50,
+		LockoutDuration: 15 * time.Minute,
+		Window:          15 * time.Minute,
+	}
+)
+
+// Delay returns how long to wait after the last of the given number of failures
+func (p LockoutPolicy) Delay(failures int) time.Duration {
+	if p.LockoutAfter > 0 && failures >= p.LockoutAfter {
+		return p.LockoutDuration
+	}
+
+	if p.BackoffAfter <= 0 || failures < p.BackoffAfter {
+		return 0
+	}
+
+	delay := p.BaseDelay
+	for i := p.BackoffAfter; i < failures && delay < p.LockoutDuration; i++ {
+		delay *= 2
+	}
+	if delay > p.LockoutDuration {
+		delay = p.LockoutDuration
+	}
+
+	return delay
+}
+
+// ttl returns how long the store has to keep a counter
+func (p LockoutPolicy) ttl() time.Duration {
+	if p.Window < p.LockoutDuration {
+		return p.LockoutDuration
+	}
+	return p.Window
+}
+
+// LoginThrottle tracks failed logins per account and per client IP
+type LoginThrottle struct {
+	store         repository.LoginAttemptRepository
+	accountPolicy LockoutPolicy
+	ipPolicy      LockoutPolicy
+}
+
+// NewLoginThrottle creates a new login throttle
+func NewLoginThrottle(store repository.LoginAttemptRepository, accountPolicy, ipPolicy LockoutPolicy) *LoginThrottle {
+	return &LoginThrottle{
+		store:         store,
+		accountPolicy: accountPolicy,
+		ipPolicy:      ipPolicy,
+	}
+}
+
+// Check returns a *domain.LoginLockedError if the account or client IP has to wait
+func (t *LoginThrottle) Check(ctx context.Context, email, clientIP string) error {
+	now := time.Now()
+
+	retryAfter, err := t.retryAfter(ctx, accountKey(ctx, email), t.accountPolicy, now)
+	if err != nil {
+		return err
+	}
+
+	if clientIP != "" {
+		ipRetryAfter, err := t.retryAfter(ctx, ipKey(clientIP), t.ipPolicy, now)
+		if err != nil {
+			return err
+		}
+		if ipRetryAfter > retryAfter {
+			retryAfter = ipRetryAfter
+		}
+	}
+
+	if retryAfter > 0 {
+		return &domain.LoginLockedError{RetryAfter: retryAfter}
+	}
+
+	return nil
+}
+
+// RecordFailure counts a failed login against the account and client IP
+func (t *LoginThrottle) RecordFailure(ctx context.Context, email, clientIP string) error {
+	now := time.Now()
+
+	if _, err := t.store.RecordFailure(ctx, accountKey(ctx, email), now, t.accountPolicy.ttl()); err != nil {
+		return err
+	}
+
+	if clientIP == "" {
+		return nil
+	}
+
+	_, err := t.store.RecordFailure(ctx, ipKey(clientIP), now, t.ipPolicy.ttl())
+	return err
+}
+
+// RecordSuccess clears the account's failures. The client IP keeps its count,
+// otherwise one valid account would let an attacker reset it.
+func (t *LoginThrottle) RecordSuccess(ctx context.Context, email string) error {
+	return t.store.Reset(ctx, accountKey(ctx, email))
+}
+
+// retryAfter returns how much longer key has to wait
+func (t *LoginThrottle) retryAfter(ctx context.Context, key string, policy LockoutPolicy, now time.Time) (time.Duration, error) {
+	attempts, err := t.store.Get(ctx, key)
+	if err != nil {
+		return 0, err
+	}
+
+	wait := attempts.LastFailureAt.Add(policy.Delay(attempts.Failures)).Sub(now)
+	if wait < 0 {
+		return 0, nil
+	}
+
+	return wait, nil
+}
+
+// accountKey is per tenant, the same address may belong to users of several tenants
+func accountKey(ctx context.Context, email string) string {
+	tenantID, _ := repository.TenantFromContext(ctx)
+	return "account:" + tenantID + ":" + strings.ToLower(strings.TrimSpace(email))
+}
+
+func ipKey(clientIP string) string {
+	return "ip:" + clientIP
+}
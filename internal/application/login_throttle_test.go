@@ -0,0 +1,143 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/yourusername/userapi/internal/adapters/repository/memory"
+	"github.com/yourusername/userapi/internal/domain"
+	"github.com/yourusername/userapi/internal/ports/repository"
+)
+
+func TestLockoutPolicyDelay(t *testing.T) {
+	policy := LockoutPolicy{
+		BackoffAfter:    3,
+		BaseDelay:       time.Second,
+		LockoutAfter:    10,
+		LockoutDuration: 5 * time.Second,
+	}
+
+	tests := []struct {
+		name     string
+		policy   LockoutPolicy
+		failures int
+		want     time.Duration
+	}{
+		{"no failures", policy, 0, 0},
+		{"before backoff", policy, 2, 0},
+		{"first backoff", policy, 3, time.Second},
+		{"doubles", policy, 4, 2 * time.Second},
+		{"doubles again", policy, 5, 4 * time.Second},
+		{"capped at the lockout duration", policy, 6, 5 * time.Second},
+		{"locked out", policy, 10, 5 * time.Second},
+		{"lockout without backoff", LockoutPolicy{LockoutAfter: 2, LockoutDuration: time.Minute}, 2, time.Minute},
+		{"backoff without lockout", LockoutPolicy{BackoffAfter: 1, BaseDelay: time.Second, LockoutDuration: time.Minute}, 3, 4 * time.Second},
+		{"disabled", LockoutPolicy{}, 100, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.policy.Delay(tt.failures); got != tt.want {
+				t.Errorf("Delay(%d) = %v, want %v", tt.failures, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoginThrottle(t *testing.T) {
+	accountPolicy := LockoutPolicy{LockoutAfter: 3, LockoutDuration: time.Minute, Window: time.Minute}
+	ipPolicy := LockoutPolicy{LockoutAfter: 5, LockoutDuration: time.Minute, Window: time.Minute}
+
+	tenantA := repository.WithTenant(context.Background(), "tenant-a")
+	tenantB := repository.WithTenant(context.Background(), "tenant-b")
+
+	type attempt struct {
+		ctx      context.Context
+		email    string
+		clientIP string
+	}
+
+	tests := []struct {
+		name     string
+		failures []attempt
+		success  *attempt
+		check    attempt
+		wantLock bool
+	}{
+		{
+			name:     "account locked",
+			failures: []attempt{{tenantA, "ada@example.com", "10.0.0.1"}, {tenantA, "ada@example.com", "10.0.0.2"}, {tenantA, "ada@example.com", "10.0.0.3"}},
+			check:    attempt{tenantA, "ada@example.com", "10.0.0.4"},
+			wantLock: true,
+		},
+		{
+			name:     "email compared case insensitively",
+			failures: []attempt{{tenantA, "ada@example.com", ""}, {tenantA, "ADA@example.com", ""}, {tenantA, " Ada@Example.com", ""}},
+			check:    attempt{tenantA, "ada@example.com", ""},
+			wantLock: true,
+		},
+		{
+			name:     "below the limit",
+			failures: []attempt{{tenantA, "ada@example.com", ""}, {tenantA, "ada@example.com", ""}},
+			check:    attempt{tenantA, "ada@example.com", ""},
+		},
+		{
+			name:     "same address in another tenant",
+			failures: []attempt{{tenantA, "ada@example.com", ""}, {tenantA, "ada@example.com", ""}, {tenantA, "ada@example.com", ""}},
+			check:    attempt{tenantB, "ada@example.com", ""},
+		},
+		{
+			name: "client IP locked across accounts",
+			failures: []attempt{
+				{tenantA, "a@example.com", "10.0.0.1"}, {tenantA, "b@example.com", "10.0.0.1"}, {tenantA, "c@example.com", "10.0.0.1"},
+				{tenantB, "d@example.com", "10.0.0.1"}, {tenantB, "e@example.com", "10.0.0.1"},
+			},
+			check:    attempt{tenantA, "f@example.com", "10.0.0.1"},
+			wantLock: true,
+		},
+		{
+			name:     "success clears the account",
+			failures: []attempt{{tenantA, "ada@example.com", "10.0.0.1"}, {tenantA, "ada@example.com", "10.0.0.1"}},
+			success:  &attempt{tenantA, "ada@example.com", "10.0.0.1"},
+			check:    attempt{tenantA, "ada@example.com", "10.0.0.1"},
+		},
+		{
+			name: "success does not clear the client IP",
+			failures: []attempt{
+				{tenantA, "a@example.com", "10.0.0.1"}, {tenantA, "b@example.com", "10.0.0.1"}, {tenantA, "c@example.com", "10.0.0.1"},
+				{tenantA, "d@example.com", "10.0.0.1"}, {tenantA, "e@example.com", "10.0.0.1"},
+			},
+			success:  &attempt{tenantA, "ada@example.com", "10.0.0.1"},
+			check:    attempt{tenantA, "ada@example.com", "10.0.0.1"},
+			wantLock: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			throttle := NewLoginThrottle(memory.NewLoginAttemptRepository(), accountPolicy, ipPolicy)
+
+			for _, a := range tt.failures {
+				if err := throttle.RecordFailure(a.ctx, a.email, a.clientIP); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if tt.success != nil {
+				if err := throttle.RecordSuccess(tt.success.ctx, tt.success.email); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			err := throttle.Check(tt.check.ctx, tt.check.email, tt.check.clientIP)
+			var locked *domain.LoginLockedError
+			if errors.As(err, &locked) != tt.wantLock {
+				t.Fatalf("Check() error = %v, want locked: %v", err, tt.wantLock)
+			}
+			if tt.wantLock && (locked.RetryAfter <= 0 || locked.RetryAfter > time.Minute) {
+				t.Errorf("RetryAfter = %v, want up to a minute", locked.RetryAfter)
+			}
+		})
+	}
+}
@@ -0,0 +1,171 @@
+package application
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/yourusername/userapi/internal/domain"
+	"github.com/yourusername/userapi/internal/ports/mailer"
+	"github.com/yourusername/userapi/internal/ports/repository"
+	"github.com/yourusername/userapi/pkg/auth"
+)
+
+const (
+	// defaultMagicLinkLimit is the number of login links an address may request per window
+	defaultMagicLinkLimit = 3
+	// defaultMagicLinkWindow is the window defaultMagicLinkLimit applies to
+	defaultMagicLinkWindow = 15 * time.Minute
+)
+
+// MagicLinkService signs users in with a one-time link sent by email
+type MagicLinkService struct {
+	userRepo    repository.UserRepository
+	linkRepo    repository.MagicLinkRepository
+	limiter     repository.LoginAttemptRepository
+	authService *AuthService
+	mailer      mailer.Mailer
+	tokenTTL    time.Duration
+	loginURL    string
+	limit       int
+	window      time.Duration
+}
+
+// MagicLinkOption enables optional MagicLinkService behaviour
+type MagicLinkOption func(*MagicLinkService)
+
+// WithMagicLinkRateLimit allows limit login links per address within window
+func WithMagicLinkRateLimit(limit int, window time.Duration) MagicLinkOption {
+	return func(s *MagicLinkService) {
+		s.limit = limit
+		s.window = window
+	}
+}
+
+// NewMagicLinkService creates a new magic link service. Requests are counted
+// per address in limiter, which can be the store used by LoginThrottle.
+// loginURL is the frontend page that receives the token as a "token" query parameter.
+func NewMagicLinkService(userRepo repository.UserRepository, linkRepo repository.MagicLinkRepository, limiter repository.LoginAttemptRepository, authService *AuthService, mailer mailer.Mailer, tokenTTL time.Duration, loginURL string, opts ...MagicLinkOption) *MagicLinkService {
+	s := &MagicLinkService{
+		userRepo:    userRepo,
+		linkRepo:    linkRepo,
+		limiter:     limiter,
+		authService: authService,
+		mailer:      mailer,
+		tokenTTL:    tokenTTL,
+		loginURL:    loginURL,
+		limit:       defaultMagicLinkLimit,
+		window:      defaultMagicLinkWindow,
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// RequestLink emails a login link if the address belongs to a user and
+// returns the nonce the link is bound to. The caller hands the nonce to the
+// requesting browser, e.g. in a cookie. It behaves the same whether or not
+// the user exists so it cannot be used to find out which addresses are registered.
+func (s *MagicLinkService) RequestLink(ctx context.Context, email string) (string, error) {
+	// Count every address, known or not, so the limit reveals nothing either
+	attempts, err := s.limiter.RecordFailure(ctx, magicLinkKey(ctx, email), time.Now(), s.window)
+	if err != nil {
+		return "", err
+	}
+	if attempts.Failures > s.limit {
+		return "", domain.ErrTooManyMagicLinks
+	}
+
+	// Generate the tokens up front so both paths do similar work
+	token, err := auth.GenerateOpaqueToken()
+	if err != nil {
+		return "", err
+	}
+	nonce, err := auth.GenerateOpaqueToken()
+	if err != nil {
+		return "", err
+	}
+
+	user, err := s.userRepo.FindByEmail(ctx, email)
+	if err != nil {
+		if err == domain.ErrUserNotFound {
+			return nonce, nil
+		}
+		return "", err
+	}
+
+	stored := domain.NewMagicLinkToken(user.ID, auth.HashOpaqueToken(token), auth.HashOpaqueToken(nonce), s.tokenTTL)
+	if err := s.linkRepo.Create(ctx, stored); err != nil {
+		return "", err
+	}
+
+	// Send in the background so response time does not reveal whether the user exists
+	msg := mailer.Message{
+		To:      user.Email,
+		Subject: "Your login link",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nUse the link below to log in. It expires in %s, can only be used once and only works in the browser you requested it from.\n\n%s\n\nIf you did not ask for this, you can ignore this email.",
+			user.Name, s.tokenTTL, linkWithToken(s.loginURL, token),
+		),
+	}
+	go func() {
+		if err := s.mailer.Send(context.WithoutCancel(ctx), msg); err != nil {
+			log.Printf("Failed to send login link email: %v", err)
+		}
+	}()
+
+	return nonce, nil
+}
+
+// Redeem consumes a login link presented together with the nonce of the
+// browser that requested it, and logs the user in like a password login
+func (s *MagicLinkService) Redeem(ctx context.Context, token, nonce string) (*LoginResult, error) {
+	stored, err := s.linkRepo.FindByHash(ctx, auth.HashOpaqueToken(token))
+	if err != nil {
+		return nil, err
+	}
+
+	if !stored.IsUsable(time.Now()) {
+		return nil, domain.ErrInvalidToken
+	}
+
+	// A link opened in another browser does not use up the token
+	nonceHash := auth.HashOpaqueToken(nonce)
+	if nonce == "" || subtle.ConstantTimeCompare([]byte(nonceHash), []byte(stored.NonceHash)) != 1 {
+		return nil, domain.ErrInvalidToken
+	}
+
+	// Consume the token before using it so it cannot be replayed concurrently
+	if err := s.linkRepo.MarkUsed(ctx, stored.ID.Hex()); err != nil {
+		return nil, err
+	}
+
+	ctx, user, err := findIssuedUser(ctx, s.userRepo, stored.UserID.Hex())
+	if err != nil {
+		if err == domain.ErrUserNotFound {
+			return nil, domain.ErrInvalidToken
+		}
+		return nil, err
+	}
+
+	// Following the link proves the user controls the address
+	if !user.IsEmailVerified() {
+		user.MarkEmailVerified(time.Now())
+		if err := s.userRepo.Update(ctx, user); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.authService.completeLogin(ctx, user)
+}
+
+func magicLinkKey(ctx context.Context, email string) string {
+	tenantID, _ := repository.TenantFromContext(ctx)
+	return "magic-link:" + tenantID + ":" + strings.ToLower(strings.TrimSpace(email))
+}
@@ -0,0 +1,298 @@
+package application
+
+import (
+	"context"
+	"crypto/subtle"
+	"strings"
+	"time"
+
+	"github.com/yourusername/userapi/internal/domain"
+	"github.com/yourusername/userapi/internal/ports/repository"
+	"github.com/yourusername/userapi/pkg/auth"
+	"github.com/yourusername/userapi/pkg/encryption"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// recoveryCodeCount is the number of recovery codes issued at a time
+	recoveryCodeCount = 10
+	// maxMFAAttempts is the number of wrong codes a challenge accepts before it is discarded
+	maxMFAAttempts = 5
+)
+
+// MFAEnrollment is returned when a user starts enrolling an authenticator app
+type MFAEnrollment struct {
+	Secret string `json:"secret"`
+	URI    string `json:"otpauth_uri"`
+}
+
+// MFAService manages TOTP multi-factor authentication
+type MFAService struct {
+	userRepo      repository.UserRepository
+	challengeRepo repository.MFAChallengeRepository
+	authService   *AuthService
+	cipher        *encryption.Cipher
+	issuer        string
+}
+
+// NewMFAService creates a new MFA service. issuer is the name shown in authenticator apps.
+func NewMFAService(userRepo repository.UserRepository, challengeRepo repository.MFAChallengeRepository, authService *AuthService, cipher *encryption.Cipher, issuer string) *MFAService {
+	return &MFAService{
+		userRepo:      userRepo,
+		challengeRepo: challengeRepo,
+		authService:   authService,
+		cipher:        cipher,
+		issuer:        issuer,
+	}
+}
+
+// BeginEnrollment generates a new TOTP secret for the user to add to their app.
+// MFA is not enabled until the enrollment is confirmed with a code.
+func (s *MFAService) BeginEnrollment(ctx context.Context, userID string) (*MFAEnrollment, error) {
+	user, err := s.loadUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.IsMFAEnabled() {
+		return nil, domain.ErrMFAAlreadyEnabled
+	}
+
+	secret, err := auth.GenerateTOTPSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	encrypted, err := s.cipher.Encrypt([]byte(secret))
+	if err != nil {
+		return nil, err
+	}
+
+	user.MFA = &domain.MFASettings{PendingEncryptedSecret: encrypted}
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return nil, err
+	}
+
+	return &MFAEnrollment{
+		Secret: secret,
+		URI:    auth.TOTPURI(s.issuer, user.Email, secret),
+	}, nil
+}
+
+// ConfirmEnrollment enables MFA once the user proves their app produces valid codes.
+// It returns one-time recovery codes that are never shown again.
+func (s *MFAService) ConfirmEnrollment(ctx context.Context, userID, code string) ([]string, error) {
+	user, err := s.loadUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.IsMFAEnabled() {
+		return nil, domain.ErrMFAAlreadyEnabled
+	}
+	if user.MFA == nil || user.MFA.PendingEncryptedSecret == "" {
+		return nil, domain.ErrMFANotEnrolling
+	}
+
+	secret, err := s.cipher.Decrypt(user.MFA.PendingEncryptedSecret)
+	if err != nil {
+		return nil, err
+	}
+
+	step, ok := auth.ValidateTOTP(string(secret), code, time.Now())
+	if !ok {
+		return nil, domain.ErrInvalidMFACode
+	}
+
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	user.MFA = &domain.MFASettings{
+		Enabled:            true,
+		EnabledAt:          &now,
+		EncryptedSecret:    user.MFA.PendingEncryptedSecret,
+		RecoveryCodeHashes: hashes,
+		LastUsedStep:       step,
+	}
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+// Disable turns MFA off after checking a current code or recovery code
+func (s *MFAService) Disable(ctx context.Context, userID, code string) error {
+	user, err := s.loadUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if !user.IsMFAEnabled() {
+		return domain.ErrMFANotEnabled
+	}
+
+	if err := s.verifyCode(user, code); err != nil {
+		return err
+	}
+
+	user.MFA = nil
+	return s.userRepo.Update(ctx, user)
+}
+
+// RegenerateRecoveryCodes replaces every recovery code after checking a current code
+func (s *MFAService) RegenerateRecoveryCodes(ctx context.Context, userID, code string) ([]string, error) {
+	user, err := s.loadUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !user.IsMFAEnabled() {
+		return nil, domain.ErrMFANotEnabled
+	}
+
+	if err := s.verifyCode(user, code); err != nil {
+		return nil, err
+	}
+
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+
+	user.MFA.RecoveryCodeHashes = hashes
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+// CompleteLogin exchanges an MFA challenge token and a valid code for a token pair.
+// Wrong codes count as failed logins for the user and clientIP, which may be empty.
+func (s *MFAService) CompleteLogin(ctx context.Context, mfaToken, code, clientIP string) (*TokenPair, error) {
+	challenge, err := s.challengeRepo.FindByHash(ctx, auth.HashOpaqueToken(mfaToken))
+	if err != nil {
+		return nil, err
+	}
+
+	if challenge.IsExpired(time.Now()) || challenge.Attempts >= maxMFAAttempts {
+		return nil, domain.ErrInvalidToken
+	}
+
+	ctx, user, err := findIssuedUser(ctx, s.userRepo, challenge.UserID.Hex())
+	if err != nil {
+		if err == domain.ErrUserNotFound {
+			return nil, domain.ErrInvalidToken
+		}
+		return nil, err
+	}
+
+	if !user.IsMFAEnabled() {
+		return nil, domain.ErrInvalidToken
+	}
+
+	throttle := s.authService.throttle
+	if throttle != nil {
+		if err := throttle.Check(ctx, user.Email, clientIP); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.verifyCode(user, code); err != nil {
+		if err == domain.ErrInvalidMFACode {
+			if _, incErr := s.challengeRepo.IncrementAttempts(ctx, challenge.ID.Hex()); incErr != nil {
+				return nil, incErr
+			}
+			if throttle != nil {
+				if failErr := throttle.RecordFailure(ctx, user.Email, clientIP); failErr != nil {
+					return nil, failErr
+				}
+			}
+		}
+		return nil, err
+	}
+
+	// The code may have consumed a recovery code or advanced the replay guard
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return nil, err
+	}
+
+	if err := s.challengeRepo.Delete(ctx, challenge.ID.Hex()); err != nil {
+		return nil, err
+	}
+
+	if err := s.authService.loginSucceeded(ctx, user.Email); err != nil {
+		return nil, err
+	}
+
+	return s.authService.issueTokenPair(ctx, user, primitive.NewObjectID().Hex())
+}
+
+// loadUser loads the user after checking the caller manages their own MFA
+func (s *MFAService) loadUser(ctx context.Context, userID string) (*domain.User, error) {
+	if err := Authorize(ctx, ActionManageMFA, userID); err != nil {
+		return nil, err
+	}
+
+	return s.userRepo.FindByID(ctx, userID)
+}
+
+// verifyCode accepts a TOTP code or consumes a recovery code. The caller must save the user.
+func (s *MFAService) verifyCode(user *domain.User, code string) error {
+	code = strings.TrimSpace(code)
+
+	secret, err := s.cipher.Decrypt(user.MFA.EncryptedSecret)
+	if err != nil {
+		return err
+	}
+
+	if step, ok := auth.ValidateTOTP(string(secret), code, time.Now()); ok {
+		// A code can only be used once
+		if step <= user.MFA.LastUsedStep {
+			return domain.ErrInvalidMFACode
+		}
+		user.MFA.LastUsedStep = step
+		return nil
+	}
+
+	hash := auth.HashOpaqueToken(normalizeRecoveryCode(code))
+	for i, stored := range user.MFA.RecoveryCodeHashes {
+		if subtle.ConstantTimeCompare([]byte(stored), []byte(hash)) == 1 {
+			user.MFA.RecoveryCodeHashes = append(user.MFA.RecoveryCodeHashes[:i], user.MFA.RecoveryCodeHashes[i+1:]...)
+			return nil
+		}
+	}
+
+	return domain.ErrInvalidMFACode
+}
+
+// generateRecoveryCodes returns new recovery codes and their hashes
+func generateRecoveryCodes() ([]string, []string, error) {
+	codes := make([]string, 0, recoveryCodeCount)
+	hashes := make([]string, 0, recoveryCodeCount)
+
+	for i := 0; i < recoveryCodeCount; i++ {
+		token, err := auth.GenerateOpaqueToken()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		// Short enough to type, formatted as xxxxx-xxxxx
+		raw := strings.ToLower(strings.NewReplacer("-", "", "_", "").Replace(token))[:10]
+		code := raw[:5] + "-" + raw[5:]
+
+		codes = append(codes, code)
+		hashes = append(hashes, auth.HashOpaqueToken(normalizeRecoveryCode(code)))
+	}
+
+	return codes, hashes, nil
+}
+
+// normalizeRecoveryCode ignores case and separators in recovery codes
+func normalizeRecoveryCode(code string) string {
+	return strings.ToLower(strings.ReplaceAll(code, "-", ""))
+}
@@ -0,0 +1,271 @@
+package application
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base32"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yourusername/userapi/internal/adapters/hasher"
+	"github.com/yourusername/userapi/internal/adapters/repository/memory"
+	"github.com/yourusername/userapi/internal/domain"
+	"github.com/yourusername/userapi/internal/ports/repository"
+	"github.com/yourusername/userapi/pkg/auth"
+	"github.com/yourusername/userapi/pkg/encryption"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	mfaTestEmail        = "ada@example.com"
+	mfaTestPassword     = "correct horse battery staple"
+	mfaTestRecoveryCode = "abcde-12345"
+)
+
+// newMFATestServices returns services for a user with MFA enabled whose
+// account is locked after three failed logins
+func newMFATestServices(t *testing.T) (context.Context, *AuthService, *MFAService) {
+	t.Helper()
+
+	passwordHasher, err := hasher.New(hasher.Config{Algorithm: hasher.AlgorithmBcrypt, BcryptCost: bcrypt.MinCost})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cipher, err := encryption.NewCipher(make([]byte, 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	users := newUserStore()
+	challenges := newMFAChallengeStore()
+	throttle := NewLoginThrottle(memory.NewLoginAttemptRepository(), LockoutPolicy{
+		LockoutAfter:    3,
+		LockoutDuration: time.Minute,
+		Window:          time.Minute,
+	}, LockoutPolicy{})
+	revocations := memory.NewTokenRevocationRepository()
+	jwtAuth := auth.NewJWTAuth("test-secret", time.Minute, auth.WithRevocationStore(revocations))
+	authService := NewAuthService(users, passwordHasher, newRefreshTokenStore(), revocations, jwtAuth, time.Hour,
+		WithMFAChallenges(challenges, time.Minute),
+		WithLoginThrottle(throttle),
+	)
+	mfaService := NewMFAService(users, challenges, authService, cipher, "test")
+
+	secret, err := auth.GenerateTOTPSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	encrypted, err := cipher.Encrypt([]byte(secret))
+	if err != nil {
+		t.Fatal(err)
+	}
+	hashedPassword, err := passwordHasher.Hash(mfaTestPassword)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	user := domain.NewUser("Ada Lovelace", mfaTestEmail, hashedPassword)
+	user.MarkEmailVerified(time.Now())
+	user.MFA = &domain.MFASettings{
+		Enabled:            true,
+		EncryptedSecret:    encrypted,
+		RecoveryCodeHashes: []string{auth.HashOpaqueToken(normalizeRecoveryCode(mfaTestRecoveryCode))},
+	}
+
+	ctx := repository.WithTenant(context.Background(), primitive.NewObjectID().Hex())
+	if err := users.Create(ctx, user); err != nil {
+		t.Fatal(err)
+	}
+
+	return ctx, authService, mfaService
+}
+
+// passwordStep signs in with the password and returns the MFA token
+func passwordStep(t *testing.T, ctx context.Context, authService *AuthService) string {
+	t.Helper()
+
+	result, err := authService.Login(ctx, mfaTestEmail, mfaTestPassword, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.MFARequired {
+		t.Fatal("login did not ask for the second factor")
+	}
+	return result.MFAToken
+}
+
+func TestMFACodesCountAsFailedLogins(t *testing.T) {
+	tests := []struct {
+		name string
+		code string
+	}{
+		{"wrong TOTP code", "000000"},
+		{"wrong recovery code", "zzzzz-99999"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, authService, mfaService := newMFATestServices(t)
+
+			// A correct password alone does not clear earlier failures
+			for i := 0; i < 2; i++ {
+				if _, err := authService.Login(ctx, mfaTestEmail, "wrong password", ""); err != domain.ErrInvalidCredentials {
+					t.Fatalf("Login() error = %v, want ErrInvalidCredentials", err)
+				}
+			}
+			mfaToken := passwordStep(t, ctx, authService)
+
+			if _, err := mfaService.CompleteLogin(ctx, mfaToken, tt.code, ""); err != domain.ErrInvalidMFACode {
+				t.Fatalf("CompleteLogin() error = %v, want ErrInvalidMFACode", err)
+			}
+
+			var locked *domain.LoginLockedError
+			if _, err := mfaService.CompleteLogin(ctx, mfaToken, mfaTestRecoveryCode, ""); !errors.As(err, &locked) {
+				t.Errorf("CompleteLogin() error = %v, want a locked account", err)
+			}
+			if _, err := authService.Login(ctx, mfaTestEmail, mfaTestPassword, ""); !errors.As(err, &locked) {
+				t.Errorf("Login() error = %v, want a locked account", err)
+			}
+		})
+	}
+}
+
+func TestMFALoginClearsFailedLogins(t *testing.T) {
+	ctx, authService, mfaService := newMFATestServices(t)
+
+	for i := 0; i < 2; i++ {
+		if _, err := authService.Login(ctx, mfaTestEmail, "wrong password", ""); err != domain.ErrInvalidCredentials {
+			t.Fatalf("Login() error = %v, want ErrInvalidCredentials", err)
+		}
+	}
+
+	if _, err := mfaService.CompleteLogin(ctx, passwordStep(t, ctx, authService), mfaTestRecoveryCode, ""); err != nil {
+		t.Fatalf("CompleteLogin() error = %v", err)
+	}
+
+	// Had the failures been kept, this one would lock the account
+	if _, err := authService.Login(ctx, mfaTestEmail, "wrong password", ""); err != domain.ErrInvalidCredentials {
+		t.Fatalf("Login() error = %v, want ErrInvalidCredentials", err)
+	}
+	passwordStep(t, ctx, authService)
+}
+
+// currentTOTPCode computes the code an authenticator app shows for secret now
+func currentTOTPCode(t *testing.T, secret string) (string, int64) {
+	t.Helper()
+
+	key, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	step := time.Now().Unix() / 30
+
+	var msg [8]byte
+	binary.BigEndian.PutUint64(msg[:], uint64(step))
+	mac := hmac.New(sha1.New, key)
+	mac.Write(msg[:])
+	sum := mac.Sum(nil)
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	return fmt.Sprintf("%06d", value%1000000), step
+}
+
+func TestVerifyCode(t *testing.T) {
+	cipher, err := encryption.NewCipher(make([]byte, 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+	service := NewMFAService(nil, nil, nil, cipher, "test")
+
+	secret, err := auth.GenerateTOTPSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	encrypted, err := cipher.Encrypt([]byte(secret))
+	if err != nil {
+		t.Fatal(err)
+	}
+	code, step := currentTOTPCode(t, secret)
+
+	tests := []struct {
+		name          string
+		code          string
+		lastUsedStep  int64
+		wantErr       error
+		wantStep      int64
+		wantRecovered int
+	}{
+		{name: "current code", code: code, wantStep: step, wantRecovered: 2},
+		{name: "code with spaces around", code: " " + code + " ", wantStep: step, wantRecovered: 2},
+		{name: "replayed code", code: code, lastUsedStep: step, wantErr: domain.ErrInvalidMFACode, wantStep: step, wantRecovered: 2},
+		{name: "recovery code", code: mfaTestRecoveryCode, wantRecovered: 1},
+		{name: "recovery code in capitals without dash", code: "ABCDE12345", wantRecovered: 1},
+		{name: "unknown code", code: "zzzzz-99999", wantErr: domain.ErrInvalidMFACode, wantRecovered: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := domain.NewUser("Ada Lovelace", mfaTestEmail, "hash")
+			user.MFA = &domain.MFASettings{
+				Enabled:         true,
+				EncryptedSecret: encrypted,
+				RecoveryCodeHashes: []string{
+					auth.HashOpaqueToken(normalizeRecoveryCode("fghij-67890")),
+					auth.HashOpaqueToken(normalizeRecoveryCode(mfaTestRecoveryCode)),
+				},
+				LastUsedStep: tt.lastUsedStep,
+			}
+
+			if err := service.verifyCode(user, tt.code); err != tt.wantErr {
+				t.Fatalf("verifyCode() error = %v, want %v", err, tt.wantErr)
+			}
+			if user.MFA.LastUsedStep != tt.wantStep {
+				t.Errorf("last used step = %d, want %d", user.MFA.LastUsedStep, tt.wantStep)
+			}
+			if len(user.MFA.RecoveryCodeHashes) != tt.wantRecovered {
+				t.Errorf("%d recovery codes left, want %d", len(user.MFA.RecoveryCodeHashes), tt.wantRecovered)
+			}
+
+			// Recovery codes are used up
+			if tt.wantErr == nil && tt.wantStep == 0 {
+				if err := service.verifyCode(user, tt.code); err != domain.ErrInvalidMFACode {
+					t.Errorf("second use of a recovery code error = %v, want ErrInvalidMFACode", err)
+				}
+			}
+		})
+	}
+}
+
+func TestGenerateRecoveryCodes(t *testing.T) {
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(codes) != recoveryCodeCount || len(hashes) != recoveryCodeCount {
+		t.Fatalf("got %d codes and %d hashes, want %d", len(codes), len(hashes), recoveryCodeCount)
+	}
+
+	format := regexp.MustCompile(`^[a-z0-9]{5}-[a-z0-9]{5}$`)
+	seen := make(map[string]bool)
+	for i, code := range codes {
+		if !format.MatchString(code) {
+			t.Errorf("code %q is not formatted as xxxxx-xxxxx", code)
+		}
+		if seen[code] {
+			t.Errorf("code %q issued twice", code)
+		}
+		seen[code] = true
+
+		if hashes[i] != auth.HashOpaqueToken(normalizeRecoveryCode(strings.ToUpper(code))) {
+			t.Errorf("hash of %q does not match the code typed in capitals", code)
+		}
+	}
+}
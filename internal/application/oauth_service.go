@@ -0,0 +1,517 @@
+package application
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/yourusername/userapi/internal/domain"
+	"github.com/yourusername/userapi/internal/ports/repository"
+	"github.com/yourusername/userapi/pkg/auth"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// OAuth2 error codes (RFC 6749 sections 4.1.2.1 and 5.2)
+const (
+	OAuthErrInvalidRequest          = "invalid_request"
+	OAuthErrInvalidClient           = "invalid_client"
+	OAuthErrInvalidGrant            = "invalid_grant"
+	OAuthErrUnauthorizedClient      = "unauthorized_client"
+	OAuthErrUnsupportedGrantType    = "unsupported_grant_type"
+	OAuthErrUnsupportedResponseType = "unsupported_response_type"
+	OAuthErrInvalidScope            = "invalid_scope"
+	OAuthErrAccessDenied            = "access_denied"
+)
+
+// clientIDBytes is the amount of randomness in a generated client ID
+const clientIDBytes = 16
+
+// OAuthClientRegistration describes a client to register
+type OAuthClientRegistration struct {
+	Name         string
+	Public       bool
+	RedirectURIs []string
+	GrantTypes   []string
+	Scopes       []string
+}
+
+// AuthorizeRequest holds the parameters of an authorization request
+type AuthorizeRequest struct {
+	ResponseType        string
+	ClientID            string
+	RedirectURI         string
+	Scope               string
+	State               string
+	CodeChallenge       string
+	CodeChallengeMethod string
+	// Nonce is copied into the ID token of OpenID Connect requests
+	Nonce string
+}
+
+// AuthorizeResult is the outcome of an authorization request. Either the
+// user still has to consent to Scopes, or Code can be sent to RedirectURI.
+type AuthorizeResult struct {
+	ConsentRequired bool     `json:"consent_required"`
+	ClientName      string   `json:"client_name"`
+	Scopes          []string `json:"scopes"`
+	RedirectURI     string   `json:"-"`
+	Code            string   `json:"-"`
+	State           string   `json:"-"`
+}
+
+// TokenRequest holds the parameters of a token request
+type TokenRequest struct {
+	GrantType    string
+	ClientID     string
+	ClientSecret string
+	Code         string
+	RedirectURI  string
+	CodeVerifier string
+	RefreshToken string
+	Scope        string
+}
+
+// OAuthTokenResponse is a successful token response (RFC 6749 section 5.1)
+type OAuthTokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int    `json:"expires_in"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+	Scope        string `json:"scope,omitempty"`
+	IDToken      string `json:"id_token,omitempty"`
+}
+
+// OAuthService is an OAuth2 authorization server supporting the
+// authorization code grant with PKCE, client credentials and refresh tokens
+type OAuthService struct {
+	clientRepo  repository.OAuthClientRepository
+	codeRepo    repository.AuthorizationCodeRepository
+	consentRepo repository.OAuthConsentRepository
+	userRepo    repository.UserRepository
+	authService *AuthService
+	codeTTL     time.Duration
+}
+
+// NewOAuthService creates a new OAuth service
+func NewOAuthService(clientRepo repository.OAuthClientRepository, codeRepo repository.AuthorizationCodeRepository, consentRepo repository.OAuthConsentRepository, userRepo repository.UserRepository, authService *AuthService, codeTTL time.Duration) *OAuthService {
+	return &OAuthService{
+		clientRepo:  clientRepo,
+		codeRepo:    codeRepo,
+		consentRepo: consentRepo,
+		userRepo:    userRepo,
+		authService: authService,
+		codeTTL:     codeTTL,
+	}
+}
+
+// RegisterClient registers a new client. The returned secret is empty for
+// public clients and is not stored, so it cannot be shown again.
+func (s *OAuthService) RegisterClient(ctx context.Context, reg OAuthClientRegistration) (*domain.OAuthClient, string, error) {
+	if err := Authorize(ctx, ActionManageOAuthClients, ""); err != nil {
+		return nil, "", err
+	}
+
+	if len(reg.GrantTypes) == 0 {
+		reg.GrantTypes = []string{domain.GrantAuthorizationCode, domain.GrantRefreshToken}
+	}
+
+	for _, grant := range reg.GrantTypes {
+		switch grant {
+		case domain.GrantAuthorizationCode, domain.GrantRefreshToken:
+		case domain.GrantClientCredentials:
+			if reg.Public {
+				return nil, "", domain.NewOAuthError(OAuthErrInvalidRequest, "public clients cannot use client_credentials")
+			}
+		default:
+			return nil, "", domain.NewOAuthError(OAuthErrInvalidRequest, "unsupported grant type: "+grant)
+		}
+	}
+
+	if containsScope(reg.GrantTypes, domain.GrantAuthorizationCode) && len(reg.RedirectURIs) == 0 {
+		return nil, "", domain.NewOAuthError(OAuthErrInvalidRequest, "authorization_code clients need a redirect URI")
+	}
+
+	for _, scope := range reg.Scopes {
+		if !containsScope(DefaultScopes, scope) && !containsScope(OpenIDScopes, scope) {
+			return nil, "", domain.ErrInvalidScope
+		}
+	}
+
+	clientID, err := newClientID()
+	if err != nil {
+		return nil, "", err
+	}
+
+	client := &domain.OAuthClient{
+		ClientID:     clientID,
+		Name:         reg.Name,
+		Public:       reg.Public,
+		RedirectURIs: reg.RedirectURIs,
+		GrantTypes:   reg.GrantTypes,
+		Scopes:       reg.Scopes,
+		CreatedAt:    time.Now(),
+	}
+
+	var secret string
+	if !reg.Public {
+		secret, err = auth.GenerateOpaqueToken()
+		if err != nil {
+			return nil, "", err
+		}
+		client.SecretHash = auth.HashOpaqueToken(secret)
+	}
+
+	if err := s.clientRepo.Create(ctx, client); err != nil {
+		return nil, "", err
+	}
+
+	return client, secret, nil
+}
+
+// ListClients returns the clients of the caller's tenant
+func (s *OAuthService) ListClients(ctx context.Context) ([]*domain.OAuthClient, error) {
+	if err := Authorize(ctx, ActionManageOAuthClients, ""); err != nil {
+		return nil, err
+	}
+
+	return s.clientRepo.FindAll(ctx)
+}
+
+// ValidateAuthorizeRequest checks an authorization request. An unknown client
+// or redirect URI is reported as domain.ErrOAuthClientNotFound or
+// domain.ErrInvalidRedirectURI, which must not be redirected. Every other
+// problem is a *domain.OAuthError to send back to the redirect URI.
+func (s *OAuthService) ValidateAuthorizeRequest(ctx context.Context, req *AuthorizeRequest) (*domain.OAuthClient, []string, error) {
+	client, err := s.clientRepo.FindByClientID(ctx, req.ClientID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Clients only sign in users of their own tenant
+	if tenantID, ok := repository.TenantFromContext(ctx); ok && tenantID != client.TenantID.Hex() {
+		return nil, nil, domain.ErrOAuthClientNotFound
+	}
+
+	// Default to the only registered redirect URI
+	if req.RedirectURI == "" && len(client.RedirectURIs) == 1 {
+		req.RedirectURI = client.RedirectURIs[0]
+	}
+	if !client.AllowsRedirectURI(req.RedirectURI) {
+		return nil, nil, domain.ErrInvalidRedirectURI
+	}
+
+	if req.ResponseType != "code" {
+		return client, nil, domain.NewOAuthError(OAuthErrUnsupportedResponseType, "only the code response type is supported")
+	}
+	if !client.AllowsGrant(domain.GrantAuthorizationCode) {
+		return client, nil, domain.NewOAuthError(OAuthErrUnauthorizedClient, "client may not use the authorization code grant")
+	}
+
+	// PKCE is required for every client, and only with S256
+	if req.CodeChallenge == "" || req.CodeChallengeMethod != auth.PKCEMethodS256 {
+		return client, nil, domain.NewOAuthError(OAuthErrInvalidRequest, "a S256 code_challenge is required")
+	}
+
+	scopes, err := s.requestedScopes(client, req.Scope)
+	if err != nil {
+		return client, nil, err
+	}
+
+	return client, scopes, nil
+}
+
+// Authorize handles an authorization request for the signed in user. A code
+// is issued if the user already consented to the scopes or approves them now.
+func (s *OAuthService) Authorize(ctx context.Context, userID string, req *AuthorizeRequest, approve bool) (*AuthorizeResult, error) {
+	client, scopes, err := s.ValidateAuthorizeRequest(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	consent, err := s.consentRepo.Find(ctx, userID, client.ClientID)
+	if err != nil && err != domain.ErrConsentNotFound {
+		return nil, err
+	}
+
+	if consent == nil || !consent.Covers(scopes) {
+		if !approve {
+			return &AuthorizeResult{
+				ConsentRequired: true,
+				ClientName:      client.Name,
+				Scopes:          scopes,
+			}, nil
+		}
+
+		consent = &domain.OAuthConsent{
+			UserID:    user.ID,
+			ClientID:  client.ClientID,
+			Scopes:    mergeScopes(consent, scopes),
+			GrantedAt: time.Now(),
+		}
+		if err := s.consentRepo.Save(ctx, consent); err != nil {
+			return nil, err
+		}
+	}
+
+	code, err := auth.GenerateOpaqueToken()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	stored := &domain.AuthorizationCode{
+		CodeHash:            auth.HashOpaqueToken(code),
+		ClientID:            client.ClientID,
+		UserID:              user.ID,
+		RedirectURI:         req.RedirectURI,
+		Scopes:              scopes,
+		CodeChallenge:       req.CodeChallenge,
+		CodeChallengeMethod: req.CodeChallengeMethod,
+		Nonce:               req.Nonce,
+		ExpiresAt:           now.Add(s.codeTTL),
+		CreatedAt:           now,
+	}
+	if err := s.codeRepo.Create(ctx, stored); err != nil {
+		return nil, err
+	}
+
+	return &AuthorizeResult{
+		ClientName:  client.Name,
+		Scopes:      scopes,
+		RedirectURI: req.RedirectURI,
+		Code:        code,
+		State:       req.State,
+	}, nil
+}
+
+// Token handles a token request
+func (s *OAuthService) Token(ctx context.Context, req *TokenRequest) (*OAuthTokenResponse, error) {
+	client, err := s.authenticateClient(ctx, req.ClientID, req.ClientSecret)
+	if err != nil {
+		return nil, err
+	}
+
+	if !client.AllowsGrant(req.GrantType) {
+		switch req.GrantType {
+		case domain.GrantAuthorizationCode, domain.GrantClientCredentials, domain.GrantRefreshToken:
+			return nil, domain.NewOAuthError(OAuthErrUnauthorizedClient, "client may not use this grant type")
+		default:
+			return nil, domain.NewOAuthError(OAuthErrUnsupportedGrantType, "")
+		}
+	}
+
+	switch req.GrantType {
+	case domain.GrantAuthorizationCode:
+		return s.exchangeCode(ctx, client, req)
+	case domain.GrantClientCredentials:
+		return s.clientCredentials(client, req)
+	default:
+		return s.refreshToken(ctx, client, req)
+	}
+}
+
+// exchangeCode redeems an authorization code
+func (s *OAuthService) exchangeCode(ctx context.Context, client *domain.OAuthClient, req *TokenRequest) (*OAuthTokenResponse, error) {
+	invalidGrant := domain.NewOAuthError(OAuthErrInvalidGrant, "authorization code is invalid or expired")
+
+	code, err := s.codeRepo.FindByHash(ctx, auth.HashOpaqueToken(req.Code))
+	if err != nil {
+		if err == domain.ErrInvalidToken {
+			return nil, invalidGrant
+		}
+		return nil, err
+	}
+
+	if !code.IsUsable(time.Now()) || code.ClientID != client.ClientID || code.RedirectURI != req.RedirectURI {
+		return nil, invalidGrant
+	}
+
+	if !auth.VerifyPKCE(req.CodeVerifier, code.CodeChallenge) {
+		return nil, domain.NewOAuthError(OAuthErrInvalidGrant, "code_verifier does not match the code challenge")
+	}
+
+	if err := s.codeRepo.MarkUsed(ctx, code.ID.Hex()); err != nil {
+		if err == domain.ErrInvalidToken {
+			return nil, invalidGrant
+		}
+		return nil, err
+	}
+
+	ctx, user, err := findIssuedUser(ctx, s.userRepo, code.UserID.Hex())
+	if err != nil {
+		if err == domain.ErrUserNotFound {
+			return nil, invalidGrant
+		}
+		return nil, err
+	}
+
+	if !user.IsActive() || user.TenantID != client.TenantID {
+		return nil, invalidGrant
+	}
+
+	pair, err := s.authService.issueTokens(ctx, user, primitive.NewObjectID().Hex(), client.ClientID, code.Scopes)
+	if err != nil {
+		return nil, err
+	}
+
+	scopes := s.authService.grantedScopes(user, code.Scopes)
+	response := s.tokenResponse(pair, scopes)
+
+	if containsScope(scopes, ScopeOpenID) {
+		info := newUserInfo(user, scopes)
+		response.IDToken, err = s.authService.jwtAuth.GenerateIDToken(info.Subject, client.ClientID, &auth.IDTokenClaims{
+			Name:          info.Name,
+			Email:         info.Email,
+			EmailVerified: info.EmailVerified,
+			Nonce:         code.Nonce,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return response, nil
+}
+
+// clientCredentials issues an access token for the client itself. No
+// refresh token is issued, the client can simply ask again.
+func (s *OAuthService) clientCredentials(client *domain.OAuthClient, req *TokenRequest) (*OAuthTokenResponse, error) {
+	if client.Public {
+		return nil, domain.NewOAuthError(OAuthErrUnauthorizedClient, "public clients cannot use client_credentials")
+	}
+
+	scopes, err := s.requestedScopes(client, req.Scope)
+	if err != nil {
+		return nil, err
+	}
+
+	// There is no user to release identity claims about, so OpenID scopes
+	// are left out of the grant
+	granted := []string{}
+	for _, scope := range scopes {
+		if !containsScope(OpenIDScopes, scope) {
+			granted = append(granted, scope)
+		}
+	}
+	if len(granted) == 0 {
+		return nil, domain.NewOAuthError(OAuthErrInvalidScope, "OpenID scopes need a user")
+	}
+	scopes = granted
+
+	accessToken, err := s.authService.jwtAuth.GenerateToken(&auth.Claims{
+		TenantID: client.TenantID.Hex(),
+		ClientID: client.ClientID,
+		Scope:    strings.Join(scopes, " "),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.tokenResponse(&TokenPair{AccessToken: accessToken}, scopes), nil
+}
+
+// refreshToken rotates a refresh token issued to the client
+func (s *OAuthService) refreshToken(ctx context.Context, client *domain.OAuthClient, req *TokenRequest) (*OAuthTokenResponse, error) {
+	pair, err := s.authService.RefreshForClient(ctx, req.RefreshToken, client.ClientID)
+	if err != nil {
+		if err == domain.ErrInvalidToken || err == domain.ErrRefreshTokenReused {
+			return nil, domain.NewOAuthError(OAuthErrInvalidGrant, err.Error())
+		}
+		return nil, err
+	}
+
+	return s.tokenResponse(pair, nil), nil
+}
+
+// authenticateClient checks the client's credentials. Public clients only
+// identify themselves.
+func (s *OAuthService) authenticateClient(ctx context.Context, clientID, clientSecret string) (*domain.OAuthClient, error) {
+	invalidClient := domain.NewOAuthError(OAuthErrInvalidClient, "client authentication failed")
+
+	if clientID == "" {
+		return nil, invalidClient
+	}
+
+	client, err := s.clientRepo.FindByClientID(ctx, clientID)
+	if err != nil {
+		if err == domain.ErrOAuthClientNotFound {
+			return nil, invalidClient
+		}
+		return nil, err
+	}
+
+	if client.Public {
+		return client, nil
+	}
+
+	hash := auth.HashOpaqueToken(clientSecret)
+	if clientSecret == "" || subtle.ConstantTimeCompare([]byte(hash), []byte(client.SecretHash)) != 1 {
+		return nil, invalidClient
+	}
+
+	return client, nil
+}
+
+// requestedScopes parses a scope parameter. An empty parameter requests
+// every scope the client is allowed.
+func (s *OAuthService) requestedScopes(client *domain.OAuthClient, scope string) ([]string, error) {
+	scopes := strings.Fields(scope)
+	if len(scopes) == 0 {
+		scopes = client.Scopes
+	}
+
+	for _, scope := range scopes {
+		if !client.AllowsScope(scope) {
+			return nil, domain.NewOAuthError(OAuthErrInvalidScope, "scope not allowed for this client: "+scope)
+		}
+	}
+
+	if len(scopes) == 0 {
+		return nil, domain.NewOAuthError(OAuthErrInvalidScope, "no scope requested")
+	}
+
+	return scopes, nil
+}
+
+// tokenResponse builds a token response. scopes is nil when the granted
+// scopes did not change from the previous token.
+func (s *OAuthService) tokenResponse(pair *TokenPair, scopes []string) *OAuthTokenResponse {
+	return &OAuthTokenResponse{
+		AccessToken:  pair.AccessToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    int(s.authService.jwtAuth.Expiry().Seconds()),
+		RefreshToken: pair.RefreshToken,
+		Scope:        strings.Join(scopes, " "),
+	}
+}
+
+// mergeScopes adds scopes to those of an existing consent
+func mergeScopes(consent *domain.OAuthConsent, scopes []string) []string {
+	var merged []string
+	if consent != nil {
+		merged = append(merged, consent.Scopes...)
+	}
+	for _, scope := range scopes {
+		if !containsScope(merged, scope) {
+			merged = append(merged, scope)
+		}
+	}
+	return merged
+}
+
+// newClientID creates a random public client identifier
+func newClientID() (string, error) {
+	b := make([]byte, clientIDBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
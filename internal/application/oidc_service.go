@@ -0,0 +1,113 @@
+package application
+
+import (
+	"context"
+	"strings"
+
+	"github.com/yourusername/userapi/internal/domain"
+	"github.com/yourusername/userapi/pkg/auth"
+)
+
+// Paths of the endpoints advertised in the discovery document
+const (
+	authorizationPath = "/oauth/authorize"
+	tokenPath         = "/oauth/token"
+	userInfoPath      = "/userinfo"
+	jwksPath          = "/.well-known/jwks.json"
+)
+
+// ProviderMetadata is the OpenID Connect discovery document
+type ProviderMetadata struct {
+	Issuer                            string   `json:"issuer"`
+	AuthorizationEndpoint             string   `json:"authorization_endpoint"`
+	TokenEndpoint                     string   `json:"token_endpoint"`
+	UserInfoEndpoint                  string   `json:"userinfo_endpoint"`
+	JWKSURI                           string   `json:"jwks_uri"`
+	ScopesSupported                   []string `json:"scopes_supported"`
+	ResponseTypesSupported            []string `json:"response_types_supported"`
+	GrantTypesSupported               []string `json:"grant_types_supported"`
+	SubjectTypesSupported             []string `json:"subject_types_supported"`
+	IDTokenSigningAlgValuesSupported  []string `json:"id_token_signing_alg_values_supported"`
+	TokenEndpointAuthMethodsSupported []string `json:"token_endpoint_auth_methods_supported"`
+	CodeChallengeMethodsSupported     []string `json:"code_challenge_methods_supported"`
+	ClaimsSupported                   []string `json:"claims_supported"`
+}
+
+// UserInfo holds the standard claims about a user. Name needs the profile
+// scope, Email and EmailVerified need the email scope.
+type UserInfo struct {
+	Subject       string `json:"sub"`
+	Name          string `json:"name,omitempty"`
+	Email         string `json:"email,omitempty"`
+	EmailVerified *bool  `json:"email_verified,omitempty"`
+}
+
+// OIDCService exposes the OpenID Connect provider metadata and user info.
+// ID tokens are issued by OAuthService when the openid scope is granted.
+type OIDCService struct {
+	userService *UserService
+	jwtAuth     *auth.JWTAuth
+}
+
+// NewOIDCService creates a new OpenID Connect service. The issuer configured
+// on jwtAuth must be the public base URL of this service, e.g.
+// https://auth.example.com, since endpoints are advertised relative to it.
+func NewOIDCService(userService *UserService, jwtAuth *auth.JWTAuth) *OIDCService {
+	return &OIDCService{
+		userService: userService,
+		jwtAuth:     jwtAuth,
+	}
+}
+
+// Discovery returns the provider metadata served at
+// /.well-known/openid-configuration
+func (s *OIDCService) Discovery() *ProviderMetadata {
+	issuer := strings.TrimSuffix(s.jwtAuth.Issuer(), "/")
+
+	var scopes []string
+	scopes = append(scopes, OpenIDScopes...)
+	scopes = append(scopes, DefaultScopes...)
+
+	return &ProviderMetadata{
+		Issuer:                            s.jwtAuth.Issuer(),
+		AuthorizationEndpoint:             issuer + authorizationPath,
+		TokenEndpoint:                     issuer + tokenPath,
+		UserInfoEndpoint:                  issuer + userInfoPath,
+		JWKSURI:                           issuer + jwksPath,
+		ScopesSupported:                   scopes,
+		ResponseTypesSupported:            []string{"code"},
+		GrantTypesSupported:               []string{domain.GrantAuthorizationCode, domain.GrantClientCredentials, domain.GrantRefreshToken},
+		SubjectTypesSupported:             []string{"public"},
+		IDTokenSigningAlgValuesSupported:  []string{s.jwtAuth.SigningAlgorithm()},
+		TokenEndpointAuthMethodsSupported: []string{"client_secret_basic", "client_secret_post", "none"},
+		CodeChallengeMethodsSupported:     []string{auth.PKCEMethodS256},
+		ClaimsSupported:                   []string{"sub", "iss", "aud", "exp", "iat", "nonce", "name", "email", "email_verified"},
+	}
+}
+
+// UserInfo returns the claims about the user that the granted scopes release
+func (s *OIDCService) UserInfo(ctx context.Context, userID string, scopes []string) (*UserInfo, error) {
+	user, err := s.userService.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return newUserInfo(user, scopes), nil
+}
+
+// newUserInfo collects the claims about user released by scopes
+func newUserInfo(user *domain.User, scopes []string) *UserInfo {
+	info := &UserInfo{Subject: user.ID.Hex()}
+
+	if containsScope(scopes, ScopeProfile) {
+		info.Name = user.Name
+	}
+
+	if containsScope(scopes, ScopeEmail) {
+		verified := user.IsEmailVerified()
+		info.Email = user.Email
+		info.EmailVerified = &verified
+	}
+
+	return info
+}
@@ -0,0 +1,129 @@
+package application
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/yourusername/userapi/internal/domain"
+	"github.com/yourusername/userapi/pkg/breached"
+)
+
+// Password policy rules reported in domain.PasswordViolation
+const (
+	RuleMinLength    = "min_length"
+	RuleUppercase    = "uppercase"
+	RuleLowercase    = "lowercase"
+	RuleDigit        = "digit"
+	RuleSymbol       = "symbol"
+	RulePersonalInfo = "personal_info"
+	RuleBreached     = "breached"
+)
+
+// minPersonalInfoLength is the shortest name or email part looked for in a password
+const minPersonalInfoLength = 3
+
+// PasswordBlocklist holds passwords that must not be used
+type PasswordBlocklist interface {
+	Contains(password string) bool
+}
+
+// PasswordPolicy is checked whenever a user sets a password
+type PasswordPolicy struct {
+	MinLength     int
+	RequireUpper  bool
+	RequireLower  bool
+	RequireDigit  bool
+	RequireSymbol bool
+	// ForbidPersonalInfo rejects passwords containing the user's name or email
+	ForbidPersonalInfo bool
+	// Blocklist rejects known breached passwords. Nil disables the check.
+	Blocklist PasswordBlocklist
+}
+
+// DefaultPasswordPolicy requires 8 characters, no personal information and
+// no password from the bundled breached password list
+func DefaultPasswordPolicy() *PasswordPolicy {
+	return &PasswordPolicy{
+		MinLength:          8,
+		ForbidPersonalInfo: true,
+		Blocklist:          breached.Default(),
+	}
+}
+
+// Check returns a *domain.WeakPasswordError listing every rule the password
+// does not meet, or nil if it meets them all
+func (p *PasswordPolicy) Check(password, name, email string) error {
+	var violations []domain.PasswordViolation
+	violate := func(rule, message string) {
+		violations = append(violations, domain.PasswordViolation{Rule: rule, Message: message})
+	}
+
+	if len([]rune(password)) < p.MinLength {
+		violate(RuleMinLength, fmt.Sprintf("must be at least %d characters long", p.MinLength))
+	}
+
+	var upper, lower, digit, symbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsDigit(r):
+			digit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r) || unicode.IsSpace(r):
+			symbol = true
+		}
+	}
+
+	if p.RequireUpper && !upper {
+		violate(RuleUppercase, "must contain an uppercase letter")
+	}
+	if p.RequireLower && !lower {
+		violate(RuleLowercase, "must contain a lowercase letter")
+	}
+	if p.RequireDigit && !digit {
+		violate(RuleDigit, "must contain a digit")
+	}
+	if p.RequireSymbol && !symbol {
+		violate(RuleSymbol, "must contain a symbol")
+	}
+
+	if p.ForbidPersonalInfo && containsPersonalInfo(password, name, email) {
+		violate(RulePersonalInfo, "must not contain your name or email address")
+	}
+
+	if p.Blocklist != nil && p.Blocklist.Contains(password) {
+		violate(RuleBreached, "has appeared in a data breach, choose another")
+	}
+
+	if len(violations) > 0 {
+		return &domain.WeakPasswordError{Violations: violations}
+	}
+
+	return nil
+}
+
+// containsPersonalInfo reports whether password contains the name, a word
+// of the name, the email address or its local part
+func containsPersonalInfo(password, name, email string) bool {
+	lowered := strings.ToLower(password)
+
+	candidates := strings.Fields(strings.ToLower(name))
+	candidates = append(candidates, strings.ToLower(strings.Join(candidates, "")))
+
+	email = strings.ToLower(strings.TrimSpace(email))
+	candidates = append(candidates, email)
+	if at := strings.LastIndex(email, "@"); at > 0 {
+		candidates = append(candidates, email[:at])
+	}
+
+	for _, c := range candidates {
+		if len(c) >= minPersonalInfoLength && strings.Contains(lowered, c) {
+			return true
+		}
+	}
+
+	return false
+}
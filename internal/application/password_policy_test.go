@@ -0,0 +1,77 @@
+package application
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/yourusername/userapi/internal/domain"
+	"github.com/yourusername/userapi/pkg/breached"
+)
+
+func TestPasswordPolicyCheck(t *testing.T) {
+	blocklist, err := breached.Load(strings.NewReader("# test list\nhunter2hunter2\n\nletmein-please\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	strict := &PasswordPolicy{
+		MinLength:          10,
+		RequireUpper:       true,
+		RequireLower:       true,
+		RequireDigit:       true,
+		RequireSymbol:      true,
+		ForbidPersonalInfo: true,
+		Blocklist:          blocklist,
+	}
+
+	tests := []struct {
+		name      string
+		policy    *PasswordPolicy
+		password  string
+		userName  string
+		wantRules []string
+	}{
+		{name: "meets every rule", policy: strict, password: "Tr0ub4dor&3x"},
+		{name: "too short", policy: strict, password: "Ab1!", wantRules: []string{RuleMinLength}},
+		{name: "length counts characters not bytes", policy: &PasswordPolicy{MinLength: 4}, password: "äöüß"},
+		{name: "missing classes", policy: strict, password: "abcdefghijkl", wantRules: []string{RuleUppercase, RuleDigit, RuleSymbol}},
+		{name: "space counts as a symbol", policy: strict, password: "Correct Horse 9"},
+		{name: "contains a word of the name", policy: strict, password: "Lovelace-1815!", wantRules: []string{RulePersonalInfo}},
+		{name: "contains the email local part", policy: strict, password: "X!9ada.lovelace", wantRules: []string{RulePersonalInfo}},
+		{name: "short name parts are ignored", policy: &PasswordPolicy{ForbidPersonalInfo: true}, password: "jo-ed-2024", userName: "Jo Ed"},
+		{name: "personal info allowed", policy: &PasswordPolicy{}, password: "lovelace"},
+		{name: "breached, case insensitive", policy: strict, password: "HUNTER2hunter2", wantRules: []string{RuleSymbol, RuleBreached}},
+		{name: "no blocklist", policy: &PasswordPolicy{}, password: "hunter2hunter2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userName := tt.userName
+			if userName == "" {
+				userName = "Ada Lovelace"
+			}
+
+			err := tt.policy.Check(tt.password, userName, "ada.lovelace@example.com")
+			if tt.wantRules == nil {
+				if err != nil {
+					t.Fatalf("Check() = %v, want nil", err)
+				}
+				return
+			}
+
+			var weak *domain.WeakPasswordError
+			if !errors.As(err, &weak) || !errors.Is(err, domain.ErrWeakPassword) {
+				t.Fatalf("Check() = %v, want a *domain.WeakPasswordError", err)
+			}
+			var rules []string
+			for _, v := range weak.Violations {
+				rules = append(rules, v.Rule)
+			}
+			if !reflect.DeepEqual(rules, tt.wantRules) {
+				t.Errorf("violated rules = %v, want %v", rules, tt.wantRules)
+			}
+		})
+	}
+}
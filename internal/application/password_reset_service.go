@@ -0,0 +1,120 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/yourusername/userapi/internal/domain"
+	"github.com/yourusername/userapi/internal/ports/mailer"
+	"github.com/yourusername/userapi/internal/ports/repository"
+	"github.com/yourusername/userapi/pkg/auth"
+)
+
+// PasswordResetService handles forgotten password recovery
+type PasswordResetService struct {
+	userRepo    repository.UserRepository
+	resetRepo   repository.PasswordResetRepository
+	authService *AuthService
+	mailer      mailer.Mailer
+	tokenTTL    time.Duration
+	resetURL    string
+}
+
+// NewPasswordResetService creates a new password reset service.
+// resetURL is the frontend page that receives the token as a "token" query parameter.
+func NewPasswordResetService(userRepo repository.UserRepository, resetRepo repository.PasswordResetRepository, authService *AuthService, mailer mailer.Mailer, tokenTTL time.Duration, resetURL string) *PasswordResetService {
+	return &PasswordResetService{
+		userRepo:    userRepo,
+		resetRepo:   resetRepo,
+		authService: authService,
+		mailer:      mailer,
+		tokenTTL:    tokenTTL,
+		resetURL:    resetURL,
+	}
+}
+
+// RequestReset emails a reset link if the address belongs to a user.
+// It behaves the same whether or not the user exists so it cannot be used
+// to find out which addresses are registered.
+func (s *PasswordResetService) RequestReset(ctx context.Context, email string) error {
+	// Generate the token up front so both paths do similar work
+	token, err := auth.GenerateOpaqueToken()
+	if err != nil {
+		return err
+	}
+
+	user, err := s.userRepo.FindByEmail(ctx, email)
+	if err != nil {
+		if err == domain.ErrUserNotFound {
+			return nil
+		}
+		return err
+	}
+
+	stored := domain.NewPasswordResetToken(user.ID, auth.HashOpaqueToken(token), s.tokenTTL)
+	if err := s.resetRepo.Create(ctx, stored); err != nil {
+		return err
+	}
+
+	// Send in the background so response time does not reveal whether the user exists
+	msg := mailer.Message{
+		To:      user.Email,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nUse the link below to choose a new password. It expires in %s and can only be used once.\n\n%s\n\nIf you did not ask for this, you can ignore this email.",
+			user.Name, s.tokenTTL, linkWithToken(s.resetURL, token),
+		),
+	}
+	go func() {
+		if err := s.mailer.Send(context.WithoutCancel(ctx), msg); err != nil {
+			log.Printf("Failed to send password reset email: %v", err)
+		}
+	}()
+
+	return nil
+}
+
+// ResetPassword consumes a reset token and sets a new password.
+// Every existing session of the user is revoked.
+func (s *PasswordResetService) ResetPassword(ctx context.Context, token, newPassword string) error {
+	stored, err := s.resetRepo.FindByHash(ctx, auth.HashOpaqueToken(token))
+	if err != nil {
+		return err
+	}
+
+	if !stored.IsUsable(time.Now()) {
+		return domain.ErrInvalidToken
+	}
+
+	ctx, user, err := findIssuedUser(ctx, s.userRepo, stored.UserID.Hex())
+	if err != nil {
+		if err == domain.ErrUserNotFound {
+			return domain.ErrInvalidToken
+		}
+		return err
+	}
+
+	// A rejected password must not use up the token
+	userService := s.authService.userService()
+	if err := userService.checkNewPassword(ctx, user, newPassword); err != nil {
+		return err
+	}
+
+	// Consume the token before using it so it cannot be replayed concurrently
+	if err := s.resetRepo.MarkUsed(ctx, stored.ID.Hex()); err != nil {
+		return err
+	}
+
+	if err := userService.setPassword(ctx, user, newPassword); err != nil {
+		return err
+	}
+
+	// Any other outstanding reset links are no longer needed
+	if err := s.resetRepo.DeleteByUserID(ctx, user.ID.Hex()); err != nil {
+		return err
+	}
+
+	return s.authService.revokeSessions(ctx, user.ID.Hex())
+}
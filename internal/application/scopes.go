@@ -0,0 +1,26 @@
+package application
+
+// Scopes that can be granted to access tokens
+const (
+	ScopeProfile    = "profile"
+	ScopeUsersRead  = "users:read"
+	ScopeUsersWrite = "users:write"
+)
+
+// OpenID Connect scopes. They do not grant API access, they select the
+// identity claims released in ID tokens and by /userinfo.
+const (
+	ScopeOpenID = "openid"
+	ScopeEmail  = "email"
+)
+
+// DefaultScopes are granted to users who log in with a password
+var DefaultScopes = []string{ScopeProfile, ScopeUsersRead, ScopeUsersWrite}
+
+// RestrictedScopes are granted to users who have not verified their email yet.
+// They can still manage their own profile, e.g. to fix a mistyped address.
+var RestrictedScopes = []string{ScopeProfile}
+
+// OpenIDScopes may be requested by OAuth clients on top of DefaultScopes.
+// Every user can grant them, whatever their role or verification status.
+var OpenIDScopes = []string{ScopeOpenID, ScopeEmail}
@@ -0,0 +1,287 @@
+package application
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/yourusername/userapi/internal/domain"
+	"github.com/yourusername/userapi/internal/ports/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// In-memory repositories for the service tests. They keep copies so a test
+// only sees changes that went through the repository.
+
+// userStore is an in-memory UserRepository scoped by the context's tenant
+type userStore struct {
+	mu    sync.Mutex
+	users map[string]*domain.User
+}
+
+func newUserStore() *userStore {
+	return &userStore{users: make(map[string]*domain.User)}
+}
+
+func (s *userStore) visible(ctx context.Context, user *domain.User) bool {
+	if user.IsDeleted() && !repository.IncludesDeleted(ctx) {
+		return false
+	}
+	return s.inTenant(ctx, user)
+}
+
+func (s *userStore) inTenant(ctx context.Context, user *domain.User) bool {
+	if tenantID, ok := repository.TenantFromContext(ctx); ok {
+		return user.TenantID.Hex() == tenantID
+	}
+	return repository.IsAcrossTenants(ctx)
+}
+
+func (s *userStore) Create(ctx context.Context, user *domain.User) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tenantID, ok := repository.TenantFromContext(ctx)
+	if !ok {
+		return domain.ErrTenantRequired
+	}
+	if user.ID.IsZero() {
+		user.ID = primitive.NewObjectID()
+	}
+	user.TenantID, _ = primitive.ObjectIDFromHex(tenantID)
+
+	stored := *user
+	s.users[user.ID.Hex()] = &stored
+	return nil
+}
+
+func (s *userStore) FindByID(ctx context.Context, id string) (*domain.User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	user, ok := s.users[id]
+	if !ok || !s.visible(ctx, user) {
+		return nil, domain.ErrUserNotFound
+	}
+	found := *user
+	return &found, nil
+}
+
+func (s *userStore) FindByEmail(ctx context.Context, email string) (*domain.User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, user := range s.users {
+		if user.Email == email && s.visible(ctx, user) {
+			found := *user
+			return &found, nil
+		}
+	}
+	return nil, domain.ErrUserNotFound
+}
+
+func (s *userStore) FindAll(ctx context.Context) ([]*domain.User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var users []*domain.User
+	for _, user := range s.users {
+		if s.visible(ctx, user) {
+			found := *user
+			users = append(users, &found)
+		}
+	}
+	return users, nil
+}
+
+func (s *userStore) Update(ctx context.Context, user *domain.User) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if stored, ok := s.users[user.ID.Hex()]; !ok || !s.inTenant(ctx, stored) {
+		return domain.ErrUserNotFound
+	}
+	stored := *user
+	s.users[user.ID.Hex()] = &stored
+	return nil
+}
+
+func (s *userStore) Delete(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	user, ok := s.users[id]
+	if !ok || user.IsDeleted() || !s.inTenant(ctx, user) {
+		return domain.ErrUserNotFound
+	}
+	now := time.Now()
+	user.DeletedAt = &now
+	return nil
+}
+
+func (s *userStore) Restore(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	user, ok := s.users[id]
+	if !ok || !user.IsDeleted() || !s.inTenant(ctx, user) {
+		return domain.ErrUserNotFound
+	}
+	user.DeletedAt = nil
+	return nil
+}
+
+func (s *userStore) FindDeletedBefore(ctx context.Context, before time.Time) ([]*domain.User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var users []*domain.User
+	for _, user := range s.users {
+		if user.IsDeleted() && user.DeletedAt.Before(before) {
+			found := *user
+			users = append(users, &found)
+		}
+	}
+	return users, nil
+}
+
+func (s *userStore) Purge(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.users, id)
+	return nil
+}
+
+func (s *userStore) Count(ctx context.Context) (int64, error) {
+	users, _ := s.FindAll(ctx)
+	return int64(len(users)), nil
+}
+
+// refreshTokenStore is an in-memory RefreshTokenRepository
+type refreshTokenStore struct {
+	mu     sync.Mutex
+	tokens map[string]*domain.RefreshToken
+}
+
+func newRefreshTokenStore() *refreshTokenStore {
+	return &refreshTokenStore{tokens: make(map[string]*domain.RefreshToken)}
+}
+
+func (s *refreshTokenStore) Create(ctx context.Context, token *domain.RefreshToken) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	token.ID = primitive.NewObjectID()
+	stored := *token
+	s.tokens[token.TokenHash] = &stored
+	return nil
+}
+
+func (s *refreshTokenStore) FindByHash(ctx context.Context, tokenHash string) (*domain.RefreshToken, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	token, ok := s.tokens[tokenHash]
+	if !ok {
+		return nil, domain.ErrRefreshTokenNotFound
+	}
+	found := *token
+	return &found, nil
+}
+
+func (s *refreshTokenStore) MarkRotated(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, token := range s.tokens {
+		if token.ID.Hex() == id {
+			if token.RotatedAt != nil || token.RevokedAt != nil {
+				return domain.ErrRefreshTokenReused
+			}
+			now := time.Now()
+			token.RotatedAt = &now
+			return nil
+		}
+	}
+	return domain.ErrRefreshTokenNotFound
+}
+
+func (s *refreshTokenStore) RevokeFamily(ctx context.Context, familyID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	for _, token := range s.tokens {
+		if token.FamilyID == familyID && token.RevokedAt == nil {
+			token.RevokedAt = &now
+		}
+	}
+	return nil
+}
+
+func (s *refreshTokenStore) RevokeAllForUser(ctx context.Context, userID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	for _, token := range s.tokens {
+		if token.UserID.Hex() == userID && token.RevokedAt == nil {
+			token.RevokedAt = &now
+		}
+	}
+	return nil
+}
+
+// mfaChallengeStore is an in-memory MFAChallengeRepository
+type mfaChallengeStore struct {
+	mu         sync.Mutex
+	challenges map[string]*domain.MFAChallenge
+}
+
+func newMFAChallengeStore() *mfaChallengeStore {
+	return &mfaChallengeStore{challenges: make(map[string]*domain.MFAChallenge)}
+}
+
+func (s *mfaChallengeStore) Create(ctx context.Context, challenge *domain.MFAChallenge) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	challenge.ID = primitive.NewObjectID()
+	stored := *challenge
+	s.challenges[challenge.ID.Hex()] = &stored
+	return nil
+}
+
+func (s *mfaChallengeStore) FindByHash(ctx context.Context, tokenHash string) (*domain.MFAChallenge, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, challenge := range s.challenges {
+		if challenge.TokenHash == tokenHash {
+			found := *challenge
+			return &found, nil
+		}
+	}
+	return nil, domain.ErrInvalidToken
+}
+
+func (s *mfaChallengeStore) IncrementAttempts(ctx context.Context, id string) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	challenge, ok := s.challenges[id]
+	if !ok {
+		return 0, domain.ErrInvalidToken
+	}
+	challenge.Attempts++
+	return challenge.Attempts, nil
+}
+
+func (s *mfaChallengeStore) Delete(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.challenges, id)
+	return nil
+}
@@ -0,0 +1,64 @@
+package application
+
+import (
+	"context"
+	"strings"
+
+	"github.com/yourusername/userapi/internal/domain"
+	"github.com/yourusername/userapi/internal/ports/repository"
+)
+
+// TenantService manages the organizations hosted on the deployment. Tenants
+// are created by operators, see cmd/tenantctl, not over the API.
+type TenantService struct {
+	tenantRepo repository.TenantRepository
+}
+
+// NewTenantService creates a new tenant service
+func NewTenantService(tenantRepo repository.TenantRepository) *TenantService {
+	return &TenantService{tenantRepo: tenantRepo}
+}
+
+// CreateTenant creates a new tenant
+func (s *TenantService) CreateTenant(ctx context.Context, slug, name string) (*domain.Tenant, error) {
+	slug = strings.ToLower(strings.TrimSpace(slug))
+	if !domain.IsValidTenantSlug(slug) {
+		return nil, domain.ErrInvalidTenantSlug
+	}
+
+	tenant := domain.NewTenant(slug, name)
+	if err := s.tenantRepo.Create(ctx, tenant); err != nil {
+		return nil, err
+	}
+
+	return tenant, nil
+}
+
+// ResolveTenant finds the tenant a request names by its slug
+func (s *TenantService) ResolveTenant(ctx context.Context, slug string) (*domain.Tenant, error) {
+	return s.tenantRepo.FindBySlug(ctx, strings.ToLower(strings.TrimSpace(slug)))
+}
+
+// ListTenants returns every tenant
+func (s *TenantService) ListTenants(ctx context.Context) ([]*domain.Tenant, error) {
+	return s.tenantRepo.FindAll(ctx)
+}
+
+// findIssuedUser loads the user a record issued by this service belongs to,
+// e.g. a refresh token or a password reset link. Such records are found by a
+// secret rather than within a tenant, so the user may be in any tenant; if
+// the request named a tenant, it has to be the user's. The returned context
+// is scoped to the user's tenant for any further work on their behalf.
+func findIssuedUser(ctx context.Context, userRepo repository.UserRepository, userID string) (context.Context, *domain.User, error) {
+	user, err := userRepo.FindByID(repository.AcrossTenants(ctx), userID)
+	if err != nil {
+		return ctx, nil, err
+	}
+
+	tenantID := user.TenantID.Hex()
+	if requested, ok := repository.TenantFromContext(ctx); ok && requested != tenantID {
+		return ctx, nil, domain.ErrUserNotFound
+	}
+
+	return repository.WithTenant(ctx, tenantID), user, nil
+}
@@ -2,59 +2,204 @@ package application
 
 import (
 	"context"
+	"log"
 	"time"
 
 	"github.com/yourusername/userapi/internal/domain"
+	"github.com/yourusername/userapi/internal/ports/hasher"
 	"github.com/yourusername/userapi/internal/ports/repository"
 	"go.mongodb.org/mongo-driver/bson/primitive"
-	"golang.org/x/crypto/bcrypt"
 )
 
 // UserService handles business logic for user operations
 type UserService struct {
 	userRepo repository.UserRepository
+	hasher   hasher.PasswordHasher
+	policy   *PasswordPolicy
+	verifier *EmailVerificationService
+	// history remembers the last historySize previous passwords of each user
+	history     repository.PasswordHistoryRepository
+	historySize int
+	identities  repository.ExternalIdentityRepository
+	groups      repository.GroupRepository
+	// deletedEmails decides whether a deleted user's address stays taken
+	deletedEmails DeletedEmailPolicy
+	// revocations and refreshTokens end a deleted user's sessions
+	revocations   repository.TokenRevocationRepository
+	refreshTokens repository.RefreshTokenRepository
+}
+
+// UserOption enables optional UserService behaviour
+type UserOption func(*UserService)
+
+// DeletedEmailPolicy decides when the email address of a deleted user can be used again
+type DeletedEmailPolicy string
+
+// Deleted email policies
+const (
+	// DeletedEmailReserve keeps the address for the deleted user until they
+	// are purged, so they can always be restored
+	DeletedEmailReserve DeletedEmailPolicy = "reserve"
+	// DeletedEmailRelease frees the address at once. Restoring the user fails
+	// if someone has taken it in the meantime.
+	DeletedEmailRelease DeletedEmailPolicy = "release"
+)
+
+// WithEmailVerifier sends a verification link whenever a user's email is set by an administrator or changed
+func WithEmailVerifier(verifier *EmailVerificationService) UserOption {
+	return func(s *UserService) {
+		s.verifier = verifier
+	}
+}
+
+// WithPasswordPolicy replaces the policy checked whenever a password is set
+func WithPasswordPolicy(policy *PasswordPolicy) UserOption {
+	return func(s *UserService) {
+		s.policy = policy
+	}
+}
+
+// WithPasswordHistory rejects a new password that matches the current one or
+// any of the size passwords before it
+func WithPasswordHistory(history repository.PasswordHistoryRepository, size int) UserOption {
+	return func(s *UserService) {
+		s.history = history
+		s.historySize = size
+	}
+}
+
+// WithExternalIdentities unlinks a user's identity provider accounts when the user is purged
+func WithExternalIdentities(identities repository.ExternalIdentityRepository) UserOption {
+	return func(s *UserService) {
+		s.identities = identities
+	}
+}
+
+// WithGroups removes a user from their groups when the user is purged
+func WithGroups(groups repository.GroupRepository) UserOption {
+	return func(s *UserService) {
+		s.groups = groups
+	}
+}
+
+// WithSessionRevocation signs deleted users out everywhere, like
+// AuthService.DeleteAccount does for users deleting themselves
+func WithSessionRevocation(revocations repository.TokenRevocationRepository, refreshTokens repository.RefreshTokenRepository) UserOption {
+	return func(s *UserService) {
+		s.revocations = revocations
+		s.refreshTokens = refreshTokens
+	}
+}
+
+// WithDeletedEmailPolicy sets when a deleted user's email address can be
+// used again. The default is DeletedEmailReserve.
+func WithDeletedEmailPolicy(policy DeletedEmailPolicy) UserOption {
+	return func(s *UserService) {
+		s.deletedEmails = policy
+	}
 }
 
 // NewUserService creates a new user service
-func NewUserService(userRepo repository.UserRepository) *UserService {
-	return &UserService{userRepo: userRepo}
+func NewUserService(userRepo repository.UserRepository, passwordHasher hasher.PasswordHasher, opts ...UserOption) *UserService {
+	s := &UserService{
+		userRepo: userRepo,
+		hasher:   passwordHasher,
+		policy:   DefaultPasswordPolicy(),
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
 }
 
 // CreateUser creates a new user with hashed password
 func (s *UserService) CreateUser(ctx context.Context, name, email, password string) (*domain.User, error) {
+	user, err := s.newUser(ctx, name, email, password)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create new user
+	if err := s.userRepo.Create(ctx, user); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// newUser checks the email address and password and returns a user that is
+// not stored yet, so callers can set more fields before creating it
+func (s *UserService) newUser(ctx context.Context, name, email, password string) (*domain.User, error) {
 	// Check if user with email already exists
-	existingUser, err := s.userRepo.FindByEmail(ctx, email)
-	if err == nil && existingUser != nil {
-		return nil, domain.ErrEmailAlreadyExists
+	if err := s.checkEmailAvailable(ctx, email); err != nil {
+		return nil, err
+	}
+
+	if err := s.policy.Check(password, name, email); err != nil {
+		return nil, err
 	}
 
 	// Hash password
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	hashedPassword, err := s.hasher.Hash(password)
 	if err != nil {
 		return nil, err
 	}
 
-	// Create new user
-	user := domain.NewUser(name, email, string(hashedPassword))
-	if err := s.userRepo.Create(ctx, user); err != nil {
+	return domain.NewUser(name, email, hashedPassword), nil
+}
+
+// CreateUserWithRole creates a user on behalf of an administrator
+func (s *UserService) CreateUserWithRole(ctx context.Context, name, email, password string, role domain.Role) (*domain.User, error) {
+	if err := Authorize(ctx, ActionCreateUser, ""); err != nil {
 		return nil, err
 	}
 
+	if !role.IsValid() {
+		return nil, domain.ErrInvalidRole
+	}
+
+	user, err := s.CreateUser(ctx, name, email, password)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.Role != role {
+		user.Role = role
+		if err := s.userRepo.Update(ctx, user); err != nil {
+			return nil, err
+		}
+	}
+
+	s.sendVerification(ctx, user)
 	return user, nil
 }
 
 // GetUserByID retrieves a user by ID
 func (s *UserService) GetUserByID(ctx context.Context, id string) (*domain.User, error) {
+	if err := Authorize(ctx, ActionReadUser, id); err != nil {
+		return nil, err
+	}
+
 	return s.userRepo.FindByID(ctx, id)
 }
 
 // GetAllUsers retrieves all users
 func (s *UserService) GetAllUsers(ctx context.Context) ([]*domain.User, error) {
+	if err := Authorize(ctx, ActionListUsers, ""); err != nil {
+		return nil, err
+	}
+
 	return s.userRepo.FindAll(ctx)
 }
 
 // UpdateUser updates a user's information
 func (s *UserService) UpdateUser(ctx context.Context, id, name, email string) (*domain.User, error) {
+	if err := Authorize(ctx, ActionUpdateUser, id); err != nil {
+		return nil, err
+	}
+
 	// Check if user exists
 	user, err := s.userRepo.FindByID(ctx, id)
 	if err != nil {
@@ -63,16 +208,134 @@ func (s *UserService) UpdateUser(ctx context.Context, id, name, email string) (*
 
 	// Check if email is being updated and is unique
 	if email != user.Email {
-		existingUser, err := s.userRepo.FindByEmail(ctx, email)
-		if err == nil && existingUser != nil {
-			return nil, domain.ErrEmailAlreadyExists
+		if err := s.checkEmailAvailable(ctx, email); err != nil {
+			return nil, err
 		}
 	}
 
-	// Update user
+	// Update user. A new email address has to be confirmed again.
+	emailChanged := email != user.Email
 	user.Name = name
-	user.Email = email
+	user.ChangeEmail(email)
+
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return nil, err
+	}
+
+	if emailChanged {
+		s.sendVerification(ctx, user)
+	}
+
+	return user, nil
+}
+
+// ChangePassword replaces a user's password after re-verifying the current one
+func (s *UserService) ChangePassword(ctx context.Context, id, currentPassword, newPassword string) error {
+	if err := Authorize(ctx, ActionChangePassword, id); err != nil {
+		return err
+	}
+
+	user, err := s.userRepo.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	// A user without a password sets one through a password reset
+	if !user.HasPassword() {
+		return domain.ErrInvalidCredentials
+	}
+
+	// Compare password
+	ok, err := s.hasher.Verify(currentPassword, user.Password)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return domain.ErrInvalidCredentials
+	}
+
+	return s.setPassword(ctx, user, newPassword)
+}
+
+// checkNewPassword checks a password the user wants to change to against
+// the password policy and their password history
+func (s *UserService) checkNewPassword(ctx context.Context, user *domain.User, newPassword string) error {
+	if err := s.policy.Check(newPassword, user.Name, user.Email); err != nil {
+		return err
+	}
+
+	var previous []string
+	if user.HasPassword() {
+		previous = append(previous, user.Password)
+	}
+	if s.history != nil && s.historySize > 0 {
+		entries, err := s.history.FindRecent(ctx, user.ID.Hex(), s.historySize)
+		if err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			previous = append(previous, entry.PasswordHash)
+		}
+	}
+
+	for _, hash := range previous {
+		ok, err := s.hasher.Verify(newPassword, hash)
+		if err != nil {
+			return err
+		}
+		if ok {
+			return domain.ErrPasswordReused
+		}
+	}
+
+	return nil
+}
+
+// setPassword hashes and stores a new password without any authorization check
+func (s *UserService) setPassword(ctx context.Context, user *domain.User, newPassword string) error {
+	if err := s.checkNewPassword(ctx, user, newPassword); err != nil {
+		return err
+	}
+
+	// Hash password
+	hashedPassword, err := s.hasher.Hash(newPassword)
+	if err != nil {
+		return err
+	}
+
+	previousHash := user.Password
+	user.Password = hashedPassword
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return err
+	}
+
+	if s.history == nil || s.historySize <= 0 || previousHash == "" {
+		return nil
+	}
+
+	if err := s.history.Add(ctx, domain.NewPasswordHistoryEntry(user.ID, previousHash)); err != nil {
+		return err
+	}
+
+	return s.history.Prune(ctx, user.ID.Hex(), s.historySize)
+}
+
+// ChangeRole assigns a new role to a user. It takes effect with the user's next token.
+func (s *UserService) ChangeRole(ctx context.Context, id string, role domain.Role) (*domain.User, error) {
+	if err := Authorize(ctx, ActionChangeRole, id); err != nil {
+		return nil, err
+	}
+
+	if !role.IsValid() {
+		return nil, domain.ErrInvalidRole
+	}
+
+	user, err := s.userRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
 
+	user.Role = role
 	if err := s.userRepo.Update(ctx, user); err != nil {
 		return nil, err
 	}
@@ -80,12 +343,177 @@ func (s *UserService) UpdateUser(ctx context.Context, id, name, email string) (*
 	return user, nil
 }
 
-// DeleteUser deletes a user
+// DeleteUser deletes a user and, with WithSessionRevocation, ends their
+// sessions. Everything about them is kept so an administrator can restore
+// them, until PurgeDeletedUsers removes it.
 func (s *UserService) DeleteUser(ctx context.Context, id string) error {
-	return s.userRepo.Delete(ctx, id)
+	if err := Authorize(ctx, ActionDeleteUser, id); err != nil {
+		return err
+	}
+
+	// The revocation stores are not tenant scoped, so sessions are only
+	// touched once the scoped delete found the user in the caller's tenant
+	if err := s.userRepo.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	if s.revocations == nil {
+		return nil
+	}
+
+	// Tokens issued later in the same second stay valid, see auth.JWTAuth
+	if err := s.revocations.RevokeAllBefore(ctx, id, time.Now()); err != nil {
+		return err
+	}
+	return s.refreshTokens.RevokeAllForUser(ctx, id)
+}
+
+// RestoreUser undoes the deletion of a user who was not purged yet
+func (s *UserService) RestoreUser(ctx context.Context, id string) (*domain.User, error) {
+	if err := Authorize(ctx, ActionRestoreUser, ""); err != nil {
+		return nil, err
+	}
+
+	if err := s.userRepo.Restore(ctx, id); err != nil {
+		return nil, err
+	}
+
+	return s.userRepo.FindByID(ctx, id)
+}
+
+// ListDeletedUsers retrieves the deleted users that can still be restored
+func (s *UserService) ListDeletedUsers(ctx context.Context) ([]*domain.User, error) {
+	if err := Authorize(ctx, ActionRestoreUser, ""); err != nil {
+		return nil, err
+	}
+
+	users, err := s.userRepo.FindAll(repository.IncludeDeleted(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	deleted := []*domain.User{}
+	for _, user := range users {
+		if user.IsDeleted() {
+			deleted = append(deleted, user)
+		}
+	}
+
+	return deleted, nil
+}
+
+// PurgeDeletedUsers removes the users of every tenant deleted longer than
+// retention ago, together with their password history, identity provider
+// links and group memberships. It returns how many users were purged.
+func (s *UserService) PurgeDeletedUsers(ctx context.Context, retention time.Duration) (int, error) {
+	users, err := s.userRepo.FindDeletedBefore(ctx, time.Now().Add(-retention))
+	if err != nil {
+		return 0, err
+	}
+
+	purged := 0
+	for _, user := range users {
+		if err := s.purgeUser(repository.WithTenant(ctx, user.TenantID.Hex()), user.ID.Hex()); err != nil {
+			return purged, err
+		}
+		purged++
+	}
+
+	return purged, nil
+}
+
+// RunPurge purges deleted users past retention on every tick
+func (s *UserService) RunPurge(ctx context.Context, interval, retention time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			purged, err := s.PurgeDeletedUsers(ctx, retention)
+			if err != nil {
+				log.Printf("Failed to purge deleted users: %v", err)
+			}
+			if purged > 0 {
+				log.Printf("Purged %d deleted users", purged)
+			}
+		}
+	}
+}
+
+// purgeUser removes a deleted user for good. The user record goes last, so
+// a purge that fails halfway is retried on the next run.
+func (s *UserService) purgeUser(ctx context.Context, id string) error {
+	// The user may have been restored since they were found
+	user, err := s.userRepo.FindByID(repository.IncludeDeleted(ctx), id)
+	if err != nil {
+		if err == domain.ErrUserNotFound {
+			return nil
+		}
+		return err
+	}
+	if !user.IsDeleted() {
+		return nil
+	}
+
+	if s.history != nil {
+		if err := s.history.DeleteByUserID(ctx, id); err != nil {
+			return err
+		}
+	}
+
+	if s.identities != nil {
+		if err := s.identities.DeleteByUserID(ctx, id); err != nil {
+			return err
+		}
+	}
+
+	if s.groups != nil {
+		if err := s.groups.RemoveMemberFromAll(ctx, id); err != nil {
+			return err
+		}
+	}
+
+	err = s.userRepo.Purge(ctx, id)
+	if err == domain.ErrUserNotFound {
+		return nil
+	}
+	return err
 }
 
 // CountUsers returns the total number of users
 func (s *UserService) CountUsers(ctx context.Context) (int64, error) {
 	return s.userRepo.Count(ctx)
 }
+
+// checkEmailAvailable returns ErrEmailAlreadyExists if a user has email.
+// Under DeletedEmailReserve, deleted users keep their address until purged.
+func (s *UserService) checkEmailAvailable(ctx context.Context, email string) error {
+	if s.deletedEmails != DeletedEmailRelease {
+		ctx = repository.IncludeDeleted(ctx)
+	}
+
+	_, err := s.userRepo.FindByEmail(ctx, email)
+	if err == nil {
+		return domain.ErrEmailAlreadyExists
+	}
+	if err != domain.ErrUserNotFound {
+		return err
+	}
+
+	return nil
+}
+
+// sendVerification emails a verification link if verification is enabled.
+// The user can ask for a new link, so a mail failure is only logged.
+func (s *UserService) sendVerification(ctx context.Context, user *domain.User) {
+	if s.verifier == nil {
+		return
+	}
+
+	if err := s.verifier.SendVerification(ctx, user); err != nil {
+		log.Printf("Failed to send verification email: %v", err)
+	}
+}
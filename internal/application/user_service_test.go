@@ -0,0 +1,68 @@
+package application
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/yourusername/userapi/internal/adapters/repository/memory"
+	"github.com/yourusername/userapi/internal/domain"
+	"github.com/yourusername/userapi/internal/ports/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestDeleteUserRevokesSessionsInTheCallersTenant(t *testing.T) {
+	tenantA, tenantB := primitive.NewObjectID().Hex(), primitive.NewObjectID().Hex()
+
+	tests := []struct {
+		name         string
+		targetTenant string
+		targetID     string
+		wantErr      error
+		wantRevoked  bool
+	}{
+		{name: "same tenant", targetTenant: tenantA, wantRevoked: true},
+		{name: "other tenant", targetTenant: tenantB, wantErr: domain.ErrUserNotFound},
+		{name: "unknown user", targetTenant: tenantA, targetID: primitive.NewObjectID().Hex(), wantErr: domain.ErrUserNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			users := newUserStore()
+			refreshTokens := newRefreshTokenStore()
+			revocations := memory.NewTokenRevocationRepository()
+			service := NewUserService(users, nil, WithSessionRevocation(revocations, refreshTokens))
+
+			target := domain.NewUser("Grace Hopper", "grace@example.com", "hash")
+			if err := users.Create(repository.WithTenant(context.Background(), tt.targetTenant), target); err != nil {
+				t.Fatal(err)
+			}
+			session := domain.NewRefreshToken(target.ID, "family", "hash", time.Hour)
+			if err := refreshTokens.Create(context.Background(), session); err != nil {
+				t.Fatal(err)
+			}
+
+			targetID := tt.targetID
+			if targetID == "" {
+				targetID = target.ID.Hex()
+			}
+
+			// An administrator of tenant A
+			ctx := repository.WithTenant(context.Background(), tenantA)
+			ctx = WithPrincipal(ctx, &Principal{UserID: primitive.NewObjectID().Hex(), Role: domain.RoleAdmin})
+
+			if err := service.DeleteUser(ctx, targetID); err != tt.wantErr {
+				t.Fatalf("DeleteUser() error = %v, want %v", err, tt.wantErr)
+			}
+
+			stored, _ := refreshTokens.FindByHash(context.Background(), "hash")
+			if stored.IsRevoked() != tt.wantRevoked {
+				t.Errorf("refresh token revoked = %v, want %v", stored.IsRevoked(), tt.wantRevoked)
+			}
+			watermark, _ := revocations.RevokedBefore(context.Background(), targetID)
+			if watermark.IsZero() == tt.wantRevoked {
+				t.Errorf("revocation watermark = %v, want one set: %v", watermark, tt.wantRevoked)
+			}
+		})
+	}
+}
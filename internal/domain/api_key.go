@@ -0,0 +1,31 @@
+package domain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// APIKey is a user-owned credential for machine clients. Only a hash of the
+// key is stored. Prefix is the non-secret start of the key, shown so the
+// owner can tell their keys apart. Empty Scopes means every scope the owner has.
+type APIKey struct {
+	ID         primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	UserID     primitive.ObjectID `json:"user_id" bson:"user_id"`
+	Name       string             `json:"name" bson:"name"`
+	Prefix     string             `json:"prefix" bson:"prefix"`
+	KeyHash    string             `json:"-" bson:"key_hash"`
+	Scopes     []string           `json:"scopes,omitempty" bson:"scopes,omitempty"`
+	ExpiresAt  *time.Time         `json:"expires_at,omitempty" bson:"expires_at,omitempty"`
+	LastUsedAt *time.Time         `json:"last_used_at,omitempty" bson:"last_used_at,omitempty"`
+	RevokedAt  *time.Time         `json:"revoked_at,omitempty" bson:"revoked_at,omitempty"`
+	CreatedAt  time.Time          `json:"created_at" bson:"created_at"`
+}
+
+// IsUsable reports whether the key is neither revoked nor expired
+func (k *APIKey) IsUsable(now time.Time) bool {
+	if k.RevokedAt != nil {
+		return false
+	}
+	return k.ExpiresAt == nil || now.Before(*k.ExpiresAt)
+}
@@ -0,0 +1,35 @@
+package domain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// EmailVerificationToken represents a stored, hashed email verification token.
+// It is bound to the address it was sent to, so it cannot confirm a later address.
+type EmailVerificationToken struct {
+	ID        primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	UserID    primitive.ObjectID `json:"user_id" bson:"user_id"`
+	Email     string             `json:"email" bson:"email"`
+	TokenHash string             `json:"-" bson:"token_hash"`
+	ExpiresAt time.Time          `json:"expires_at" bson:"expires_at"`
+	CreatedAt time.Time          `json:"created_at" bson:"created_at"`
+}
+
+// NewEmailVerificationToken creates a new verification token for an address
+func NewEmailVerificationToken(userID primitive.ObjectID, email, tokenHash string, ttl time.Duration) *EmailVerificationToken {
+	now := time.Now()
+	return &EmailVerificationToken{
+		UserID:    userID,
+		Email:     email,
+		TokenHash: tokenHash,
+		ExpiresAt: now.Add(ttl),
+		CreatedAt: now,
+	}
+}
+
+// IsExpired reports whether the token has passed its expiry time
+func (t *EmailVerificationToken) IsExpired(now time.Time) bool {
+	return !now.Before(t.ExpiresAt)
+}
@@ -4,8 +4,46 @@ import "errors"
 
 // Domain error definitions
 var (
-	ErrUserNotFound      = errors.New("user not found")
-	ErrEmailAlreadyExists = errors.New("email already exists")
-	ErrInvalidCredentials = errors.New("invalid credentials")
-	ErrInvalidToken      = errors.New("invalid token")
+	ErrUserNotFound         = errors.New("user not found")
+	ErrTenantNotFound       = errors.New("tenant not found")
+	ErrTenantRequired       = errors.New("tenant is required")
+	ErrTenantAlreadyExists  = errors.New("tenant already exists")
+	ErrInvalidTenantSlug    = errors.New("tenant slug must be lowercase letters, digits and hyphens")
+	ErrGroupNotFound        = errors.New("group not found")
+	ErrGroupAlreadyExists   = errors.New("a group with this name already exists")
+	ErrInvitationNotFound   = errors.New("invitation not found")
+	ErrInvitationPending    = errors.New("an invitation for this email address is already pending")
+	ErrEmailAlreadyExists   = errors.New("email already exists")
+	ErrInvalidCredentials   = errors.New("invalid credentials")
+	ErrWeakPassword         = errors.New("password does not meet the password policy")
+	ErrPasswordReused       = errors.New("password was used recently, choose another")
+	ErrTooManyLoginAttempts = errors.New("too many failed login attempts")
+	ErrInvalidToken         = errors.New("invalid token")
+	ErrUnauthenticated      = errors.New("authentication required")
+	ErrForbidden            = errors.New("not allowed to perform this action")
+	ErrInvalidRole          = errors.New("invalid role")
+	ErrAccountInactive      = errors.New("account is not active")
+	ErrInvalidStatusChange  = errors.New("invalid account status transition")
+	ErrEmailNotVerified     = errors.New("email address is not verified")
+	ErrEmailAlreadyVerified = errors.New("email address is already verified")
+	ErrVerificationCooldown = errors.New("verification email was sent recently, try again later")
+	ErrTooManyMagicLinks    = errors.New("too many login links requested, try again later")
+	ErrInvalidMFACode       = errors.New("invalid authentication code")
+	ErrMFAAlreadyEnabled    = errors.New("multi-factor authentication is already enabled")
+	ErrMFANotEnabled        = errors.New("multi-factor authentication is not enabled")
+	ErrMFANotEnrolling      = errors.New("multi-factor enrollment has not been started")
+	ErrRefreshTokenNotFound = errors.New("refresh token not found")
+	ErrAPIKeyNotFound       = errors.New("api key not found")
+	ErrOAuthClientNotFound  = errors.New("oauth client not found")
+	ErrInvalidRedirectURI   = errors.New("redirect_uri is not registered for this client")
+	ErrConsentNotFound      = errors.New("consent not found")
+	ErrIdentityNotFound     = errors.New("external identity not found")
+	ErrUnknownProvider      = errors.New("unknown identity provider")
+	ErrIdentityConflict     = errors.New("an account with this email address already exists, sign in with your password")
+	ErrSignupNotAllowed     = errors.New("no account exists for this identity")
+	ErrInvalidScope         = errors.New("unknown scope")
+	ErrRefreshTokenReused   = errors.New("refresh token reuse detected")
+	ErrSigningKeyNotFound   = errors.New("signing key not found")
+	ErrSigningKeyInUse      = errors.New("signing key may still verify unexpired tokens")
+	ErrInvalidKeyTransition = errors.New("invalid signing key transition")
 )
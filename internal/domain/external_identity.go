@@ -0,0 +1,50 @@
+package domain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ExternalIdentity links a user to their account at an upstream identity
+// provider. The provider's "sub" claim is stable, unlike the email address.
+type ExternalIdentity struct {
+	ID          primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	UserID      primitive.ObjectID `json:"user_id" bson:"user_id"`
+	Provider    string             `json:"provider" bson:"provider"`
+	Subject     string             `json:"subject" bson:"subject"`
+	Email       string             `json:"email" bson:"email"`
+	LastLoginAt time.Time          `json:"last_login_at" bson:"last_login_at"`
+	CreatedAt   time.Time          `json:"created_at" bson:"created_at"`
+}
+
+// NewExternalIdentity creates a link between a user and a provider account
+func NewExternalIdentity(userID primitive.ObjectID, provider, subject, email string) *ExternalIdentity {
+	now := time.Now()
+	return &ExternalIdentity{
+		UserID:      userID,
+		Provider:    provider,
+		Subject:     subject,
+		Email:       email,
+		LastLoginAt: now,
+		CreatedAt:   now,
+	}
+}
+
+// FederatedLoginState is kept between redirecting a user to an identity
+// provider and handling the callback. It is found by the hash of the
+// "state" parameter and can be used once.
+type FederatedLoginState struct {
+	ID           primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	StateHash    string             `json:"-" bson:"state_hash"`
+	Provider     string             `json:"provider" bson:"provider"`
+	Nonce        string             `json:"-" bson:"nonce"`
+	CodeVerifier string             `json:"-" bson:"code_verifier"`
+	ExpiresAt    time.Time          `json:"expires_at" bson:"expires_at"`
+	CreatedAt    time.Time          `json:"created_at" bson:"created_at"`
+}
+
+// IsExpired reports whether the state has passed its expiry time
+func (s *FederatedLoginState) IsExpired(now time.Time) bool {
+	return !now.Before(s.ExpiresAt)
+}
@@ -0,0 +1,30 @@
+package domain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Group organizes users of a tenant, e.g. into a team or a department
+type Group struct {
+	ID          primitive.ObjectID   `json:"id" bson:"_id,omitempty"`
+	TenantID    primitive.ObjectID   `json:"tenant_id" bson:"tenant_id"`
+	Name        string               `json:"name" bson:"name"`
+	Description string               `json:"description,omitempty" bson:"description,omitempty"`
+	MemberIDs   []primitive.ObjectID `json:"member_ids" bson:"member_ids"`
+	CreatedAt   time.Time            `json:"created_at" bson:"created_at"`
+	UpdatedAt   time.Time            `json:"updated_at" bson:"updated_at"`
+}
+
+// NewGroup creates a new group without members
+func NewGroup(name, description string) *Group {
+	now := time.Now()
+	return &Group{
+		Name:        name,
+		Description: description,
+		MemberIDs:   []primitive.ObjectID{},
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+}
@@ -0,0 +1,50 @@
+package domain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Invitation lets someone create an account in a tenant with a role and
+// groups chosen by an administrator. Only the hash of the emailed token is stored.
+type Invitation struct {
+	ID         primitive.ObjectID   `json:"id" bson:"_id,omitempty"`
+	TenantID   primitive.ObjectID   `json:"tenant_id" bson:"tenant_id"`
+	Email      string               `json:"email" bson:"email"`
+	Role       Role                 `json:"role" bson:"role"`
+	GroupIDs   []primitive.ObjectID `json:"group_ids,omitempty" bson:"group_ids,omitempty"`
+	InvitedBy  primitive.ObjectID   `json:"invited_by" bson:"invited_by"`
+	TokenHash  string               `json:"-" bson:"token_hash"`
+	ExpiresAt  time.Time            `json:"expires_at" bson:"expires_at"`
+	SentAt     time.Time            `json:"sent_at" bson:"sent_at"`
+	CreatedAt  time.Time            `json:"created_at" bson:"created_at"`
+	AcceptedAt *time.Time           `json:"accepted_at,omitempty" bson:"accepted_at,omitempty"`
+	RevokedAt  *time.Time           `json:"revoked_at,omitempty" bson:"revoked_at,omitempty"`
+}
+
+// NewInvitation creates a new invitation
+func NewInvitation(email string, role Role, groupIDs []primitive.ObjectID, invitedBy primitive.ObjectID, tokenHash string, ttl time.Duration) *Invitation {
+	now := time.Now()
+	return &Invitation{
+		Email:     email,
+		Role:      role,
+		GroupIDs:  groupIDs,
+		InvitedBy: invitedBy,
+		TokenHash: tokenHash,
+		ExpiresAt: now.Add(ttl),
+		SentAt:    now,
+		CreatedAt: now,
+	}
+}
+
+// IsPending reports whether the invitation was neither accepted nor revoked.
+// A pending invitation may have expired, resending it renews it.
+func (i *Invitation) IsPending() bool {
+	return i.AcceptedAt == nil && i.RevokedAt == nil
+}
+
+// IsUsable reports whether the invitation can still be accepted
+func (i *Invitation) IsUsable(now time.Time) bool {
+	return i.IsPending() && now.Before(i.ExpiresAt)
+}
@@ -0,0 +1,30 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// LoginAttempts tracks recent failed logins for one account or client IP
+type LoginAttempts struct {
+	Key           string    `json:"key" bson:"_id"`
+	Failures      int       `json:"failures" bson:"failures"`
+	LastFailureAt time.Time `json:"last_failure_at" bson:"last_failure_at"`
+	// ExpiresAt is when the failures are forgotten if no new one is recorded
+	ExpiresAt time.Time `json:"expires_at" bson:"expires_at"`
+}
+
+// LoginLockedError is returned when too many logins failed recently.
+// It matches ErrTooManyLoginAttempts with errors.Is.
+type LoginLockedError struct {
+	RetryAfter time.Duration
+}
+
+func (e *LoginLockedError) Error() string {
+	return fmt.Sprintf("%s, retry after %s", ErrTooManyLoginAttempts, e.RetryAfter.Round(time.Second))
+}
+
+// Is makes errors.Is(err, ErrTooManyLoginAttempts) hold
+func (e *LoginLockedError) Is(target error) bool {
+	return target == ErrTooManyLoginAttempts
+}
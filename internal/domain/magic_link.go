@@ -0,0 +1,36 @@
+package domain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MagicLinkToken is a stored, hashed one-time login link. It can only be
+// redeemed by the browser holding the nonce it was requested with.
+type MagicLinkToken struct {
+	ID        primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	UserID    primitive.ObjectID `json:"user_id" bson:"user_id"`
+	TokenHash string             `json:"-" bson:"token_hash"`
+	NonceHash string             `json:"-" bson:"nonce_hash"`
+	ExpiresAt time.Time          `json:"expires_at" bson:"expires_at"`
+	CreatedAt time.Time          `json:"created_at" bson:"created_at"`
+	UsedAt    *time.Time         `json:"used_at,omitempty" bson:"used_at,omitempty"`
+}
+
+// NewMagicLinkToken creates a new single-use login link token
+func NewMagicLinkToken(userID primitive.ObjectID, tokenHash, nonceHash string, ttl time.Duration) *MagicLinkToken {
+	now := time.Now()
+	return &MagicLinkToken{
+		UserID:    userID,
+		TokenHash: tokenHash,
+		NonceHash: nonceHash,
+		ExpiresAt: now.Add(ttl),
+		CreatedAt: now,
+	}
+}
+
+// IsUsable reports whether the token is unused and not expired
+func (t *MagicLinkToken) IsUsable(now time.Time) bool {
+	return t.UsedAt == nil && now.Before(t.ExpiresAt)
+}
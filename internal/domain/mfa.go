@@ -0,0 +1,51 @@
+package domain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MFASettings holds a user's TOTP configuration. Secrets are stored encrypted
+// and recovery codes are stored hashed.
+type MFASettings struct {
+	Enabled                bool       `json:"enabled" bson:"enabled"`
+	EnabledAt              *time.Time `json:"enabled_at,omitempty" bson:"enabled_at,omitempty"`
+	EncryptedSecret        string     `json:"-" bson:"encrypted_secret,omitempty"`
+	PendingEncryptedSecret string     `json:"-" bson:"pending_encrypted_secret,omitempty"`
+	RecoveryCodeHashes     []string   `json:"-" bson:"recovery_code_hashes,omitempty"`
+	// LastUsedStep is the TOTP time step of the last accepted code, to stop replays
+	LastUsedStep int64 `json:"-" bson:"last_used_step,omitempty"`
+}
+
+// IsMFAEnabled reports whether the user has to present a second factor
+func (u *User) IsMFAEnabled() bool {
+	return u.MFA != nil && u.MFA.Enabled
+}
+
+// MFAChallenge is issued after a correct password when the user has MFA enabled.
+// It is exchanged, together with a valid code, for a token pair.
+type MFAChallenge struct {
+	ID        primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	UserID    primitive.ObjectID `json:"user_id" bson:"user_id"`
+	TokenHash string             `json:"-" bson:"token_hash"`
+	Attempts  int                `json:"attempts" bson:"attempts"`
+	ExpiresAt time.Time          `json:"expires_at" bson:"expires_at"`
+	CreatedAt time.Time          `json:"created_at" bson:"created_at"`
+}
+
+// NewMFAChallenge creates a new challenge for a user
+func NewMFAChallenge(userID primitive.ObjectID, tokenHash string, ttl time.Duration) *MFAChallenge {
+	now := time.Now()
+	return &MFAChallenge{
+		UserID:    userID,
+		TokenHash: tokenHash,
+		ExpiresAt: now.Add(ttl),
+		CreatedAt: now,
+	}
+}
+
+// IsExpired reports whether the challenge has passed its expiry time
+func (c *MFAChallenge) IsExpired(now time.Time) bool {
+	return !now.Before(c.ExpiresAt)
+}
@@ -0,0 +1,113 @@
+package domain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// OAuth grant types
+const (
+	GrantAuthorizationCode = "authorization_code"
+	GrantClientCredentials = "client_credentials"
+	GrantRefreshToken      = "refresh_token"
+)
+
+// OAuthClient is an application registered to obtain tokens through OAuth2.
+// Public clients, such as native and single page apps, have no secret.
+// A client belongs to the tenant of the administrator who registered it.
+type OAuthClient struct {
+	ID           primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	TenantID     primitive.ObjectID `json:"tenant_id" bson:"tenant_id"`
+	ClientID     string             `json:"client_id" bson:"client_id"`
+	SecretHash   string             `json:"-" bson:"secret_hash,omitempty"`
+	Name         string             `json:"name" bson:"name"`
+	Public       bool               `json:"public" bson:"public"`
+	RedirectURIs []string           `json:"redirect_uris" bson:"redirect_uris"`
+	GrantTypes   []string           `json:"grant_types" bson:"grant_types"`
+	Scopes       []string           `json:"scopes" bson:"scopes"`
+	CreatedAt    time.Time          `json:"created_at" bson:"created_at"`
+}
+
+// AllowsGrant reports whether the client may use the grant type
+func (c *OAuthClient) AllowsGrant(grantType string) bool {
+	return containsString(c.GrantTypes, grantType)
+}
+
+// AllowsRedirectURI reports whether uri exactly matches a registered redirect URI
+func (c *OAuthClient) AllowsRedirectURI(uri string) bool {
+	return containsString(c.RedirectURIs, uri)
+}
+
+// AllowsScope reports whether the client may request the scope
+func (c *OAuthClient) AllowsScope(scope string) bool {
+	return containsString(c.Scopes, scope)
+}
+
+// AuthorizationCode is a one-time code issued by /oauth/authorize and
+// redeemed at /oauth/token together with the PKCE code verifier
+type AuthorizationCode struct {
+	ID                  primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	CodeHash            string             `json:"-" bson:"code_hash"`
+	ClientID            string             `json:"client_id" bson:"client_id"`
+	UserID              primitive.ObjectID `json:"user_id" bson:"user_id"`
+	RedirectURI         string             `json:"redirect_uri" bson:"redirect_uri"`
+	Scopes              []string           `json:"scopes" bson:"scopes"`
+	CodeChallenge       string             `json:"-" bson:"code_challenge"`
+	CodeChallengeMethod string             `json:"-" bson:"code_challenge_method"`
+	Nonce               string             `json:"-" bson:"nonce,omitempty"`
+	ExpiresAt           time.Time          `json:"expires_at" bson:"expires_at"`
+	UsedAt              *time.Time         `json:"used_at,omitempty" bson:"used_at,omitempty"`
+	CreatedAt           time.Time          `json:"created_at" bson:"created_at"`
+}
+
+// IsUsable reports whether the code is unused and not expired
+func (c *AuthorizationCode) IsUsable(now time.Time) bool {
+	return c.UsedAt == nil && now.Before(c.ExpiresAt)
+}
+
+// OAuthConsent records the scopes a user has granted to a client
+type OAuthConsent struct {
+	ID        primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	UserID    primitive.ObjectID `json:"user_id" bson:"user_id"`
+	ClientID  string             `json:"client_id" bson:"client_id"`
+	Scopes    []string           `json:"scopes" bson:"scopes"`
+	GrantedAt time.Time          `json:"granted_at" bson:"granted_at"`
+}
+
+// Covers reports whether every scope was already granted
+func (c *OAuthConsent) Covers(scopes []string) bool {
+	for _, scope := range scopes {
+		if !containsString(c.Scopes, scope) {
+			return false
+		}
+	}
+	return true
+}
+
+// OAuthError is an error response defined by RFC 6749
+type OAuthError struct {
+	Code        string `json:"error"`
+	Description string `json:"error_description,omitempty"`
+}
+
+func (e *OAuthError) Error() string {
+	if e.Description == "" {
+		return e.Code
+	}
+	return e.Code + ": " + e.Description
+}
+
+// NewOAuthError creates an OAuth error with the given RFC 6749 error code
+func NewOAuthError(code, description string) *OAuthError {
+	return &OAuthError{Code: code, Description: description}
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,24 @@
+package domain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// PasswordHistoryEntry is a hash of a password a user has set
+type PasswordHistoryEntry struct {
+	ID           primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	UserID       primitive.ObjectID `json:"user_id" bson:"user_id"`
+	PasswordHash string             `json:"-" bson:"password_hash"`
+	CreatedAt    time.Time          `json:"created_at" bson:"created_at"`
+}
+
+// NewPasswordHistoryEntry creates a new history entry for a user
+func NewPasswordHistoryEntry(userID primitive.ObjectID, passwordHash string) *PasswordHistoryEntry {
+	return &PasswordHistoryEntry{
+		UserID:       userID,
+		PasswordHash: passwordHash,
+		CreatedAt:    time.Now(),
+	}
+}
@@ -0,0 +1,28 @@
+package domain
+
+import "strings"
+
+// PasswordViolation is a password policy rule that a password does not meet
+type PasswordViolation struct {
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// WeakPasswordError lists every rule a password does not meet.
+// It matches ErrWeakPassword with errors.Is.
+type WeakPasswordError struct {
+	Violations []PasswordViolation
+}
+
+func (e *WeakPasswordError) Error() string {
+	messages := make([]string, 0, len(e.Violations))
+	for _, v := range e.Violations {
+		messages = append(messages, v.Message)
+	}
+	return ErrWeakPassword.Error() + ": " + strings.Join(messages, "; ")
+}
+
+// Is makes errors.Is(err, ErrWeakPassword) hold
+func (e *WeakPasswordError) Is(target error) bool {
+	return target == ErrWeakPassword
+}
@@ -0,0 +1,33 @@
+package domain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// PasswordResetToken represents a stored, hashed password reset token
+type PasswordResetToken struct {
+	ID        primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	UserID    primitive.ObjectID `json:"user_id" bson:"user_id"`
+	TokenHash string             `json:"-" bson:"token_hash"`
+	ExpiresAt time.Time          `json:"expires_at" bson:"expires_at"`
+	CreatedAt time.Time          `json:"created_at" bson:"created_at"`
+	UsedAt    *time.Time         `json:"used_at,omitempty" bson:"used_at,omitempty"`
+}
+
+// NewPasswordResetToken creates a new single-use reset token
+func NewPasswordResetToken(userID primitive.ObjectID, tokenHash string, ttl time.Duration) *PasswordResetToken {
+	now := time.Now()
+	return &PasswordResetToken{
+		UserID:    userID,
+		TokenHash: tokenHash,
+		ExpiresAt: now.Add(ttl),
+		CreatedAt: now,
+	}
+}
+
+// IsUsable reports whether the token is unused and not expired
+func (t *PasswordResetToken) IsUsable(now time.Time) bool {
+	return t.UsedAt == nil && now.Before(t.ExpiresAt)
+}
@@ -0,0 +1,50 @@
+package domain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// RefreshToken represents a stored, hashed refresh token.
+// Tokens issued by rotating a previous one share the same FamilyID.
+type RefreshToken struct {
+	ID        primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	UserID    primitive.ObjectID `json:"user_id" bson:"user_id"`
+	FamilyID  string             `json:"family_id" bson:"family_id"`
+	TokenHash string             `json:"-" bson:"token_hash"`
+	ExpiresAt time.Time          `json:"expires_at" bson:"expires_at"`
+	CreatedAt time.Time          `json:"created_at" bson:"created_at"`
+	RotatedAt *time.Time         `json:"rotated_at,omitempty" bson:"rotated_at,omitempty"`
+	RevokedAt *time.Time         `json:"revoked_at,omitempty" bson:"revoked_at,omitempty"`
+	// ClientID and Scopes are set for tokens issued to an OAuth client
+	ClientID string   `json:"client_id,omitempty" bson:"client_id,omitempty"`
+	Scopes   []string `json:"scopes,omitempty" bson:"scopes,omitempty"`
+}
+
+// NewRefreshToken creates a new refresh token in the given family
+func NewRefreshToken(userID primitive.ObjectID, familyID, tokenHash string, ttl time.Duration) *RefreshToken {
+	now := time.Now()
+	return &RefreshToken{
+		UserID:    userID,
+		FamilyID:  familyID,
+		TokenHash: tokenHash,
+		ExpiresAt: now.Add(ttl),
+		CreatedAt: now,
+	}
+}
+
+// IsExpired reports whether the token has passed its expiry time
+func (t *RefreshToken) IsExpired(now time.Time) bool {
+	return !now.Before(t.ExpiresAt)
+}
+
+// IsRotated reports whether the token has already been exchanged for a new one
+func (t *RefreshToken) IsRotated() bool {
+	return t.RotatedAt != nil
+}
+
+// IsRevoked reports whether the token has been revoked
+func (t *RefreshToken) IsRevoked() bool {
+	return t.RevokedAt != nil
+}
@@ -0,0 +1,28 @@
+package domain
+
+// Role determines what a user is allowed to do
+type Role string
+
+// Supported roles
+const (
+	RoleAdmin   Role = "admin"
+	RoleSupport Role = "support"
+	RoleMember  Role = "member"
+)
+
+// IsValid reports whether the role is one of the supported roles
+func (r Role) IsValid() bool {
+	switch r {
+	case RoleAdmin, RoleSupport, RoleMember:
+		return true
+	}
+	return false
+}
+
+// RoleOrDefault returns the role, treating users stored before roles existed as members
+func (r Role) RoleOrDefault() Role {
+	if r == "" {
+		return RoleMember
+	}
+	return r
+}
@@ -0,0 +1,122 @@
+package domain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// SigningKeyStatus describes where a key is in its rotation lifecycle
+type SigningKeyStatus string
+
+// Signing key statuses. A pending key is published for verification before it
+// signs anything, and a retiring key keeps verifying until its tokens expire.
+const (
+	SigningKeyPending  SigningKeyStatus = "pending"
+	SigningKeyActive   SigningKeyStatus = "active"
+	SigningKeyRetiring SigningKeyStatus = "retiring"
+	SigningKeyRetired  SigningKeyStatus = "retired"
+)
+
+// SigningKey represents a managed JWT signing key
+type SigningKey struct {
+	ID        string `json:"kid" bson:"_id"`
+	Algorithm string `json:"alg" bson:"algorithm"`
+	// EncryptedPrivateKey is the PEM encoded private key, encrypted at rest
+	EncryptedPrivateKey string `json:"-" bson:"encrypted_private_key,omitempty"`
+	// PrivateKeyPEM is only set on keys stored before private keys were encrypted
+	PrivateKeyPEM string           `json:"-" bson:"private_key_pem,omitempty"`
+	Status        SigningKeyStatus `json:"status" bson:"status"`
+	CreatedAt     time.Time        `json:"created_at" bson:"created_at"`
+	ActivatedAt   *time.Time       `json:"activated_at,omitempty" bson:"activated_at,omitempty"`
+	DeactivatedAt *time.Time       `json:"deactivated_at,omitempty" bson:"deactivated_at,omitempty"`
+	RetiredAt     *time.Time       `json:"retired_at,omitempty" bson:"retired_at,omitempty"`
+}
+
+// NewSigningKey creates a new pending signing key
+func NewSigningKey(id, algorithm, encryptedPrivateKey string) *SigningKey {
+	return &SigningKey{
+		ID:                  id,
+		Algorithm:           algorithm,
+		EncryptedPrivateKey: encryptedPrivateKey,
+		Status:              SigningKeyPending,
+		CreatedAt:           time.Now(),
+	}
+}
+
+// IsEncrypted reports whether the private key is stored encrypted
+func (k *SigningKey) IsEncrypted() bool {
+	return k.PrivateKeyPEM == ""
+}
+
+// Activate makes the key the one new tokens are signed with
+func (k *SigningKey) Activate(now time.Time) error {
+	if k.Status != SigningKeyPending && k.Status != SigningKeyRetiring {
+		return ErrInvalidKeyTransition
+	}
+
+	k.Status = SigningKeyActive
+	k.ActivatedAt = &now
+	k.DeactivatedAt = nil
+	return nil
+}
+
+// Deactivate stops the key from signing while it still verifies
+func (k *SigningKey) Deactivate(now time.Time) error {
+	if k.Status != SigningKeyActive {
+		return ErrInvalidKeyTransition
+	}
+
+	k.Status = SigningKeyRetiring
+	k.DeactivatedAt = &now
+	return nil
+}
+
+// Retire removes the key from verification. A key that has signed tokens can
+// only be retired once the longest token lifetime has passed since it stopped signing.
+func (k *SigningKey) Retire(now time.Time, maxTokenLifetime time.Duration) error {
+	switch k.Status {
+	case SigningKeyPending:
+	case SigningKeyRetiring:
+		if k.DeactivatedAt != nil && now.Before(k.DeactivatedAt.Add(maxTokenLifetime)) {
+			return ErrSigningKeyInUse
+		}
+	default:
+		return ErrInvalidKeyTransition
+	}
+
+	k.Status = SigningKeyRetired
+	k.RetiredAt = &now
+	return nil
+}
+
+// KeyRotationAction names an audited change to a signing key
+type KeyRotationAction string
+
+// Audited key rotation actions
+const (
+	KeyGenerated   KeyRotationAction = "generated"
+	KeyPromoted    KeyRotationAction = "promoted"
+	KeyDeactivated KeyRotationAction = "deactivated"
+	KeyRetired     KeyRotationAction = "retired"
+	KeyEncrypted   KeyRotationAction = "encrypted"
+)
+
+// KeyRotationEvent is an audit record of a signing key change
+type KeyRotationEvent struct {
+	ID         primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	KeyID      string             `json:"kid" bson:"key_id"`
+	Action     KeyRotationAction  `json:"action" bson:"action"`
+	Actor      string             `json:"actor" bson:"actor"`
+	OccurredAt time.Time          `json:"occurred_at" bson:"occurred_at"`
+}
+
+// NewKeyRotationEvent creates a new audit record
+func NewKeyRotationEvent(keyID string, action KeyRotationAction, actor string) *KeyRotationEvent {
+	return &KeyRotationEvent{
+		KeyID:      keyID,
+		Action:     action,
+		Actor:      actor,
+		OccurredAt: time.Now(),
+	}
+}
@@ -0,0 +1,35 @@
+package domain
+
+import (
+	"regexp"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// tenantSlugPattern allows slugs that are safe in headers, URLs and hostnames
+var tenantSlugPattern = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?$`)
+
+// Tenant is a customer organization hosted on the deployment.
+// Every user belongs to exactly one tenant.
+type Tenant struct {
+	ID        primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	Slug      string             `json:"slug" bson:"slug"`
+	Name      string             `json:"name" bson:"name"`
+	CreatedAt time.Time          `json:"created_at" bson:"created_at"`
+}
+
+// NewTenant creates a new tenant. The slug is how requests name the tenant.
+func NewTenant(slug, name string) *Tenant {
+	return &Tenant{
+		Slug:      slug,
+		Name:      name,
+		CreatedAt: time.Now(),
+	}
+}
+
+// IsValidTenantSlug reports whether slug is lowercase letters, digits and
+// inner hyphens, at most 63 characters long
+func IsValidTenantSlug(slug string) bool {
+	return tenantSlugPattern.MatchString(slug)
+}
@@ -8,11 +8,18 @@ import (
 
 // User represents the user entity
 type User struct {
-	ID        primitive.ObjectID `json:"id" bson:"_id,omitempty"`
-	Name      string             `json:"name" bson:"name"`
-	Email     string             `json:"email" bson:"email"`
-	Password  string             `json:"-" bson:"password"` // Password is not returned in JSON
-	CreatedAt time.Time          `json:"created_at" bson:"created_at"`
+	ID              primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	TenantID        primitive.ObjectID `json:"tenant_id" bson:"tenant_id"`
+	Name            string             `json:"name" bson:"name"`
+	Email           string             `json:"email" bson:"email"`
+	EmailVerifiedAt *time.Time         `json:"email_verified_at,omitempty" bson:"email_verified_at,omitempty"`
+	Password        string             `json:"-" bson:"password"` // Password is not returned in JSON
+	Role            Role               `json:"role" bson:"role"`
+	MFA             *MFASettings       `json:"mfa,omitempty" bson:"mfa,omitempty"`
+	Status          UserStatus         `json:"status" bson:"status,omitempty"`
+	StatusChangedAt *time.Time         `json:"status_changed_at,omitempty" bson:"status_changed_at,omitempty"`
+	CreatedAt       time.Time          `json:"created_at" bson:"created_at"`
+	DeletedAt       *time.Time         `json:"deleted_at,omitempty" bson:"deleted_at,omitempty"`
 }
 
 // NewUser creates a new user with default values
@@ -21,10 +28,43 @@ func NewUser(name, email, password string) *User {
 		Name:      name,
 		Email:     email,
 		Password:  password,
+		Role:      RoleMember,
+		Status:    UserActive,
 		CreatedAt: time.Now(),
 	}
 }
 
+// IsEmailVerified reports whether the user has confirmed their current email address
+func (u *User) IsEmailVerified() bool {
+	return u.EmailVerifiedAt != nil
+}
+
+// ChangeEmail sets a new email address, which has to be verified again
+func (u *User) ChangeEmail(email string) {
+	if email == u.Email {
+		return
+	}
+
+	u.Email = email
+	u.EmailVerifiedAt = nil
+}
+
+// MarkEmailVerified records that the current email address was confirmed
+func (u *User) MarkEmailVerified(now time.Time) {
+	u.EmailVerifiedAt = &now
+}
+
+// IsDeleted reports whether the user was deleted and awaits being purged
+func (u *User) IsDeleted() bool {
+	return u.DeletedAt != nil
+}
+
+// HasPassword reports whether the user can log in with a password. Users
+// created by a federated login have none until they reset it.
+func (u *User) HasPassword() bool {
+	return u.Password != ""
+}
+
 // internal/domain/errors.go
 package domain
 
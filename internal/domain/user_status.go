@@ -0,0 +1,92 @@
+package domain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// UserStatus describes whether a user may sign in
+type UserStatus string
+
+// User statuses. Only active users can sign in or use their tokens. An
+// administrator suspends a user for misconduct, locks an account that may be
+// compromised, and deactivates an account that is no longer used.
+const (
+	UserActive      UserStatus = "active"
+	UserSuspended   UserStatus = "suspended"
+	UserLocked      UserStatus = "locked"
+	UserDeactivated UserStatus = "deactivated"
+)
+
+// StatusOrDefault returns the status, treating users stored before statuses existed as active
+func (s UserStatus) StatusOrDefault() UserStatus {
+	if s == "" {
+		return UserActive
+	}
+	return s
+}
+
+// IsActive reports whether the user may sign in
+func (u *User) IsActive() bool {
+	return u.Status.StatusOrDefault() == UserActive
+}
+
+// Suspend blocks an active or locked user
+func (u *User) Suspend(now time.Time) error {
+	return u.transition(now, UserSuspended, UserActive, UserLocked)
+}
+
+// Lock blocks an active user whose account may be compromised
+func (u *User) Lock(now time.Time) error {
+	return u.transition(now, UserLocked, UserActive)
+}
+
+// Deactivate blocks a user whose account is no longer used
+func (u *User) Deactivate(now time.Time) error {
+	return u.transition(now, UserDeactivated, UserActive, UserSuspended, UserLocked)
+}
+
+// Reactivate lets a blocked user sign in again
+func (u *User) Reactivate(now time.Time) error {
+	return u.transition(now, UserActive, UserSuspended, UserLocked, UserDeactivated)
+}
+
+// transition moves the user to status if their current status is one of from
+func (u *User) transition(now time.Time, status UserStatus, from ...UserStatus) error {
+	current := u.Status.StatusOrDefault()
+	for _, allowed := range from {
+		if current == allowed {
+			u.Status = status
+			u.StatusChangedAt = &now
+			return nil
+		}
+	}
+
+	return ErrInvalidStatusChange
+}
+
+// UserStatusChange is an audit record of a user's status transition
+type UserStatusChange struct {
+	ID         primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	TenantID   primitive.ObjectID `json:"tenant_id" bson:"tenant_id"`
+	UserID     primitive.ObjectID `json:"user_id" bson:"user_id"`
+	From       UserStatus         `json:"from" bson:"from"`
+	To         UserStatus         `json:"to" bson:"to"`
+	Reason     string             `json:"reason" bson:"reason"`
+	Actor      string             `json:"actor" bson:"actor"`
+	OccurredAt time.Time          `json:"occurred_at" bson:"occurred_at"`
+}
+
+// NewUserStatusChange records that actor moved user from a status to their current one
+func NewUserStatusChange(user *User, from UserStatus, reason, actor string) *UserStatusChange {
+	return &UserStatusChange{
+		TenantID:   user.TenantID,
+		UserID:     user.ID,
+		From:       from.StatusOrDefault(),
+		To:         user.Status,
+		Reason:     reason,
+		Actor:      actor,
+		OccurredAt: time.Now(),
+	}
+}
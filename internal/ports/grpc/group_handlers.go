@@ -0,0 +1,159 @@
+package grpc
+
+import (
+	"context"
+
+	"github.com/yourusername/userapi/internal/domain"
+	"github.com/yourusername/userapi/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// CreateGroup implements the gRPC CreateGroup method
+func (h *UserServiceHandler) CreateGroup(ctx context.Context, req *proto.CreateGroupRequest) (*proto.GroupResponse, error) {
+	if err := h.requireGroups(); err != nil {
+		return nil, err
+	}
+	if req.Name == "" {
+		return nil, status.Error(codes.InvalidArgument, "name is required")
+	}
+
+	group, err := h.groupService.CreateGroup(ctx, req.Name, req.Description)
+	if err != nil {
+		return nil, toStatus(err, "failed to create group")
+	}
+
+	return toGroupResponse(group), nil
+}
+
+// GetGroup implements the gRPC GetGroup method
+func (h *UserServiceHandler) GetGroup(ctx context.Context, req *proto.GetGroupRequest) (*proto.GroupResponse, error) {
+	if err := h.requireGroups(); err != nil {
+		return nil, err
+	}
+
+	group, err := h.groupService.GetGroup(ctx, req.Id)
+	if err != nil {
+		return nil, toStatus(err, "failed to get group")
+	}
+
+	return toGroupResponse(group), nil
+}
+
+// ListGroups implements the gRPC ListGroups method
+func (h *UserServiceHandler) ListGroups(ctx context.Context, req *proto.ListGroupsRequest) (*proto.ListGroupsResponse, error) {
+	if err := h.requireGroups(); err != nil {
+		return nil, err
+	}
+
+	groups, err := h.groupService.ListGroups(ctx)
+	if err != nil {
+		return nil, toStatus(err, "failed to list groups")
+	}
+
+	return toListGroupsResponse(groups), nil
+}
+
+// UpdateGroup implements the gRPC UpdateGroup method
+func (h *UserServiceHandler) UpdateGroup(ctx context.Context, req *proto.UpdateGroupRequest) (*proto.GroupResponse, error) {
+	if err := h.requireGroups(); err != nil {
+		return nil, err
+	}
+	if req.Name == "" {
+		return nil, status.Error(codes.InvalidArgument, "name is required")
+	}
+
+	group, err := h.groupService.UpdateGroup(ctx, req.Id, req.Name, req.Description)
+	if err != nil {
+		return nil, toStatus(err, "failed to update group")
+	}
+
+	return toGroupResponse(group), nil
+}
+
+// DeleteGroup implements the gRPC DeleteGroup method
+func (h *UserServiceHandler) DeleteGroup(ctx context.Context, req *proto.DeleteGroupRequest) (*proto.DeleteGroupResponse, error) {
+	if err := h.requireGroups(); err != nil {
+		return nil, err
+	}
+
+	if err := h.groupService.DeleteGroup(ctx, req.Id); err != nil {
+		return nil, toStatus(err, "failed to delete group")
+	}
+
+	return &proto.DeleteGroupResponse{}, nil
+}
+
+// AddGroupMember implements the gRPC AddGroupMember method
+func (h *UserServiceHandler) AddGroupMember(ctx context.Context, req *proto.GroupMemberRequest) (*proto.GroupResponse, error) {
+	if err := h.requireGroups(); err != nil {
+		return nil, err
+	}
+
+	group, err := h.groupService.AddMember(ctx, req.GroupId, req.UserId)
+	if err != nil {
+		return nil, toStatus(err, "failed to add group member")
+	}
+
+	return toGroupResponse(group), nil
+}
+
+// RemoveGroupMember implements the gRPC RemoveGroupMember method
+func (h *UserServiceHandler) RemoveGroupMember(ctx context.Context, req *proto.GroupMemberRequest) (*proto.GroupResponse, error) {
+	if err := h.requireGroups(); err != nil {
+		return nil, err
+	}
+
+	group, err := h.groupService.RemoveMember(ctx, req.GroupId, req.UserId)
+	if err != nil {
+		return nil, toStatus(err, "failed to remove group member")
+	}
+
+	return toGroupResponse(group), nil
+}
+
+// ListUserGroups implements the gRPC ListUserGroups method
+func (h *UserServiceHandler) ListUserGroups(ctx context.Context, req *proto.ListUserGroupsRequest) (*proto.ListGroupsResponse, error) {
+	if err := h.requireGroups(); err != nil {
+		return nil, err
+	}
+
+	groups, err := h.groupService.ListUserGroups(ctx, req.UserId)
+	if err != nil {
+		return nil, toStatus(err, "failed to list user groups")
+	}
+
+	return toListGroupsResponse(groups), nil
+}
+
+// requireGroups fails the call unless the handler was built WithGroupService
+func (h *UserServiceHandler) requireGroups() error {
+	if h.groupService == nil {
+		return status.Error(codes.Unimplemented, "groups are not enabled")
+	}
+	return nil
+}
+
+func toGroupResponse(group *domain.Group) *proto.GroupResponse {
+	memberIDs := make([]string, 0, len(group.MemberIDs))
+	for _, id := range group.MemberIDs {
+		memberIDs = append(memberIDs, id.Hex())
+	}
+
+	return &proto.GroupResponse{
+		Id:          group.ID.Hex(),
+		Name:        group.Name,
+		Description: group.Description,
+		MemberIds:   memberIDs,
+		CreatedAt:   timestamppb.New(group.CreatedAt).String(),
+	}
+}
+
+func toListGroupsResponse(groups []*domain.Group) *proto.ListGroupsResponse {
+	response := &proto.ListGroupsResponse{Groups: make([]*proto.GroupResponse, 0, len(groups))}
+	for _, group := range groups {
+		response.Groups = append(response.Groups, toGroupResponse(group))
+	}
+	return response
+}
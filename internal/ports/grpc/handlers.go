@@ -2,10 +2,14 @@ package grpc
 
 import (
 	"context"
+	"encoding/base64"
+	"strings"
 
 	"github.com/yourusername/userapi/internal/application"
+	"github.com/yourusername/userapi/internal/ports/repository"
 	"github.com/yourusername/userapi/proto"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
@@ -15,27 +19,68 @@ type UserServiceHandler struct {
 	proto.UnimplementedUserServiceServer
 	userService *application.UserService
 	authService *application.AuthService
+
+	introspectionService *application.IntrospectionService
+	tenantService        *application.TenantService
+	groupService         *application.GroupService
+}
+
+// HandlerOption enables optional methods on a UserServiceHandler.
+// Methods whose service is not set return codes.Unimplemented.
+type HandlerOption func(*UserServiceHandler)
+
+// WithIntrospectionService enables the IntrospectToken method
+func WithIntrospectionService(service *application.IntrospectionService) HandlerOption {
+	return func(h *UserServiceHandler) {
+		h.introspectionService = service
+	}
+}
+
+// WithTenantService lets CreateUser name the tenant in the "x-tenant" metadata
+func WithTenantService(service *application.TenantService) HandlerOption {
+	return func(h *UserServiceHandler) {
+		h.tenantService = service
+	}
+}
+
+// WithGroupService enables the group management methods
+func WithGroupService(service *application.GroupService) HandlerOption {
+	return func(h *UserServiceHandler) {
+		h.groupService = service
+	}
 }
 
 // NewUserServiceHandler creates a new gRPC handler
-func NewUserServiceHandler(userService *application.UserService, authService *application.AuthService) *UserServiceHandler {
-	return &UserServiceHandler{
+func NewUserServiceHandler(userService *application.UserService, authService *application.AuthService, opts ...HandlerOption) *UserServiceHandler {
+	h := &UserServiceHandler{
 		userService: userService,
 		authService: authService,
 	}
+
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	return h
 }
 
 // CreateUser implements the gRPC CreateUser method
 func (h *UserServiceHandler) CreateUser(ctx context.Context, req *proto.CreateUserRequest) (*proto.UserResponse, error) {
+	ctx, err := h.withTenant(ctx)
+	if err != nil {
+		return nil, toStatus(err, "failed to resolve tenant")
+	}
+
 	user, err := h.authService.Register(ctx, req.Name, req.Email, req.Password)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to create user: %v", err)
+		return nil, toStatus(err, "failed to create user")
 	}
 
 	return &proto.UserResponse{
 		Id:        user.ID.Hex(),
 		Name:      user.Name,
 		Email:     user.Email,
+		Role:      string(user.Role.RoleOrDefault()),
 		CreatedAt: timestamppb.New(user.CreatedAt).String(),
 	}, nil
 }
@@ -44,13 +89,90 @@ func (h *UserServiceHandler) CreateUser(ctx context.Context, req *proto.CreateUs
 func (h *UserServiceHandler) GetUser(ctx context.Context, req *proto.GetUserRequest) (*proto.UserResponse, error) {
 	user, err := h.userService.GetUserByID(ctx, req.Id)
 	if err != nil {
-		return nil, status.Errorf(codes.NotFound, "user not found: %v", err)
+		return nil, toStatus(err, "failed to get user")
 	}
 
 	return &proto.UserResponse{
 		Id:        user.ID.Hex(),
 		Name:      user.Name,
 		Email:     user.Email,
+		Role:      string(user.Role.RoleOrDefault()),
 		CreatedAt: timestamppb.New(user.CreatedAt).String(),
 	}, nil
 }
+
+// IntrospectToken implements the gRPC IntrospectToken method
+func (h *UserServiceHandler) IntrospectToken(ctx context.Context, req *proto.IntrospectTokenRequest) (*proto.IntrospectTokenResponse, error) {
+	if h.introspectionService == nil {
+		return nil, status.Error(codes.Unimplemented, "token introspection is not enabled")
+	}
+	if req.Token == "" {
+		return nil, status.Error(codes.InvalidArgument, "token is required")
+	}
+
+	introspection, err := h.introspectionService.Introspect(ctx, introspectionCaller(ctx), req.Token)
+	if err != nil {
+		return nil, toStatus(err, "failed to introspect token")
+	}
+
+	return &proto.IntrospectTokenResponse{
+		Active:    introspection.Active,
+		Scope:     introspection.Scope,
+		ClientId:  introspection.ClientID,
+		Username:  introspection.Username,
+		TokenType: introspection.TokenType,
+		Exp:       introspection.ExpiresAt,
+		Iat:       introspection.IssuedAt,
+		Sub:       introspection.Subject,
+		Aud:       introspection.Audience,
+		Iss:       introspection.Issuer,
+		Jti:       introspection.TokenID,
+		TenantId:  introspection.TenantID,
+	}, nil
+}
+
+// withTenant scopes an unauthenticated call to the tenant named, by its
+// slug, in the "x-tenant" metadata. Without it the call stays unscoped.
+func (h *UserServiceHandler) withTenant(ctx context.Context) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get("x-tenant")
+	if len(values) == 0 || h.tenantService == nil {
+		return ctx, nil
+	}
+
+	tenant, err := h.tenantService.ResolveTenant(ctx, values[0])
+	if err != nil {
+		return nil, err
+	}
+
+	return repository.WithTenant(ctx, tenant.ID.Hex()), nil
+}
+
+// introspectionCaller reads the caller's credentials from the request metadata
+func introspectionCaller(ctx context.Context) application.IntrospectionCaller {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if values := md.Get("x-api-key"); len(values) > 0 {
+		return application.IntrospectionCaller{APIKey: values[0]}
+	}
+
+	values := md.Get("authorization")
+	if len(values) == 0 {
+		return application.IntrospectionCaller{}
+	}
+
+	scheme, credentials, _ := strings.Cut(values[0], " ")
+	switch strings.ToLower(scheme) {
+	case "apikey":
+		return application.IntrospectionCaller{APIKey: strings.TrimSpace(credentials)}
+	case "basic":
+		// Decode like net/http does for Request.BasicAuth
+		decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(credentials))
+		if err != nil {
+			return application.IntrospectionCaller{}
+		}
+		clientID, clientSecret, _ := strings.Cut(string(decoded), ":")
+		return application.IntrospectionCaller{ClientID: clientID, ClientSecret: clientSecret}
+	}
+
+	return application.IntrospectionCaller{}
+}
@@ -0,0 +1,108 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/yourusername/userapi/internal/application"
+	"github.com/yourusername/userapi/internal/domain"
+	"github.com/yourusername/userapi/internal/ports/repository"
+	"github.com/yourusername/userapi/pkg/auth"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// publicMethods can be called without a token
+var publicMethods = map[string]bool{
+	"/proto.UserService/CreateUser": true,
+	// The introspection caller authenticates itself, see IntrospectToken
+	"/proto.UserService/IntrospectToken": true,
+}
+
+// methodScopes lists the scopes a token needs for each method, matching the
+// RequireScope checks on the HTTP routes. Methods missing here are refused,
+// so a new RPC cannot be exposed without deciding its scopes.
+var methodScopes = map[string][]string{
+	"/proto.UserService/GetUser": {application.ScopeUsersRead},
+
+	"/proto.UserService/ListGroups":        {application.ScopeUsersRead},
+	"/proto.UserService/CreateGroup":       {application.ScopeUsersWrite},
+	"/proto.UserService/GetGroup":          {application.ScopeUsersRead},
+	"/proto.UserService/UpdateGroup":       {application.ScopeUsersWrite},
+	"/proto.UserService/DeleteGroup":       {application.ScopeUsersWrite},
+	"/proto.UserService/AddGroupMember":    {application.ScopeUsersWrite},
+	"/proto.UserService/RemoveGroupMember": {application.ScopeUsersWrite},
+	"/proto.UserService/ListUserGroups":    {application.ScopeUsersRead},
+}
+
+// AuthInterceptor validates the bearer token sent in the "authorization"
+// metadata and attaches the caller to the context, like AuthMiddleware does for HTTP.
+// The token must carry the scopes methodScopes lists for the method.
+func AuthInterceptor(jwtAuth *auth.JWTAuth) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if publicMethods[info.FullMethod] {
+			return handler(ctx, req)
+		}
+
+		md, _ := metadata.FromIncomingContext(ctx)
+		values := md.Get("authorization")
+		if len(values) == 0 {
+			return nil, status.Error(codes.Unauthenticated, "authorization metadata is required")
+		}
+
+		bearerToken := strings.Split(values[0], " ")
+		if len(bearerToken) != 2 || strings.ToLower(bearerToken[0]) != "bearer" {
+			return nil, status.Error(codes.Unauthenticated, "invalid authorization format. Format: Bearer {token}")
+		}
+
+		claims, err := jwtAuth.ValidateToken(ctx, bearerToken[1])
+		if err != nil || (claims.UserID != "" && claims.TenantID == "") {
+			return nil, status.Error(codes.Unauthenticated, "invalid or expired token")
+		}
+
+		scopes, ok := methodScopes[info.FullMethod]
+		if !ok {
+			return nil, status.Error(codes.PermissionDenied, "method is not available")
+		}
+		for _, scope := range scopes {
+			if !claims.HasScope(scope) {
+				return nil, status.Errorf(codes.PermissionDenied, "token is missing required scope: %s", scope)
+			}
+		}
+
+		ctx = repository.WithTenant(ctx, claims.TenantID)
+		ctx = application.WithPrincipal(ctx, &application.Principal{
+			UserID: claims.UserID,
+			Email:  claims.Email,
+			Role:   domain.Role(claims.Role),
+		})
+
+		return handler(ctx, req)
+	}
+}
+
+// toStatus maps application errors to gRPC status errors
+func toStatus(err error, message string) error {
+	code := codes.Internal
+	if errors.Is(err, domain.ErrWeakPassword) {
+		code = codes.InvalidArgument
+	}
+	switch err {
+	case domain.ErrUserNotFound, domain.ErrGroupNotFound:
+		code = codes.NotFound
+	case domain.ErrEmailAlreadyExists, domain.ErrGroupAlreadyExists:
+		code = codes.AlreadyExists
+	case domain.ErrTenantRequired:
+		code = codes.InvalidArgument
+	case domain.ErrTenantNotFound:
+		code = codes.NotFound
+	case domain.ErrForbidden:
+		code = codes.PermissionDenied
+	case domain.ErrUnauthenticated:
+		code = codes.Unauthenticated
+	}
+	return status.Errorf(code, "%s: %v", message, err)
+}
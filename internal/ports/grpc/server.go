@@ -0,0 +1,55 @@
+package grpc
+
+import (
+	"log"
+	"net"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/yourusername/userapi/pkg/auth"
+	"github.com/yourusername/userapi/proto"
+	"google.golang.org/grpc"
+)
+
+// Server represents the gRPC server
+type Server struct {
+	server *grpc.Server
+	addr   string
+}
+
+// NewServer creates a new gRPC server
+func NewServer(handler *UserServiceHandler, jwtAuth *auth.JWTAuth, addr string) *Server {
+	server := grpc.NewServer(grpc.UnaryInterceptor(AuthInterceptor(jwtAuth)))
+	proto.RegisterUserServiceServer(server, handler)
+
+	return &Server{
+		server: server,
+		addr:   addr,
+	}
+}
+
+// Start starts the gRPC server
+func (s *Server) Start() {
+	listener, err := net.Listen("tcp", s.addr)
+	if err != nil {
+		log.Fatalf("Failed to listen on %s: %v", s.addr, err)
+	}
+
+	// Start the server in a goroutine
+	go func() {
+		log.Printf("Starting gRPC server on %s", s.addr)
+		if err := s.server.Serve(listener); err != nil {
+			log.Fatalf("Failed to start gRPC server: %v", err)
+		}
+	}()
+
+	// Wait for shutdown signal
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+
+	log.Println("Shutting down gRPC server...")
+	s.server.GracefulStop()
+	log.Println("gRPC server exited gracefully")
+}
@@ -0,0 +1,12 @@
+package hasher
+
+// PasswordHasher defines the interface for hashing and verifying passwords.
+// Hashes are self-describing strings that name their algorithm and parameters.
+type PasswordHasher interface {
+	Hash(password string) (string, error)
+	// Verify reports whether password matches the encoded hash
+	Verify(password, encoded string) (bool, error)
+	// NeedsRehash reports whether encoded was made with an outdated algorithm
+	// or parameters and should be replaced the next time the password is known
+	NeedsRehash(encoded string) bool
+}
@@ -0,0 +1,84 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/yourusername/userapi/internal/domain"
+	"github.com/yourusername/userapi/pkg/validation"
+)
+
+// SuspendUserHandler suspends a user
+func (h *Handler) SuspendUserHandler(w http.ResponseWriter, r *http.Request) {
+	h.changeStatus(w, r, h.statusService.SuspendUser)
+}
+
+// LockUserHandler locks a user whose account may be compromised
+func (h *Handler) LockUserHandler(w http.ResponseWriter, r *http.Request) {
+	h.changeStatus(w, r, h.statusService.LockUser)
+}
+
+// DeactivateUserHandler deactivates a user
+func (h *Handler) DeactivateUserHandler(w http.ResponseWriter, r *http.Request) {
+	h.changeStatus(w, r, h.statusService.DeactivateUser)
+}
+
+// ReactivateUserHandler lets a suspended, locked or deactivated user sign in again
+func (h *Handler) ReactivateUserHandler(w http.ResponseWriter, r *http.Request) {
+	h.changeStatus(w, r, h.statusService.ReactivateUser)
+}
+
+// ListStatusChangesHandler lists a user's status changes
+func (h *Handler) ListStatusChangesHandler(w http.ResponseWriter, r *http.Request) {
+	changes, err := h.statusService.ListStatusChanges(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		respondWithError(w, statusChangeErrorStatus(err), err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, Response{Success: true, Data: changes})
+}
+
+// changeStatus reads the reason for a status change and applies it with change
+func (h *Handler) changeStatus(w http.ResponseWriter, r *http.Request, change func(ctx context.Context, id, reason string) (*domain.User, error)) {
+	var input struct {
+		Reason string `json:"reason" validate:"required"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	// Validate input
+	if err := validation.Validate(input); err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	user, err := change(r.Context(), chi.URLParam(r, "id"), input.Reason)
+	if err != nil {
+		respondWithError(w, statusChangeErrorStatus(err), err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, Response{Success: true, Data: user})
+}
+
+// statusChangeErrorStatus maps account status errors to HTTP status codes
+func statusChangeErrorStatus(err error) int {
+	switch err {
+	case domain.ErrUserNotFound:
+		return http.StatusNotFound
+	case domain.ErrInvalidStatusChange:
+		return http.StatusConflict
+	case domain.ErrForbidden:
+		return http.StatusForbidden
+	case domain.ErrUnauthenticated:
+		return http.StatusUnauthorized
+	default:
+		return http.StatusInternalServerError
+	}
+}
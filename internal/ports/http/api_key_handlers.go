@@ -0,0 +1,95 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/yourusername/userapi/internal/domain"
+	"github.com/yourusername/userapi/pkg/validation"
+)
+
+// CreateAPIKeyHandler creates an API key for the authenticated user.
+// The key itself is only part of this response.
+func (h *Handler) CreateAPIKeyHandler(w http.ResponseWriter, r *http.Request) {
+	userID, _ := r.Context().Value("userID").(string)
+
+	var input struct {
+		Name      string     `json:"name" validate:"required"`
+		Scopes    []string   `json:"scopes"`
+		ExpiresAt *time.Time `json:"expires_at"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	// Validate input
+	if err := validation.Validate(input); err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if input.ExpiresAt != nil && !input.ExpiresAt.After(time.Now()) {
+		respondWithError(w, http.StatusBadRequest, "expires_at must be in the future")
+		return
+	}
+
+	key, plain, err := h.apiKeyService.Create(r.Context(), userID, input.Name, input.Scopes, input.ExpiresAt)
+	if err != nil {
+		respondWithError(w, apiKeyErrorStatus(err), err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, Response{
+		Success: true,
+		Data: map[string]interface{}{
+			"key":     plain,
+			"api_key": key,
+		},
+	})
+}
+
+// ListAPIKeysHandler lists the authenticated user's API keys
+func (h *Handler) ListAPIKeysHandler(w http.ResponseWriter, r *http.Request) {
+	userID, _ := r.Context().Value("userID").(string)
+
+	keys, err := h.apiKeyService.List(r.Context(), userID)
+	if err != nil {
+		respondWithError(w, apiKeyErrorStatus(err), err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, Response{Success: true, Data: keys})
+}
+
+// RevokeAPIKeyHandler revokes one of the authenticated user's API keys
+func (h *Handler) RevokeAPIKeyHandler(w http.ResponseWriter, r *http.Request) {
+	userID, _ := r.Context().Value("userID").(string)
+	id := chi.URLParam(r, "id")
+
+	if err := h.apiKeyService.Revoke(r.Context(), userID, id); err != nil {
+		respondWithError(w, apiKeyErrorStatus(err), err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, Response{Success: true})
+}
+
+// apiKeyErrorStatus maps API key management errors to HTTP status codes
+func apiKeyErrorStatus(err error) int {
+	switch err {
+	case domain.ErrInvalidScope:
+		return http.StatusBadRequest
+	case domain.ErrAPIKeyNotFound, domain.ErrUserNotFound:
+		return http.StatusNotFound
+	case domain.ErrForbidden:
+		return http.StatusForbidden
+	case domain.ErrUnauthenticated:
+		return http.StatusUnauthorized
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,51 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/yourusername/userapi/internal/domain"
+)
+
+// VerifyEmailHandler confirms an email address using the token from the link
+func (h *Handler) VerifyEmailHandler(w http.ResponseWriter, r *http.Request) {
+	token := r.URL.Query().Get("token")
+	if token == "" {
+		respondWithError(w, http.StatusBadRequest, "Missing token")
+		return
+	}
+
+	user, err := h.verificationService.Verify(r.Context(), token)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err == domain.ErrInvalidToken {
+			status = http.StatusBadRequest
+		}
+		respondWithError(w, status, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, Response{Success: true, Data: user})
+}
+
+// ResendVerificationHandler sends the authenticated user a new verification link
+func (h *Handler) ResendVerificationHandler(w http.ResponseWriter, r *http.Request) {
+	userID, _ := r.Context().Value("userID").(string)
+
+	err := h.verificationService.Resend(r.Context(), userID)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err == domain.ErrEmailAlreadyVerified {
+			status = http.StatusConflict
+		} else if err == domain.ErrVerificationCooldown {
+			status = http.StatusTooManyRequests
+			w.Header().Set("Retry-After", fmt.Sprint(int(h.verificationService.ResendCooldown().Seconds())))
+		} else if err == domain.ErrUserNotFound {
+			status = http.StatusNotFound
+		}
+		respondWithError(w, status, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusAccepted, Response{Success: true})
+}
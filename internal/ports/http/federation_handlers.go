@@ -0,0 +1,95 @@
+package http
+
+import (
+	"crypto/subtle"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/yourusername/userapi/internal/domain"
+	"github.com/yourusername/userapi/pkg/oidc"
+)
+
+// federationStateCookie binds a federated login to the browser that started
+// it, so a callback URL cannot be replayed in someone else's browser
+const federationStateCookie = "sso_state"
+
+// ListIdentityProvidersHandler lists the providers users can sign in with
+func (h *Handler) ListIdentityProvidersHandler(w http.ResponseWriter, r *http.Request) {
+	respondWithJSON(w, http.StatusOK, Response{Success: true, Data: h.federationService.Providers()})
+}
+
+// BeginFederatedLoginHandler redirects the user to the identity provider
+func (h *Handler) BeginFederatedLoginHandler(w http.ResponseWriter, r *http.Request) {
+	provider := chi.URLParam(r, "provider")
+
+	login, err := h.federationService.BeginLogin(r.Context(), provider)
+	if err != nil {
+		status := http.StatusBadGateway
+		if err == domain.ErrUnknownProvider {
+			status = http.StatusNotFound
+		}
+		respondWithError(w, status, err.Error())
+		return
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     federationStateCookie,
+		Value:    login.State,
+		Path:     "/sso/" + provider,
+		HttpOnly: true,
+		Secure:   true,
+		// Lax lets the cookie through on the provider's top-level redirect back
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	http.Redirect(w, r, login.AuthURL, http.StatusFound)
+}
+
+// FederatedCallbackHandler completes a login when the identity provider
+// redirects back, responding like LoginHandler
+func (h *Handler) FederatedCallbackHandler(w http.ResponseWriter, r *http.Request) {
+	provider := chi.URLParam(r, "provider")
+	query := r.URL.Query()
+
+	// The state is single use either way, so clear the cookie
+	http.SetCookie(w, &http.Cookie{
+		Name:     federationStateCookie,
+		Path:     "/sso/" + provider,
+		MaxAge:   -1,
+		HttpOnly: true,
+		Secure:   true,
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	if errCode := query.Get("error"); errCode != "" {
+		respondWithError(w, http.StatusUnauthorized, "Identity provider returned an error: "+errCode)
+		return
+	}
+
+	state := query.Get("state")
+	cookie, err := r.Cookie(federationStateCookie)
+	if err != nil || state == "" || subtle.ConstantTimeCompare([]byte(cookie.Value), []byte(state)) != 1 {
+		respondWithError(w, http.StatusBadRequest, "Login state does not match, start the login again")
+		return
+	}
+
+	result, err := h.federationService.CompleteLogin(r.Context(), provider, state, query.Get("code"))
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err == domain.ErrUnknownProvider {
+			status = http.StatusNotFound
+		} else if err == domain.ErrInvalidToken {
+			status = http.StatusBadRequest
+		} else if err == oidc.ErrTokenExchange || err == oidc.ErrInvalidIDToken {
+			status = http.StatusUnauthorized
+		} else if err == domain.ErrEmailNotVerified || err == domain.ErrSignupNotAllowed || err == domain.ErrAccountInactive {
+			status = http.StatusForbidden
+		} else if err == domain.ErrIdentityConflict || err == domain.ErrEmailAlreadyExists {
+			status = http.StatusConflict
+		}
+		respondWithError(w, status, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, Response{Success: true, Data: result})
+}
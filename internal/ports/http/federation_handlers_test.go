@@ -0,0 +1,219 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/yourusername/userapi/internal/adapters/hasher"
+	"github.com/yourusername/userapi/internal/adapters/repository/memory"
+	"github.com/yourusername/userapi/internal/application"
+	"github.com/yourusername/userapi/internal/ports/repository"
+	"github.com/yourusername/userapi/pkg/auth"
+	"github.com/yourusername/userapi/pkg/oidc"
+	"github.com/yourusername/userapi/pkg/oidc/oidctest"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// federationTestServer runs the API with two providers backed by the same
+// fake identity provider: "corp" creates users, "partner" links by email
+type federationTestServer struct {
+	*oauthTestServer
+	idp      *oidctest.Provider
+	users    *userStore
+	tenantID string
+}
+
+func newFederationTestServer(t *testing.T) *federationTestServer {
+	t.Helper()
+
+	passwordHasher, err := hasher.New(hasher.Config{Algorithm: hasher.AlgorithmBcrypt, BcryptCost: bcrypt.MinCost})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	users := newUserStore()
+	revocations := memory.NewTokenRevocationRepository()
+	jwtAuth := auth.NewJWTAuth("test-secret", time.Minute, auth.WithRevocationStore(revocations))
+	authService := application.NewAuthService(users, passwordHasher, newRefreshTokenStore(), revocations, jwtAuth, time.Hour)
+
+	tenants := application.NewTenantService(&tenantStore{})
+	tenant, err := tenants.CreateTenant(context.Background(), testTenant, "Acme")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := authService.Register(repository.WithTenant(context.Background(), tenant.ID.Hex()), "Ada Lovelace", testEmail, testPassword); err != nil {
+		t.Fatal(err)
+	}
+
+	idp := oidctest.NewProvider(t)
+	provider := func(name string) *oidc.Provider {
+		return oidc.NewProvider(oidc.Config{
+			Name:         name,
+			Issuer:       idp.Issuer(),
+			ClientID:     oidctest.ClientID,
+			ClientSecret: oidctest.ClientSecret,
+			RedirectURL:  "https://app.example/sso/" + name + "/callback",
+		})
+	}
+	federationService := application.NewFederationService([]application.IdentityProvider{
+		{Provider: provider("corp"), TenantID: tenant.ID.Hex(), CreateUsers: true},
+		{Provider: provider("partner"), TenantID: tenant.ID.Hex(), LinkByEmail: true},
+	}, &identityStore{}, newLoginStateStore(), users, authService, time.Minute)
+
+	handler := NewHandler(application.NewUserService(users, passwordHasher), authService, jwtAuth,
+		WithTenantService(tenants),
+		WithFederationService(federationService),
+	)
+	api := httptest.NewServer(NewServer(handler, jwtAuth, "").router)
+	t.Cleanup(api.Close)
+
+	return &federationTestServer{
+		oauthTestServer: &oauthTestServer{t: t, api: api, jwtAuth: jwtAuth},
+		idp:             idp,
+		users:           users,
+		tenantID:        tenant.ID.Hex(),
+	}
+}
+
+// signIn starts a login at the provider, signs user in at the fake identity
+// provider and follows the callback back to the API
+func (s *federationTestServer) signIn(provider string, user oidctest.User, wantStatus int) *application.LoginResult {
+	s.t.Helper()
+
+	resp := s.do(http.MethodGet, "/sso/"+provider+"/login", "", nil, http.StatusFound, nil)
+	var stateCookie *http.Cookie
+	for _, cookie := range resp.Cookies() {
+		if cookie.Name == federationStateCookie {
+			stateCookie = cookie
+		}
+	}
+	if stateCookie == nil {
+		s.t.Fatal("login did not set the state cookie")
+	}
+
+	code := s.idp.Authorize(resp.Header.Get("Location"), user)
+
+	req, err := http.NewRequest(http.MethodGet, s.api.URL+"/sso/"+provider+"/callback?code="+code+"&state="+stateCookie.Value, nil)
+	if err != nil {
+		s.t.Fatal(err)
+	}
+	req.AddCookie(stateCookie)
+
+	var body struct {
+		Data *application.LoginResult `json:"data"`
+	}
+	s.send(req, wantStatus, &body)
+	return body.Data
+}
+
+func (s *federationTestServer) tenantContext() context.Context {
+	return repository.WithTenant(context.Background(), s.tenantID)
+}
+
+var corpUser = oidctest.User{
+	Subject:       "corp-1",
+	Name:          "Grace Hopper",
+	Email:         "grace@corp.example",
+	EmailVerified: true,
+}
+
+func TestFederatedLoginCreatesUser(t *testing.T) {
+	s := newFederationTestServer(t)
+
+	result := s.signIn("corp", corpUser, http.StatusOK)
+
+	user, err := s.users.FindByEmail(s.tenantContext(), corpUser.Email)
+	if err != nil {
+		t.Fatalf("user was not created: %v", err)
+	}
+	if user.Name != corpUser.Name || user.HasPassword() || !user.IsEmailVerified() {
+		t.Errorf("unexpected user: %+v", user)
+	}
+
+	claims, err := s.jwtAuth.ValidateToken(context.Background(), result.AccessToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if claims.UserID != user.ID.Hex() {
+		t.Errorf("token is for %s, want %s", claims.UserID, user.ID.Hex())
+	}
+
+	// The identity is linked, so a changed email at the provider signs in the same user
+	changed := corpUser
+	changed.Email = "grace.hopper@corp.example"
+	result = s.signIn("corp", changed, http.StatusOK)
+	if claims, _ := s.jwtAuth.ValidateToken(context.Background(), result.AccessToken); claims == nil || claims.UserID != user.ID.Hex() {
+		t.Errorf("second sign in did not reuse the linked user")
+	}
+}
+
+func TestFederatedLoginRejections(t *testing.T) {
+	tests := []struct {
+		name       string
+		provider   string
+		user       oidctest.User
+		wantStatus int
+	}{
+		{
+			name:     "existing email without link by email",
+			provider: "corp",
+			user:     oidctest.User{Subject: "corp-2", Email: testEmail, EmailVerified: true},
+			// domain.ErrIdentityConflict
+			wantStatus: http.StatusConflict,
+		},
+		{
+			name:     "unverified email",
+			provider: "partner",
+			user:     oidctest.User{Subject: "partner-1", Email: testEmail, EmailVerified: false},
+			// domain.ErrEmailNotVerified
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:     "unknown email without user creation",
+			provider: "partner",
+			user:     oidctest.User{Subject: "partner-2", Email: "new@partner.example", EmailVerified: true},
+			// domain.ErrSignupNotAllowed
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:     "nonce mismatch",
+			provider: "corp",
+			user:     oidctest.User{Subject: "corp-3", Email: "eve@corp.example", EmailVerified: true, Nonce: "another-login"},
+			// oidc.ErrInvalidIDToken
+			wantStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newFederationTestServer(t)
+			s.signIn(tt.provider, tt.user, tt.wantStatus)
+
+			count, _ := s.users.Count(s.tenantContext())
+			if count != 1 {
+				t.Errorf("%d users after a rejected sign in, want 1", count)
+			}
+		})
+	}
+}
+
+func TestFederatedLoginLinksByEmail(t *testing.T) {
+	s := newFederationTestServer(t)
+
+	result := s.signIn("partner", oidctest.User{Subject: "partner-1", Email: testEmail, EmailVerified: true}, http.StatusOK)
+
+	existing, err := s.users.FindByEmail(s.tenantContext(), testEmail)
+	if err != nil {
+		t.Fatal(err)
+	}
+	claims, err := s.jwtAuth.ValidateToken(context.Background(), result.AccessToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if claims.UserID != existing.ID.Hex() {
+		t.Errorf("token is for %s, want the existing user %s", claims.UserID, existing.ID.Hex())
+	}
+}
@@ -0,0 +1,160 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/yourusername/userapi/internal/domain"
+	"github.com/yourusername/userapi/pkg/validation"
+)
+
+// groupInput is the body of the create and update group requests
+type groupInput struct {
+	Name        string `json:"name" validate:"required"`
+	Description string `json:"description"`
+}
+
+// CreateGroupHandler creates a group
+func (h *Handler) CreateGroupHandler(w http.ResponseWriter, r *http.Request) {
+	var input groupInput
+
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	// Validate input
+	if err := validation.Validate(input); err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	group, err := h.groupService.CreateGroup(r.Context(), input.Name, input.Description)
+	if err != nil {
+		respondWithError(w, groupErrorStatus(err), err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, Response{Success: true, Data: group})
+}
+
+// ListGroupsHandler lists all groups
+func (h *Handler) ListGroupsHandler(w http.ResponseWriter, r *http.Request) {
+	groups, err := h.groupService.ListGroups(r.Context())
+	if err != nil {
+		respondWithError(w, groupErrorStatus(err), err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, Response{Success: true, Data: groups})
+}
+
+// GetGroupHandler retrieves a group by ID
+func (h *Handler) GetGroupHandler(w http.ResponseWriter, r *http.Request) {
+	group, err := h.groupService.GetGroup(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		respondWithError(w, groupErrorStatus(err), err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, Response{Success: true, Data: group})
+}
+
+// UpdateGroupHandler renames a group and replaces its description
+func (h *Handler) UpdateGroupHandler(w http.ResponseWriter, r *http.Request) {
+	var input groupInput
+
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	// Validate input
+	if err := validation.Validate(input); err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	group, err := h.groupService.UpdateGroup(r.Context(), chi.URLParam(r, "id"), input.Name, input.Description)
+	if err != nil {
+		respondWithError(w, groupErrorStatus(err), err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, Response{Success: true, Data: group})
+}
+
+// DeleteGroupHandler deletes a group
+func (h *Handler) DeleteGroupHandler(w http.ResponseWriter, r *http.Request) {
+	if err := h.groupService.DeleteGroup(r.Context(), chi.URLParam(r, "id")); err != nil {
+		respondWithError(w, groupErrorStatus(err), err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, Response{Success: true})
+}
+
+// AddGroupMemberHandler adds a user to a group
+func (h *Handler) AddGroupMemberHandler(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		UserID string `json:"user_id" validate:"required"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	// Validate input
+	if err := validation.Validate(input); err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	group, err := h.groupService.AddMember(r.Context(), chi.URLParam(r, "id"), input.UserID)
+	if err != nil {
+		respondWithError(w, groupErrorStatus(err), err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, Response{Success: true, Data: group})
+}
+
+// RemoveGroupMemberHandler removes a user from a group
+func (h *Handler) RemoveGroupMemberHandler(w http.ResponseWriter, r *http.Request) {
+	group, err := h.groupService.RemoveMember(r.Context(), chi.URLParam(r, "id"), chi.URLParam(r, "userID"))
+	if err != nil {
+		respondWithError(w, groupErrorStatus(err), err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, Response{Success: true, Data: group})
+}
+
+// ListUserGroupsHandler lists the groups a user is a member of
+func (h *Handler) ListUserGroupsHandler(w http.ResponseWriter, r *http.Request) {
+	groups, err := h.groupService.ListUserGroups(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		respondWithError(w, groupErrorStatus(err), err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, Response{Success: true, Data: groups})
+}
+
+// groupErrorStatus maps group management errors to HTTP status codes
+func groupErrorStatus(err error) int {
+	switch err {
+	case domain.ErrGroupNotFound, domain.ErrUserNotFound:
+		return http.StatusNotFound
+	case domain.ErrGroupAlreadyExists:
+		return http.StatusConflict
+	case domain.ErrForbidden:
+		return http.StatusForbidden
+	case domain.ErrUnauthenticated:
+		return http.StatusUnauthorized
+	default:
+		return http.StatusInternalServerError
+	}
+}
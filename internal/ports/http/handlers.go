@@ -2,7 +2,11 @@ package http
 
 import (
 	"encoding/json"
+	"errors"
+	"math"
+	"net"
 	"net/http"
+	"strconv"
 	"strings"
 
 	"github.com/go-chi/chi/v5"
@@ -14,18 +18,144 @@ import (
 
 // Handler holds services needed for HTTP handlers
 type Handler struct {
-	userService *application.UserService
-	authService *application.AuthService
-	jwtAuth     *auth.JWTAuth
+	userService          *application.UserService
+	authService          *application.AuthService
+	jwtAuth              *auth.JWTAuth
+	passwordResetService *application.PasswordResetService
+	verificationService  *application.EmailVerificationService
+	mfaService           *application.MFAService
+	apiKeyService        *application.APIKeyService
+	oauthService         *application.OAuthService
+	oidcService          *application.OIDCService
+	federationService    *application.FederationService
+	magicLinkService     *application.MagicLinkService
+	introspectionService *application.IntrospectionService
+	tenantService        *application.TenantService
+	groupService         *application.GroupService
+	invitationService    *application.InvitationService
+	statusService        *application.AccountStatusService
+	// authorizeLoginURL is where browsers without a session are sent from /oauth/authorize
+	authorizeLoginURL string
+}
+
+// HandlerOption enables optional features on a Handler.
+// Routes for a feature are only registered when its service is set.
+type HandlerOption func(*Handler)
+
+// WithPasswordResetService enables the forgotten password endpoints
+func WithPasswordResetService(service *application.PasswordResetService) HandlerOption {
+	return func(h *Handler) {
+		h.passwordResetService = service
+	}
+}
+
+// WithEmailVerificationService enables the email verification endpoints
+func WithEmailVerificationService(service *application.EmailVerificationService) HandlerOption {
+	return func(h *Handler) {
+		h.verificationService = service
+	}
+}
+
+// WithMFAService enables the multi-factor authentication endpoints
+func WithMFAService(service *application.MFAService) HandlerOption {
+	return func(h *Handler) {
+		h.mfaService = service
+	}
+}
+
+// WithAPIKeyService enables personal API keys, both their endpoints and
+// authenticating requests with them
+func WithAPIKeyService(service *application.APIKeyService) HandlerOption {
+	return func(h *Handler) {
+		h.apiKeyService = service
+	}
+}
+
+// WithOAuthService enables the OAuth2 authorization server endpoints
+func WithOAuthService(service *application.OAuthService) HandlerOption {
+	return func(h *Handler) {
+		h.oauthService = service
+	}
+}
+
+// WithAuthorizeLoginURL sends browsers that open /oauth/authorize without a
+// session to the login page at loginURL
+func WithAuthorizeLoginURL(loginURL string) HandlerOption {
+	return func(h *Handler) {
+		h.authorizeLoginURL = loginURL
+	}
+}
+
+// WithOIDCService enables the OpenID Connect discovery and userinfo endpoints
+func WithOIDCService(service *application.OIDCService) HandlerOption {
+	return func(h *Handler) {
+		h.oidcService = service
+	}
+}
+
+// WithFederationService enables sign in through upstream identity providers
+func WithFederationService(service *application.FederationService) HandlerOption {
+	return func(h *Handler) {
+		h.federationService = service
+	}
+}
+
+// WithMagicLinkService enables passwordless login by email link
+func WithMagicLinkService(service *application.MagicLinkService) HandlerOption {
+	return func(h *Handler) {
+		h.magicLinkService = service
+	}
+}
+
+// WithTenantService resolves the tenant named by unauthenticated requests.
+// Without it only requests with a token can reach users.
+func WithTenantService(service *application.TenantService) HandlerOption {
+	return func(h *Handler) {
+		h.tenantService = service
+	}
+}
+
+// WithGroupService enables the group management endpoints
+func WithGroupService(service *application.GroupService) HandlerOption {
+	return func(h *Handler) {
+		h.groupService = service
+	}
+}
+
+// WithInvitationService enables the invitation endpoints
+func WithInvitationService(service *application.InvitationService) HandlerOption {
+	return func(h *Handler) {
+		h.invitationService = service
+	}
+}
+
+// WithAccountStatusService enables the endpoints that suspend and reactivate users
+func WithAccountStatusService(service *application.AccountStatusService) HandlerOption {
+	return func(h *Handler) {
+		h.statusService = service
+	}
+}
+
+// WithIntrospectionService enables the token introspection endpoint
+func WithIntrospectionService(service *application.IntrospectionService) HandlerOption {
+	return func(h *Handler) {
+		h.introspectionService = service
+	}
 }
 
 // NewHandler creates a new HTTP handler
-func NewHandler(userService *application.UserService, authService *application.AuthService, jwtAuth *auth.JWTAuth) *Handler {
-	return &Handler{
+func NewHandler(userService *application.UserService, authService *application.AuthService, jwtAuth *auth.JWTAuth, opts ...HandlerOption) *Handler {
+	h := &Handler{
 		userService: userService,
 		authService: authService,
 		jwtAuth:     jwtAuth,
 	}
+
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	return h
 }
 
 // Response represents the standard API response format
@@ -40,7 +170,7 @@ func (h *Handler) RegisterHandler(w http.ResponseWriter, r *http.Request) {
 	var input struct {
 		Name     string `json:"name" validate:"required"`
 		Email    string `json:"email" validate:"required,email"`
-		Password string `json:"password" validate:"required,min=6"`
+		Password string `json:"password" validate:"required"`
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
@@ -56,6 +186,10 @@ func (h *Handler) RegisterHandler(w http.ResponseWriter, r *http.Request) {
 
 	user, err := h.authService.Register(r.Context(), input.Name, input.Email, input.Password)
 	if err != nil {
+		if respondWithPasswordPolicyError(w, err) {
+			return
+		}
+
 		status := http.StatusInternalServerError
 		if err == domain.ErrEmailAlreadyExists {
 			status = http.StatusConflict
@@ -67,6 +201,50 @@ func (h *Handler) RegisterHandler(w http.ResponseWriter, r *http.Request) {
 	respondWithJSON(w, http.StatusCreated, Response{Success: true, Data: user})
 }
 
+// CreateUserHandler lets an administrator create a user with a given role
+func (h *Handler) CreateUserHandler(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		Name     string `json:"name" validate:"required"`
+		Email    string `json:"email" validate:"required,email"`
+		Password string `json:"password" validate:"required"`
+		Role     string `json:"role"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	// Validate input
+	if err := validation.Validate(input); err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	role := domain.Role(input.Role).RoleOrDefault()
+	user, err := h.userService.CreateUserWithRole(r.Context(), input.Name, input.Email, input.Password, role)
+	if err != nil {
+		if respondWithPasswordPolicyError(w, err) {
+			return
+		}
+
+		status := http.StatusInternalServerError
+		if err == domain.ErrEmailAlreadyExists {
+			status = http.StatusConflict
+		} else if err == domain.ErrInvalidRole {
+			status = http.StatusBadRequest
+		} else if err == domain.ErrForbidden {
+			status = http.StatusForbidden
+		} else if err == domain.ErrUnauthenticated {
+			status = http.StatusUnauthorized
+		}
+		respondWithError(w, status, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, Response{Success: true, Data: user})
+}
+
 // LoginHandler handles user authentication
 func (h *Handler) LoginHandler(w http.ResponseWriter, r *http.Request) {
 	var input struct {
@@ -85,22 +263,113 @@ func (h *Handler) LoginHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	token, err := h.authService.Login(r.Context(), input.Email, input.Password)
+	result, err := h.authService.Login(r.Context(), input.Email, input.Password, clientIP(r))
 	if err != nil {
+		if respondIfLocked(w, err) {
+			return
+		}
+
 		status := http.StatusInternalServerError
 		if err == domain.ErrInvalidCredentials {
 			status = http.StatusUnauthorized
+		} else if err == domain.ErrEmailNotVerified || err == domain.ErrAccountInactive {
+			status = http.StatusForbidden
 		}
 		respondWithError(w, status, err.Error())
 		return
 	}
 
-	respondWithJSON(w, http.StatusOK, Response{
-		Success: true,
-		Data: map[string]string{
-			"token": token,
-		},
-	})
+	respondWithJSON(w, http.StatusOK, Response{Success: true, Data: result})
+}
+
+// RefreshTokenHandler exchanges a refresh token for a new token pair
+func (h *Handler) RefreshTokenHandler(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		RefreshToken string `json:"refresh_token" validate:"required"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	// Validate input
+	if err := validation.Validate(input); err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	tokens, err := h.authService.Refresh(r.Context(), input.RefreshToken)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err == domain.ErrInvalidToken || err == domain.ErrRefreshTokenReused {
+			status = http.StatusUnauthorized
+		}
+		respondWithError(w, status, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, Response{Success: true, Data: tokens})
+}
+
+// LogoutHandler revokes the caller's access token and optionally their refresh token
+func (h *Handler) LogoutHandler(w http.ResponseWriter, r *http.Request) {
+	claims, ok := r.Context().Value("claims").(*auth.Claims)
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "Missing token claims")
+		return
+	}
+
+	var input struct {
+		RefreshToken string `json:"refresh_token"`
+	}
+
+	// The body is optional
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+			respondWithError(w, http.StatusBadRequest, "Invalid request payload")
+			return
+		}
+	}
+
+	if err := h.authService.Logout(r.Context(), claims, input.RefreshToken); err != nil {
+		respondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, Response{Success: true})
+}
+
+// RevokeAllSessionsHandler invalidates every token issued to a user
+func (h *Handler) RevokeAllSessionsHandler(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		respondWithError(w, http.StatusBadRequest, "Missing user ID")
+		return
+	}
+
+	err := h.authService.RevokeAllSessions(r.Context(), id)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err == domain.ErrUserNotFound {
+			status = http.StatusNotFound
+		} else if err == domain.ErrForbidden {
+			status = http.StatusForbidden
+		} else if err == domain.ErrUnauthenticated {
+			status = http.StatusUnauthorized
+		}
+		respondWithError(w, status, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, Response{Success: true})
+}
+
+// JWKSHandler publishes the public keys used to verify tokens
+func (h *Handler) JWKSHandler(w http.ResponseWriter, r *http.Request) {
+	// Verifiers may cache the key set for a short while
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	respondWithJSON(w, http.StatusOK, h.jwtAuth.JWKS())
 }
 
 // GetUserHandler retrieves a user by ID
@@ -116,6 +385,10 @@ func (h *Handler) GetUserHandler(w http.ResponseWriter, r *http.Request) {
 		status := http.StatusInternalServerError
 		if err == domain.ErrUserNotFound {
 			status = http.StatusNotFound
+		} else if err == domain.ErrForbidden {
+			status = http.StatusForbidden
+		} else if err == domain.ErrUnauthenticated {
+			status = http.StatusUnauthorized
 		}
 		respondWithError(w, status, err.Error())
 		return
@@ -128,7 +401,13 @@ func (h *Handler) GetUserHandler(w http.ResponseWriter, r *http.Request) {
 func (h *Handler) GetAllUsersHandler(w http.ResponseWriter, r *http.Request) {
 	users, err := h.userService.GetAllUsers(r.Context())
 	if err != nil {
-		respondWithError(w, http.StatusInternalServerError, err.Error())
+		status := http.StatusInternalServerError
+		if err == domain.ErrForbidden {
+			status = http.StatusForbidden
+		} else if err == domain.ErrUnauthenticated {
+			status = http.StatusUnauthorized
+		}
+		respondWithError(w, status, err.Error())
 		return
 	}
 
@@ -166,6 +445,52 @@ func (h *Handler) UpdateUserHandler(w http.ResponseWriter, r *http.Request) {
 			status = http.StatusNotFound
 		} else if err == domain.ErrEmailAlreadyExists {
 			status = http.StatusConflict
+		} else if err == domain.ErrForbidden {
+			status = http.StatusForbidden
+		} else if err == domain.ErrUnauthenticated {
+			status = http.StatusUnauthorized
+		}
+		respondWithError(w, status, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, Response{Success: true, Data: user})
+}
+
+// ChangeRoleHandler assigns a new role to a user
+func (h *Handler) ChangeRoleHandler(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		respondWithError(w, http.StatusBadRequest, "Missing user ID")
+		return
+	}
+
+	var input struct {
+		Role string `json:"role" validate:"required"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	// Validate input
+	if err := validation.Validate(input); err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	user, err := h.userService.ChangeRole(r.Context(), id, domain.Role(input.Role))
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err == domain.ErrUserNotFound {
+			status = http.StatusNotFound
+		} else if err == domain.ErrInvalidRole {
+			status = http.StatusBadRequest
+		} else if err == domain.ErrForbidden {
+			status = http.StatusForbidden
+		} else if err == domain.ErrUnauthenticated {
+			status = http.StatusUnauthorized
 		}
 		respondWithError(w, status, err.Error())
 		return
@@ -187,6 +512,10 @@ func (h *Handler) DeleteUserHandler(w http.ResponseWriter, r *http.Request) {
 		status := http.StatusInternalServerError
 		if err == domain.ErrUserNotFound {
 			status = http.StatusNotFound
+		} else if err == domain.ErrForbidden {
+			status = http.StatusForbidden
+		} else if err == domain.ErrUnauthenticated {
+			status = http.StatusUnauthorized
 		}
 		respondWithError(w, status, err.Error())
 		return
@@ -195,6 +524,66 @@ func (h *Handler) DeleteUserHandler(w http.ResponseWriter, r *http.Request) {
 	respondWithJSON(w, http.StatusOK, Response{Success: true})
 }
 
+// RestoreUserHandler restores a deleted user
+func (h *Handler) RestoreUserHandler(w http.ResponseWriter, r *http.Request) {
+	user, err := h.userService.RestoreUser(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err == domain.ErrUserNotFound {
+			status = http.StatusNotFound
+		} else if err == domain.ErrEmailAlreadyExists {
+			status = http.StatusConflict
+		} else if err == domain.ErrForbidden {
+			status = http.StatusForbidden
+		} else if err == domain.ErrUnauthenticated {
+			status = http.StatusUnauthorized
+		}
+		respondWithError(w, status, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, Response{Success: true, Data: user})
+}
+
+// ListDeletedUsersHandler lists the deleted users that can still be restored
+func (h *Handler) ListDeletedUsersHandler(w http.ResponseWriter, r *http.Request) {
+	users, err := h.userService.ListDeletedUsers(r.Context())
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err == domain.ErrForbidden {
+			status = http.StatusForbidden
+		} else if err == domain.ErrUnauthenticated {
+			status = http.StatusUnauthorized
+		}
+		respondWithError(w, status, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, Response{Success: true, Data: users})
+}
+
+// respondIfLocked answers 429 with Retry-After if err is a *domain.LoginLockedError
+func respondIfLocked(w http.ResponseWriter, err error) bool {
+	var locked *domain.LoginLockedError
+	if !errors.As(err, &locked) {
+		return false
+	}
+
+	w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(locked.RetryAfter.Seconds()))))
+	respondWithError(w, http.StatusTooManyRequests, err.Error())
+	return true
+}
+
+// clientIP returns the caller's IP address. middleware.RealIP has already
+// replaced RemoteAddr with the forwarded address when there is one.
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
 // Helper functions for HTTP responses
 func respondWithError(w http.ResponseWriter, code int, message string) {
 	respondWithJSON(w, code, Response{Success: false, Error: message})
@@ -206,3 +595,19 @@ func respondWithJSON(w http.ResponseWriter, code int, payload interface{}) {
 	w.WriteHeader(code)
 	w.Write(response)
 }
+
+// respondWithPasswordPolicyError lists the unmet password rules if err is a
+// password policy error, and reports whether it responded
+func respondWithPasswordPolicyError(w http.ResponseWriter, err error) bool {
+	var weak *domain.WeakPasswordError
+	if !errors.As(err, &weak) {
+		return false
+	}
+
+	respondWithJSON(w, http.StatusBadRequest, Response{
+		Success: false,
+		Data:    map[string]interface{}{"violations": weak.Violations},
+		Error:   err.Error(),
+	})
+	return true
+}
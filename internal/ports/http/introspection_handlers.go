@@ -0,0 +1,61 @@
+package http
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/yourusername/userapi/internal/application"
+	"github.com/yourusername/userapi/internal/domain"
+)
+
+// IntrospectionHandler is the token introspection endpoint (RFC 7662).
+// Callers authenticate as a confidential OAuth client, with HTTP Basic or
+// client_id and client_secret in the form, or with an admin's API key.
+func (h *Handler) IntrospectionHandler(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		respondWithOAuthError(w, domain.NewOAuthError(application.OAuthErrInvalidRequest, "invalid form body"))
+		return
+	}
+
+	token := r.PostForm.Get("token")
+	if token == "" {
+		respondWithOAuthError(w, domain.NewOAuthError(application.OAuthErrInvalidRequest, "token is required"))
+		return
+	}
+
+	introspection, err := h.introspectionService.Introspect(r.Context(), introspectionCaller(r), token)
+	if err != nil {
+		if err == domain.ErrUnauthenticated {
+			respondWithOAuthError(w, domain.NewOAuthError(application.OAuthErrInvalidClient, ""))
+		} else if err == domain.ErrForbidden {
+			respondWithError(w, http.StatusForbidden, err.Error())
+		} else {
+			respondWithOAuthError(w, domain.NewOAuthError("server_error", ""))
+		}
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Pragma", "no-cache")
+	respondWithJSON(w, http.StatusOK, introspection)
+}
+
+// introspectionCaller reads the caller's credentials the same way
+// AuthMiddleware reads API keys and TokenHandler reads client credentials
+func introspectionCaller(r *http.Request) application.IntrospectionCaller {
+	authHeader := r.Header.Get("Authorization")
+	if apiKey := r.Header.Get("X-API-Key"); apiKey != "" {
+		return application.IntrospectionCaller{APIKey: apiKey}
+	}
+	if strings.HasPrefix(strings.ToLower(authHeader), "apikey ") {
+		return application.IntrospectionCaller{APIKey: strings.TrimSpace(authHeader[len("apikey "):])}
+	}
+
+	clientID, clientSecret, ok := r.BasicAuth()
+	if !ok {
+		clientID = r.PostForm.Get("client_id")
+		clientSecret = r.PostForm.Get("client_secret")
+	}
+
+	return application.IntrospectionCaller{ClientID: clientID, ClientSecret: clientSecret}
+}
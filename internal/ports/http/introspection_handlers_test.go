@@ -0,0 +1,44 @@
+package http
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestIntrospectionIsScopedToTheCallersTenant(t *testing.T) {
+	s := newOAuthTestServer(t)
+	clientID, secret := s.registerClient(`{"name":"Resource server","grant_types":["client_credentials"],"scopes":["profile"]}`)
+
+	tests := []struct {
+		name       string
+		token      string
+		wantActive bool
+	}{
+		{"token of the client's tenant", s.adminToken, true},
+		{"token of another tenant", s.otherAdminToken, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodPost, s.api.URL+"/introspect", strings.NewReader(url.Values{"token": {tt.token}}.Encode()))
+			if err != nil {
+				t.Fatal(err)
+			}
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			req.SetBasicAuth(clientID, secret)
+
+			var body map[string]interface{}
+			s.send(req, http.StatusOK, &body)
+
+			if body["active"] != tt.wantActive {
+				t.Fatalf("active = %v, want %v", body["active"], tt.wantActive)
+			}
+			// An inactive token reveals nothing about its user
+			if !tt.wantActive && len(body) != 1 {
+				t.Errorf("inactive token is described: %v", body)
+			}
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/yourusername/userapi/internal/domain"
+	"github.com/yourusername/userapi/pkg/validation"
+)
+
+// InviteHandler invites someone to the caller's tenant
+func (h *Handler) InviteHandler(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		Email    string   `json:"email" validate:"required,email"`
+		Role     string   `json:"role"`
+		GroupIDs []string `json:"group_ids"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	// Validate input
+	if err := validation.Validate(input); err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	invitation, err := h.invitationService.Invite(r.Context(), input.Email, domain.Role(input.Role), input.GroupIDs)
+	if err != nil {
+		respondWithError(w, invitationErrorStatus(err), err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, Response{Success: true, Data: invitation})
+}
+
+// ListInvitationsHandler lists the pending invitations
+func (h *Handler) ListInvitationsHandler(w http.ResponseWriter, r *http.Request) {
+	invitations, err := h.invitationService.ListPending(r.Context())
+	if err != nil {
+		respondWithError(w, invitationErrorStatus(err), err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, Response{Success: true, Data: invitations})
+}
+
+// ResendInvitationHandler emails a new link for a pending invitation
+func (h *Handler) ResendInvitationHandler(w http.ResponseWriter, r *http.Request) {
+	invitation, err := h.invitationService.Resend(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		respondWithError(w, invitationErrorStatus(err), err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, Response{Success: true, Data: invitation})
+}
+
+// RevokeInvitationHandler withdraws a pending invitation
+func (h *Handler) RevokeInvitationHandler(w http.ResponseWriter, r *http.Request) {
+	if err := h.invitationService.Revoke(r.Context(), chi.URLParam(r, "id")); err != nil {
+		respondWithError(w, invitationErrorStatus(err), err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, Response{Success: true})
+}
+
+// AcceptInvitationHandler creates the invitee's account using an invitation token
+func (h *Handler) AcceptInvitationHandler(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		Token    string `json:"token" validate:"required"`
+		Name     string `json:"name" validate:"required"`
+		Password string `json:"password" validate:"required"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	// Validate input
+	if err := validation.Validate(input); err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	user, err := h.invitationService.Accept(r.Context(), input.Token, input.Name, input.Password)
+	if err != nil {
+		if respondWithPasswordPolicyError(w, err) {
+			return
+		}
+
+		status := http.StatusInternalServerError
+		if err == domain.ErrInvalidToken {
+			status = http.StatusBadRequest
+		} else if err == domain.ErrEmailAlreadyExists {
+			status = http.StatusConflict
+		}
+		respondWithError(w, status, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, Response{Success: true, Data: user})
+}
+
+// invitationErrorStatus maps invitation management errors to HTTP status codes
+func invitationErrorStatus(err error) int {
+	switch err {
+	case domain.ErrInvitationNotFound, domain.ErrGroupNotFound:
+		return http.StatusNotFound
+	case domain.ErrInvitationPending, domain.ErrEmailAlreadyExists:
+		return http.StatusConflict
+	case domain.ErrInvalidRole:
+		return http.StatusBadRequest
+	case domain.ErrForbidden:
+		return http.StatusForbidden
+	case domain.ErrUnauthenticated:
+		return http.StatusUnauthorized
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,108 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/yourusername/userapi/internal/domain"
+	"github.com/yourusername/userapi/pkg/validation"
+)
+
+// magicLinkNonceCookie binds a login link to the browser that requested it
+const magicLinkNonceCookie = "magic_link_nonce"
+
+// magicLinkCookiePath covers both the request and the redeem endpoint
+const magicLinkCookiePath = "/login/magic"
+
+// RequestMagicLinkHandler emails a one-time login link
+func (h *Handler) RequestMagicLinkHandler(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		Email string `json:"email" validate:"required,email"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	// Validate input
+	if err := validation.Validate(input); err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	nonce, err := h.magicLinkService.RequestLink(r.Context(), input.Email)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err == domain.ErrTooManyMagicLinks {
+			status = http.StatusTooManyRequests
+		}
+		respondWithError(w, status, err.Error())
+		return
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     magicLinkNonceCookie,
+		Value:    nonce,
+		Path:     magicLinkCookiePath,
+		HttpOnly: true,
+		Secure:   true,
+		// Lax lets the cookie through when the link is opened from a mail client
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	respondWithJSON(w, http.StatusAccepted, Response{
+		Success: true,
+		Data: map[string]string{
+			"message": "If an account exists for this email, a login link has been sent",
+		},
+	})
+}
+
+// RedeemMagicLinkHandler logs the user in with a login link token,
+// responding like LoginHandler
+func (h *Handler) RedeemMagicLinkHandler(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		Token string `json:"token" validate:"required"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	// Validate input
+	if err := validation.Validate(input); err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var nonce string
+	if cookie, err := r.Cookie(magicLinkNonceCookie); err == nil {
+		nonce = cookie.Value
+	}
+
+	result, err := h.magicLinkService.Redeem(r.Context(), input.Token, nonce)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err == domain.ErrInvalidToken {
+			status = http.StatusUnauthorized
+		} else if err == domain.ErrEmailNotVerified || err == domain.ErrAccountInactive {
+			status = http.StatusForbidden
+		}
+		respondWithError(w, status, err.Error())
+		return
+	}
+
+	// The nonce has served its purpose
+	http.SetCookie(w, &http.Cookie{
+		Name:     magicLinkNonceCookie,
+		Path:     magicLinkCookiePath,
+		MaxAge:   -1,
+		HttpOnly: true,
+		Secure:   true,
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	respondWithJSON(w, http.StatusOK, Response{Success: true, Data: result})
+}
@@ -0,0 +1,139 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/yourusername/userapi/internal/domain"
+	"github.com/yourusername/userapi/pkg/validation"
+)
+
+// GetMeHandler returns the authenticated user
+func (h *Handler) GetMeHandler(w http.ResponseWriter, r *http.Request) {
+	userID, _ := r.Context().Value("userID").(string)
+
+	user, err := h.userService.GetUserByID(r.Context(), userID)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err == domain.ErrUserNotFound {
+			status = http.StatusNotFound
+		}
+		respondWithError(w, status, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, Response{Success: true, Data: user})
+}
+
+// UpdateMeHandler partially updates the authenticated user's profile
+func (h *Handler) UpdateMeHandler(w http.ResponseWriter, r *http.Request) {
+	userID, _ := r.Context().Value("userID").(string)
+
+	var input struct {
+		Name  *string `json:"name" validate:"omitempty,min=1"`
+		Email *string `json:"email" validate:"omitempty,email"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	// Validate input
+	if err := validation.Validate(input); err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	user, err := h.userService.GetUserByID(r.Context(), userID)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err == domain.ErrUserNotFound {
+			status = http.StatusNotFound
+		}
+		respondWithError(w, status, err.Error())
+		return
+	}
+
+	// Keep fields that were not sent
+	name, email := user.Name, user.Email
+	if input.Name != nil {
+		name = *input.Name
+	}
+	if input.Email != nil {
+		email = *input.Email
+	}
+
+	user, err = h.userService.UpdateUser(r.Context(), userID, name, email)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err == domain.ErrUserNotFound {
+			status = http.StatusNotFound
+		} else if err == domain.ErrEmailAlreadyExists {
+			status = http.StatusConflict
+		}
+		respondWithError(w, status, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, Response{Success: true, Data: user})
+}
+
+// ChangeMyPasswordHandler changes the authenticated user's password.
+// Every other session is revoked and a fresh token pair is returned.
+func (h *Handler) ChangeMyPasswordHandler(w http.ResponseWriter, r *http.Request) {
+	userID, _ := r.Context().Value("userID").(string)
+
+	var input struct {
+		CurrentPassword string `json:"current_password" validate:"required"`
+		NewPassword     string `json:"new_password" validate:"required"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	// Validate input
+	if err := validation.Validate(input); err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	tokens, err := h.authService.ChangePassword(r.Context(), userID, input.CurrentPassword, input.NewPassword)
+	if err != nil {
+		if respondWithPasswordPolicyError(w, err) {
+			return
+		}
+
+		status := http.StatusInternalServerError
+		if err == domain.ErrInvalidCredentials {
+			status = http.StatusUnauthorized
+		} else if err == domain.ErrUserNotFound {
+			status = http.StatusNotFound
+		} else if err == domain.ErrPasswordReused {
+			status = http.StatusUnprocessableEntity
+		}
+		respondWithError(w, status, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, Response{Success: true, Data: tokens})
+}
+
+// DeleteMeHandler deletes the authenticated user's account
+func (h *Handler) DeleteMeHandler(w http.ResponseWriter, r *http.Request) {
+	userID, _ := r.Context().Value("userID").(string)
+
+	err := h.authService.DeleteAccount(r.Context(), userID)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err == domain.ErrUserNotFound {
+			status = http.StatusNotFound
+		}
+		respondWithError(w, status, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, Response{Success: true})
+}
@@ -0,0 +1,149 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/yourusername/userapi/internal/domain"
+	"github.com/yourusername/userapi/pkg/validation"
+)
+
+// mfaCodeInput is the request body of endpoints that take an authentication code
+type mfaCodeInput struct {
+	Code string `json:"code" validate:"required"`
+}
+
+// BeginMFAEnrollmentHandler generates a TOTP secret for the authenticated user
+func (h *Handler) BeginMFAEnrollmentHandler(w http.ResponseWriter, r *http.Request) {
+	userID, _ := r.Context().Value("userID").(string)
+
+	enrollment, err := h.mfaService.BeginEnrollment(r.Context(), userID)
+	if err != nil {
+		respondWithError(w, mfaErrorStatus(err), err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, Response{Success: true, Data: enrollment})
+}
+
+// ConfirmMFAEnrollmentHandler enables MFA and returns the recovery codes
+func (h *Handler) ConfirmMFAEnrollmentHandler(w http.ResponseWriter, r *http.Request) {
+	userID, _ := r.Context().Value("userID").(string)
+
+	var input mfaCodeInput
+	if !decodeMFACode(w, r, &input) {
+		return
+	}
+
+	codes, err := h.mfaService.ConfirmEnrollment(r.Context(), userID, input.Code)
+	if err != nil {
+		respondWithError(w, mfaErrorStatus(err), err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, Response{Success: true, Data: map[string][]string{"recovery_codes": codes}})
+}
+
+// DisableMFAHandler turns MFA off for the authenticated user
+func (h *Handler) DisableMFAHandler(w http.ResponseWriter, r *http.Request) {
+	userID, _ := r.Context().Value("userID").(string)
+
+	var input mfaCodeInput
+	if !decodeMFACode(w, r, &input) {
+		return
+	}
+
+	if err := h.mfaService.Disable(r.Context(), userID, input.Code); err != nil {
+		respondWithError(w, mfaErrorStatus(err), err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, Response{Success: true})
+}
+
+// RegenerateRecoveryCodesHandler replaces the authenticated user's recovery codes
+func (h *Handler) RegenerateRecoveryCodesHandler(w http.ResponseWriter, r *http.Request) {
+	userID, _ := r.Context().Value("userID").(string)
+
+	var input mfaCodeInput
+	if !decodeMFACode(w, r, &input) {
+		return
+	}
+
+	codes, err := h.mfaService.RegenerateRecoveryCodes(r.Context(), userID, input.Code)
+	if err != nil {
+		respondWithError(w, mfaErrorStatus(err), err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, Response{Success: true, Data: map[string][]string{"recovery_codes": codes}})
+}
+
+// CompleteMFALoginHandler exchanges an MFA challenge token and a code for a token pair
+func (h *Handler) CompleteMFALoginHandler(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		MFAToken string `json:"mfa_token" validate:"required"`
+		Code     string `json:"code" validate:"required"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	// Validate input
+	if err := validation.Validate(input); err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	tokens, err := h.mfaService.CompleteLogin(r.Context(), input.MFAToken, input.Code, clientIP(r))
+	if err != nil {
+		if respondIfLocked(w, err) {
+			return
+		}
+
+		status := http.StatusInternalServerError
+		if err == domain.ErrInvalidToken || err == domain.ErrInvalidMFACode {
+			status = http.StatusUnauthorized
+		}
+		respondWithError(w, status, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, Response{Success: true, Data: tokens})
+}
+
+// decodeMFACode decodes and validates a code request body, responding on failure
+func decodeMFACode(w http.ResponseWriter, r *http.Request, input *mfaCodeInput) bool {
+	if err := json.NewDecoder(r.Body).Decode(input); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request payload")
+		return false
+	}
+
+	// Validate input
+	if err := validation.Validate(input); err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return false
+	}
+
+	return true
+}
+
+// mfaErrorStatus maps MFA management errors to HTTP status codes
+func mfaErrorStatus(err error) int {
+	switch err {
+	case domain.ErrInvalidMFACode:
+		return http.StatusUnauthorized
+	case domain.ErrMFAAlreadyEnabled, domain.ErrMFANotEnabled, domain.ErrMFANotEnrolling:
+		return http.StatusConflict
+	case domain.ErrUserNotFound:
+		return http.StatusNotFound
+	case domain.ErrForbidden:
+		return http.StatusForbidden
+	case domain.ErrUnauthenticated:
+		return http.StatusUnauthorized
+	default:
+		return http.StatusInternalServerError
+	}
+}
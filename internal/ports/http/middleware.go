@@ -2,14 +2,24 @@ package http
 
 import (
 	"context"
+	"fmt"
 	"log"
 	"net/http"
+	"net/url"
 	"strings"
 	"time"
 
+	"github.com/go-chi/chi/v5"
+	"github.com/yourusername/userapi/internal/application"
+	"github.com/yourusername/userapi/internal/domain"
+	"github.com/yourusername/userapi/internal/ports/repository"
 	"github.com/yourusername/userapi/pkg/auth"
 )
 
+// TenantHeader names the tenant, by its slug, of a request that is not
+// authenticated yet, e.g. a login
+const TenantHeader = "X-Tenant"
+
 // Middleware type
 type Middleware func(http.Handler) http.Handler
 
@@ -17,10 +27,10 @@ type Middleware func(http.Handler) http.Handler
 func LoggingMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		start := time.Now()
-		
+
 		// Call the next handler
 		next.ServeHTTP(w, r)
-		
+
 		// Log the request details
 		log.Printf(
 			"Method: %s\tPath: %s\tTime: %v",
@@ -31,37 +41,194 @@ func LoggingMiddleware(next http.Handler) http.Handler {
 	})
 }
 
-// AuthMiddleware validates JWT tokens
-func AuthMiddleware(jwtAuth *auth.JWTAuth) Middleware {
+// TenantMiddleware scopes the request to the tenant named in TenantHeader.
+// Requests without the header are left unscoped, see RequireTenant.
+func TenantMiddleware(tenants *application.TenantService) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			slug := r.Header.Get(TenantHeader)
+			if slug == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			tenant, err := tenants.ResolveTenant(r.Context(), slug)
+			if err != nil {
+				if err == domain.ErrTenantNotFound {
+					respondWithError(w, http.StatusBadRequest, "Unknown tenant")
+				} else {
+					respondWithError(w, http.StatusInternalServerError, err.Error())
+				}
+				return
+			}
+
+			ctx := repository.WithTenant(r.Context(), tenant.ID.Hex())
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// RequireTenant rejects unauthenticated requests that look users up by email
+// address but do not name a tenant. It must run after TenantMiddleware.
+func RequireTenant(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := repository.TenantFromContext(r.Context()); !ok {
+			respondWithError(w, http.StatusBadRequest, fmt.Sprintf("%s header is required", TenantHeader))
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// AuthMiddleware validates JWT tokens and, when apiKeys is set, API keys sent
+// as "Authorization: ApiKey {key}" or in the X-API-Key header
+func AuthMiddleware(jwtAuth *auth.JWTAuth, apiKeys *application.APIKeyService) Middleware {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			// Get token from Authorization header
 			authHeader := r.Header.Get("Authorization")
-			if authHeader == "" {
+			apiKey := r.Header.Get("X-API-Key")
+			if authHeader == "" && apiKey == "" {
 				respondWithError(w, http.StatusUnauthorized, "Authorization header is required")
 				return
 			}
-			
-			// Check if the header format is valid
-			bearerToken := strings.Split(authHeader, " ")
-			if len(bearerToken) != 2 || strings.ToLower(bearerToken[0]) != "bearer" {
-				respondWithError(w, http.StatusUnauthorized, "Invalid authorization format. Format: Bearer {token}")
-				return
+
+			var claims *auth.Claims
+			var err error
+			if apiKey != "" || strings.HasPrefix(strings.ToLower(authHeader), "apikey ") {
+				if apiKeys == nil {
+					respondWithError(w, http.StatusUnauthorized, "API keys are not accepted")
+					return
+				}
+
+				if apiKey == "" {
+					apiKey = strings.TrimSpace(authHeader[len("apikey "):])
+				}
+
+				claims, err = apiKeys.Authenticate(r.Context(), apiKey)
+				if err != nil {
+					respondWithError(w, http.StatusUnauthorized, "Invalid or expired API key")
+					return
+				}
+			} else {
+				// Check if the header format is valid
+				bearerToken := strings.Split(authHeader, " ")
+				if len(bearerToken) != 2 || strings.ToLower(bearerToken[0]) != "bearer" {
+					respondWithError(w, http.StatusUnauthorized, "Invalid authorization format. Format: Bearer {token}")
+					return
+				}
+
+				// Validate token
+				claims, err = jwtAuth.ValidateToken(r.Context(), bearerToken[1])
+				if err != nil {
+					respondWithError(w, http.StatusUnauthorized, "Invalid or expired token")
+					return
+				}
 			}
-			
-			// Validate token
-			claims, err := jwtAuth.ValidateToken(bearerToken[1])
-			if err != nil {
+
+			// Tokens issued to a user before tenants existed cannot be scoped
+			if claims.UserID != "" && claims.TenantID == "" {
 				respondWithError(w, http.StatusUnauthorized, "Invalid or expired token")
 				return
 			}
-			
-			// Set user ID in context
-			ctx := context.WithValue(r.Context(), "userID", claims.UserID)
-			ctx = context.WithValue(ctx, "email", claims.Email)
-			
+
 			// Call the next handler with our new context
-			next.ServeHTTP(w, r.WithContext(ctx))
+			next.ServeHTTP(w, r.WithContext(withClaims(r.Context(), claims)))
+		})
+	}
+}
+
+// SessionCookieMiddleware authenticates browsers with the access token that
+// POST /oauth/session stored in a cookie. Browsers reach the authorization
+// endpoint by redirect and cannot send an Authorization header. Without a
+// valid session a GET is sent to loginURL, if set, with a return_to parameter
+// holding the path to come back to.
+func SessionCookieMiddleware(jwtAuth *auth.JWTAuth, loginURL string) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var claims *auth.Claims
+			cookie, err := r.Cookie(oauthSessionCookie)
+			if err == nil {
+				claims, err = jwtAuth.ValidateToken(r.Context(), cookie.Value)
+			}
+
+			// Only a user's own first-party token makes a session
+			if err != nil || claims.UserID == "" || claims.TenantID == "" || claims.ClientID != "" {
+				if r.Method == http.MethodGet && loginURL != "" {
+					http.Redirect(w, r, loginURL+"?"+url.Values{"return_to": {r.URL.RequestURI()}}.Encode(), http.StatusFound)
+					return
+				}
+				respondWithError(w, http.StatusUnauthorized, "Sign in to authorize applications")
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(withClaims(r.Context(), claims)))
 		})
 	}
-}
\ No newline at end of file
+}
+
+// withClaims scopes ctx to the tenant and principal of a validated token
+func withClaims(ctx context.Context, claims *auth.Claims) context.Context {
+	// The token decides the tenant, whatever the request names
+	ctx = repository.WithTenant(ctx, claims.TenantID)
+
+	// Set user ID in context
+	ctx = context.WithValue(ctx, "userID", claims.UserID)
+	ctx = context.WithValue(ctx, "email", claims.Email)
+	ctx = context.WithValue(ctx, "claims", claims)
+	return application.WithPrincipal(ctx, &application.Principal{
+		UserID: claims.UserID,
+		Email:  claims.Email,
+		Role:   domain.Role(claims.Role),
+	})
+}
+
+// RequireScope rejects requests whose token was not granted every given scope.
+// It must run after AuthMiddleware.
+func RequireScope(scopes ...string) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if checkScopes(w, r, scopes) {
+				next.ServeHTTP(w, r)
+			}
+		})
+	}
+}
+
+// RequireScopeOrSelf is RequireScope for routes about the user named by the
+// {id} route parameter. A caller acting on their own account only needs
+// selfScope. It must run after AuthMiddleware.
+func RequireScopeOrSelf(selfScope string, scopes ...string) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			required := scopes
+			if claims, ok := r.Context().Value("claims").(*auth.Claims); ok && claims.UserID != "" && claims.UserID == chi.URLParam(r, "id") {
+				required = []string{selfScope}
+			}
+
+			if checkScopes(w, r, required) {
+				next.ServeHTTP(w, r)
+			}
+		})
+	}
+}
+
+// checkScopes responds with an error unless the request's token was granted
+// every given scope
+func checkScopes(w http.ResponseWriter, r *http.Request, scopes []string) bool {
+	claims, ok := r.Context().Value("claims").(*auth.Claims)
+	if !ok {
+		respondWithError(w, http.StatusUnauthorized, "Authorization header is required")
+		return false
+	}
+
+	for _, scope := range scopes {
+		if !claims.HasScope(scope) {
+			respondWithError(w, http.StatusForbidden, fmt.Sprintf("Token is missing required scope: %s", scope))
+			return false
+		}
+	}
+
+	return true
+}
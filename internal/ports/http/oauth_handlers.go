@@ -0,0 +1,266 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/yourusername/userapi/internal/application"
+	"github.com/yourusername/userapi/internal/domain"
+	"github.com/yourusername/userapi/pkg/auth"
+	"github.com/yourusername/userapi/pkg/validation"
+)
+
+// oauthSessionCookie holds the access token that authenticates a browser at
+// /oauth/authorize. SameSite=Lax keeps it off cross-site posts, so another
+// site cannot approve a consent on the user's behalf.
+const oauthSessionCookie = "oauth_session"
+
+// RegisterOAuthClientHandler lets an administrator register an OAuth client.
+// The client secret is only part of this response.
+func (h *Handler) RegisterOAuthClientHandler(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		Name         string   `json:"name" validate:"required"`
+		Public       bool     `json:"public"`
+		RedirectURIs []string `json:"redirect_uris"`
+		GrantTypes   []string `json:"grant_types"`
+		Scopes       []string `json:"scopes" validate:"required"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	// Validate input
+	if err := validation.Validate(input); err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	client, secret, err := h.oauthService.RegisterClient(r.Context(), application.OAuthClientRegistration{
+		Name:         input.Name,
+		Public:       input.Public,
+		RedirectURIs: input.RedirectURIs,
+		GrantTypes:   input.GrantTypes,
+		Scopes:       input.Scopes,
+	})
+	if err != nil {
+		var oauthErr *domain.OAuthError
+		status := http.StatusInternalServerError
+		if errors.As(err, &oauthErr) || err == domain.ErrInvalidScope {
+			status = http.StatusBadRequest
+		} else if err == domain.ErrForbidden {
+			status = http.StatusForbidden
+		} else if err == domain.ErrUnauthenticated {
+			status = http.StatusUnauthorized
+		}
+		respondWithError(w, status, err.Error())
+		return
+	}
+
+	data := map[string]interface{}{"client": client}
+	if secret != "" {
+		data["client_secret"] = secret
+	}
+
+	respondWithJSON(w, http.StatusCreated, Response{Success: true, Data: data})
+}
+
+// ListOAuthClientsHandler lists the registered OAuth clients
+func (h *Handler) ListOAuthClientsHandler(w http.ResponseWriter, r *http.Request) {
+	clients, err := h.oauthService.ListClients(r.Context())
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err == domain.ErrForbidden {
+			status = http.StatusForbidden
+		} else if err == domain.ErrUnauthenticated {
+			status = http.StatusUnauthorized
+		}
+		respondWithError(w, status, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, Response{Success: true, Data: clients})
+}
+
+// CreateOAuthSessionHandler stores the caller's access token in the session
+// cookie, so their browser can be redirected to /oauth/authorize. It ends
+// when the token expires or is revoked.
+func (h *Handler) CreateOAuthSessionHandler(w http.ResponseWriter, r *http.Request) {
+	// Tokens issued to OAuth clients must not be used to authorize other clients
+	claims, ok := r.Context().Value("claims").(*auth.Claims)
+	if !ok || claims.ClientID != "" || claims.ExpiresAt == nil {
+		respondWithError(w, http.StatusForbidden, "Sign in directly to authorize applications")
+		return
+	}
+
+	// AuthMiddleware has checked the header is "Bearer {token}"
+	token := strings.Fields(r.Header.Get("Authorization"))[1]
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     oauthSessionCookie,
+		Value:    token,
+		Path:     "/oauth/authorize",
+		Expires:  claims.ExpiresAt.Time,
+		HttpOnly: true,
+		Secure:   true,
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// AuthorizeHandler handles GET and POST /oauth/authorize for the signed in user.
+// GET redirects with a code if the user already consented, otherwise it
+// describes the consent needed. POST with decision=approve records consent.
+func (h *Handler) AuthorizeHandler(w http.ResponseWriter, r *http.Request) {
+	userID, _ := r.Context().Value("userID").(string)
+
+	// Tokens issued to OAuth clients must not be used to authorize other clients
+	if claims, ok := r.Context().Value("claims").(*auth.Claims); !ok || claims.ClientID != "" {
+		respondWithError(w, http.StatusForbidden, "Sign in directly to authorize applications")
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	req := &application.AuthorizeRequest{
+		ResponseType:        r.Form.Get("response_type"),
+		ClientID:            r.Form.Get("client_id"),
+		RedirectURI:         r.Form.Get("redirect_uri"),
+		Scope:               r.Form.Get("scope"),
+		State:               r.Form.Get("state"),
+		CodeChallenge:       r.Form.Get("code_challenge"),
+		CodeChallengeMethod: r.Form.Get("code_challenge_method"),
+		Nonce:               r.Form.Get("nonce"),
+	}
+
+	if r.Method == http.MethodPost && r.Form.Get("decision") == "deny" {
+		if _, _, err := h.oauthService.ValidateAuthorizeRequest(r.Context(), req); err != nil {
+			respondWithAuthorizeError(w, r, req, err)
+			return
+		}
+		redirectWithParams(w, r, req.RedirectURI, url.Values{
+			"error": {application.OAuthErrAccessDenied},
+			"state": {req.State},
+		})
+		return
+	}
+
+	approve := r.Method == http.MethodPost && r.Form.Get("decision") == "approve"
+	result, err := h.oauthService.Authorize(r.Context(), userID, req, approve)
+	if err != nil {
+		respondWithAuthorizeError(w, r, req, err)
+		return
+	}
+
+	if result.ConsentRequired {
+		respondWithJSON(w, http.StatusOK, Response{Success: true, Data: result})
+		return
+	}
+
+	redirectWithParams(w, r, result.RedirectURI, url.Values{
+		"code":  {result.Code},
+		"state": {result.State},
+	})
+}
+
+// TokenHandler is the OAuth2 token endpoint. Clients authenticate with HTTP
+// Basic or with client_id and client_secret in the form.
+func (h *Handler) TokenHandler(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		respondWithOAuthError(w, domain.NewOAuthError(application.OAuthErrInvalidRequest, "invalid form body"))
+		return
+	}
+
+	clientID, clientSecret, ok := r.BasicAuth()
+	if !ok {
+		clientID = r.PostForm.Get("client_id")
+		clientSecret = r.PostForm.Get("client_secret")
+	}
+
+	resp, err := h.oauthService.Token(r.Context(), &application.TokenRequest{
+		GrantType:    r.PostForm.Get("grant_type"),
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		Code:         r.PostForm.Get("code"),
+		RedirectURI:  r.PostForm.Get("redirect_uri"),
+		CodeVerifier: r.PostForm.Get("code_verifier"),
+		RefreshToken: r.PostForm.Get("refresh_token"),
+		Scope:        r.PostForm.Get("scope"),
+	})
+	if err != nil {
+		var oauthErr *domain.OAuthError
+		if !errors.As(err, &oauthErr) {
+			oauthErr = domain.NewOAuthError("server_error", "")
+		}
+		respondWithOAuthError(w, oauthErr)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Pragma", "no-cache")
+	respondWithJSON(w, http.StatusOK, resp)
+}
+
+// respondWithAuthorizeError redirects authorization errors back to the client
+// once its redirect URI is trusted, and reports them directly otherwise
+func respondWithAuthorizeError(w http.ResponseWriter, r *http.Request, req *application.AuthorizeRequest, err error) {
+	var oauthErr *domain.OAuthError
+	if !errors.As(err, &oauthErr) {
+		status := http.StatusInternalServerError
+		if err == domain.ErrOAuthClientNotFound || err == domain.ErrInvalidRedirectURI {
+			status = http.StatusBadRequest
+		} else if err == domain.ErrUserNotFound {
+			status = http.StatusNotFound
+		}
+		respondWithError(w, status, err.Error())
+		return
+	}
+
+	redirectWithParams(w, r, req.RedirectURI, url.Values{
+		"error":             {oauthErr.Code},
+		"error_description": {oauthErr.Description},
+		"state":             {req.State},
+	})
+}
+
+// respondWithOAuthError writes a token endpoint error response (RFC 6749 section 5.2)
+func respondWithOAuthError(w http.ResponseWriter, oauthErr *domain.OAuthError) {
+	status := http.StatusBadRequest
+	switch oauthErr.Code {
+	case application.OAuthErrInvalidClient:
+		status = http.StatusUnauthorized
+		w.Header().Set("WWW-Authenticate", `Basic realm="oauth"`)
+	case "server_error":
+		status = http.StatusInternalServerError
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	respondWithJSON(w, status, oauthErr)
+}
+
+// redirectWithParams redirects to uri with params added to its query
+func redirectWithParams(w http.ResponseWriter, r *http.Request, uri string, params url.Values) {
+	target, err := url.Parse(uri)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid redirect_uri")
+		return
+	}
+
+	query := target.Query()
+	for key, values := range params {
+		if len(values) > 0 && values[0] != "" {
+			query.Set(key, values[0])
+		}
+	}
+	target.RawQuery = query.Encode()
+
+	http.Redirect(w, r, target.String(), http.StatusFound)
+}
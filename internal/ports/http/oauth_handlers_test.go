@@ -0,0 +1,492 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yourusername/userapi/internal/adapters/hasher"
+	"github.com/yourusername/userapi/internal/adapters/repository/memory"
+	"github.com/yourusername/userapi/internal/application"
+	"github.com/yourusername/userapi/internal/domain"
+	"github.com/yourusername/userapi/internal/ports/repository"
+	"github.com/yourusername/userapi/pkg/auth"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	testTenant   = "acme"
+	testEmail    = "ada@example.com"
+	testPassword = "correct horse battery"
+	testLoginURL = "https://app.example/login"
+)
+
+// oauthTestServer runs the API with the OAuth authorization server enabled
+type oauthTestServer struct {
+	t       *testing.T
+	api     *httptest.Server
+	jwtAuth *auth.JWTAuth
+	// adminToken is a first-party access token of an administrator
+	adminToken string
+	// otherAdminToken is the token of an administrator of another tenant
+	otherAdminToken string
+}
+
+func newOAuthTestServer(t *testing.T) *oauthTestServer {
+	t.Helper()
+
+	passwordHasher, err := hasher.New(hasher.Config{Algorithm: hasher.AlgorithmBcrypt, BcryptCost: bcrypt.MinCost})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	users := newUserStore()
+	revocations := memory.NewTokenRevocationRepository()
+	jwtAuth := auth.NewJWTAuth("test-secret", time.Minute, auth.WithRevocationStore(revocations))
+	authService := application.NewAuthService(users, passwordHasher, newRefreshTokenStore(), revocations, jwtAuth, time.Hour)
+	oauthService := application.NewOAuthService(newOAuthClientStore(), newAuthorizationCodeStore(), newConsentStore(), users, authService, time.Minute)
+
+	tenants := application.NewTenantService(&tenantStore{})
+	tenant, err := tenants.CreateTenant(context.Background(), testTenant, "Acme")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	other, err := tenants.CreateTenant(context.Background(), "globex", "Globex")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Both tenants have an administrator with the same email address
+	for _, id := range []string{tenant.ID.Hex(), other.ID.Hex()} {
+		ctx := repository.WithTenant(context.Background(), id)
+		admin, err := authService.Register(ctx, "Ada Lovelace", testEmail, testPassword)
+		if err != nil {
+			t.Fatal(err)
+		}
+		admin.Role = domain.RoleAdmin
+		if err := users.Update(ctx, admin); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	handler := NewHandler(application.NewUserService(users, passwordHasher), authService, jwtAuth,
+		WithTenantService(tenants),
+		WithOAuthService(oauthService),
+		WithAuthorizeLoginURL(testLoginURL),
+		WithIntrospectionService(application.NewIntrospectionService(jwtAuth, oauthService, nil)),
+	)
+	api := httptest.NewServer(NewServer(handler, jwtAuth, "").router)
+	t.Cleanup(api.Close)
+
+	s := &oauthTestServer{t: t, api: api, jwtAuth: jwtAuth}
+
+	var login struct {
+		Data application.LoginResult `json:"data"`
+	}
+	s.do(http.MethodPost, "/login", "", strings.NewReader(`{"email":"`+testEmail+`","password":"`+testPassword+`"}`), http.StatusOK, &login)
+	s.adminToken = login.Data.AccessToken
+
+	result, err := authService.Login(repository.WithTenant(context.Background(), other.ID.Hex()), testEmail, testPassword, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.otherAdminToken = result.AccessToken
+
+	return s
+}
+
+// do sends a request to the API, checks the status and decodes the JSON response into out
+func (s *oauthTestServer) do(method, path, token string, body *strings.Reader, wantStatus int, out interface{}) *http.Response {
+	s.t.Helper()
+
+	if body == nil {
+		body = strings.NewReader("")
+	}
+	req, err := http.NewRequest(method, s.api.URL+path, body)
+	if err != nil {
+		s.t.Fatal(err)
+	}
+	req.Header.Set(TenantHeader, testTenant)
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	return s.send(req, wantStatus, out)
+}
+
+// send sends a request without following redirects
+func (s *oauthTestServer) send(req *http.Request, wantStatus int, out interface{}) *http.Response {
+	s.t.Helper()
+
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}}
+	resp, err := client.Do(req)
+	if err != nil {
+		s.t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != wantStatus {
+		var body map[string]interface{}
+		json.NewDecoder(resp.Body).Decode(&body)
+		s.t.Fatalf("%s %s: got status %d, want %d: %v", req.Method, req.URL.Path, resp.StatusCode, wantStatus, body)
+	}
+	if out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			s.t.Fatalf("%s %s: decoding response: %v", req.Method, req.URL.Path, err)
+		}
+	}
+
+	return resp
+}
+
+// session starts a browser session at /oauth/authorize for the holder of token
+func (s *oauthTestServer) session(token string) *http.Cookie {
+	s.t.Helper()
+
+	resp := s.do(http.MethodPost, "/oauth/session", token, nil, http.StatusNoContent, nil)
+	for _, cookie := range resp.Cookies() {
+		if cookie.Name == oauthSessionCookie {
+			return cookie
+		}
+	}
+	s.t.Fatal("no session cookie was set")
+	return nil
+}
+
+// authorize sends an authorization request from a browser with the session
+// cookie, which may be nil. A POST sends params as a form.
+func (s *oauthTestServer) authorize(method string, params url.Values, session *http.Cookie, wantStatus int, out interface{}) *http.Response {
+	s.t.Helper()
+
+	target, body := s.api.URL+"/oauth/authorize?"+params.Encode(), ""
+	if method == http.MethodPost {
+		target, body = s.api.URL+"/oauth/authorize", params.Encode()
+	}
+	req, err := http.NewRequest(method, target, strings.NewReader(body))
+	if err != nil {
+		s.t.Fatal(err)
+	}
+	if method == http.MethodPost {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+	if session != nil {
+		req.AddCookie(session)
+	}
+
+	return s.send(req, wantStatus, out)
+}
+
+// registerClient registers an OAuth client as the administrator
+func (s *oauthTestServer) registerClient(registration string) (clientID, secret string) {
+	s.t.Helper()
+
+	var created struct {
+		Data struct {
+			Client       domain.OAuthClient `json:"client"`
+			ClientSecret string             `json:"client_secret"`
+		} `json:"data"`
+	}
+	s.do(http.MethodPost, "/oauth/clients", s.adminToken, strings.NewReader(registration), http.StatusCreated, &created)
+
+	return created.Data.Client.ClientID, created.Data.ClientSecret
+}
+
+// token calls the token endpoint
+func (s *oauthTestServer) token(form url.Values, basicUser, basicPassword string, wantStatus int) map[string]interface{} {
+	s.t.Helper()
+
+	req, err := http.NewRequest(http.MethodPost, s.api.URL+"/oauth/token", strings.NewReader(form.Encode()))
+	if err != nil {
+		s.t.Fatal(err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if basicUser != "" {
+		req.SetBasicAuth(basicUser, basicPassword)
+	}
+
+	var body map[string]interface{}
+	s.send(req, wantStatus, &body)
+	return body
+}
+
+// fakeClient is a local OAuth client application. Its callback records the
+// authorization response the user's browser is redirected to.
+type fakeClient struct {
+	server   *httptest.Server
+	clientID string
+	verifier string
+	state    string
+
+	callback url.Values
+}
+
+func newFakeClient(t *testing.T) *fakeClient {
+	c := &fakeClient{
+		verifier: strings.Repeat("fake-client-verifier-", 3),
+		state:    "state-1234",
+	}
+	c.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c.callback = r.URL.Query()
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(c.server.Close)
+	return c
+}
+
+func (c *fakeClient) redirectURI() string {
+	return c.server.URL + "/callback"
+}
+
+// params returns the client's authorization request
+func (c *fakeClient) params() url.Values {
+	return url.Values{
+		"response_type":         {"code"},
+		"client_id":             {c.clientID},
+		"redirect_uri":          {c.redirectURI()},
+		"scope":                 {application.ScopeProfile},
+		"state":                 {c.state},
+		"code_challenge":        {auth.PKCEChallenge(c.verifier)},
+		"code_challenge_method": {"S256"},
+	}
+}
+
+// authorize signs the administrator in to the client: it approves the
+// consent screen and follows the redirect back to the client's callback
+func (c *fakeClient) authorize(s *oauthTestServer) string {
+	s.t.Helper()
+
+	session := s.session(s.adminToken)
+	params := c.params()
+
+	var consent struct {
+		Data application.AuthorizeResult `json:"data"`
+	}
+	s.authorize(http.MethodGet, params, session, http.StatusOK, &consent)
+	if !consent.Data.ConsentRequired {
+		s.t.Fatal("first authorization did not ask for consent")
+	}
+
+	params.Set("decision", "approve")
+	resp := s.authorize(http.MethodPost, params, session, http.StatusFound, nil)
+
+	location := resp.Header.Get("Location")
+	if !strings.HasPrefix(location, c.redirectURI()+"?") {
+		s.t.Fatalf("redirected to %q, want the client's callback", location)
+	}
+	callback, err := http.Get(location)
+	if err != nil {
+		s.t.Fatal(err)
+	}
+	callback.Body.Close()
+
+	if got := c.callback.Get("state"); got != c.state {
+		s.t.Fatalf("callback state = %q, want %q", got, c.state)
+	}
+	code := c.callback.Get("code")
+	if code == "" {
+		s.t.Fatalf("callback has no code: %v", c.callback)
+	}
+	return code
+}
+
+// exchange redeems an authorization code with the given verifier
+func (c *fakeClient) exchange(s *oauthTestServer, code, verifier string, wantStatus int) map[string]interface{} {
+	s.t.Helper()
+
+	return s.token(url.Values{
+		"grant_type":    {domain.GrantAuthorizationCode},
+		"client_id":     {c.clientID},
+		"code":          {code},
+		"redirect_uri":  {c.redirectURI()},
+		"code_verifier": {verifier},
+	}, "", "", wantStatus)
+}
+
+func TestOAuthAuthorizationCodeFlow(t *testing.T) {
+	s := newOAuthTestServer(t)
+	client := newFakeClient(t)
+	client.clientID, _ = s.registerClient(`{"name":"Fake app","public":true,"redirect_uris":["` + client.redirectURI() + `"],"scopes":["profile"]}`)
+
+	code := client.authorize(s)
+
+	t.Run("wrong verifier is rejected", func(t *testing.T) {
+		body := client.exchange(s, code, client.verifier+"-wrong", http.StatusBadRequest)
+		if body["error"] != application.OAuthErrInvalidGrant {
+			t.Fatalf("error = %v, want %s", body["error"], application.OAuthErrInvalidGrant)
+		}
+	})
+
+	body := client.exchange(s, code, client.verifier, http.StatusOK)
+	accessToken, _ := body["access_token"].(string)
+	refreshToken, _ := body["refresh_token"].(string)
+	if accessToken == "" || refreshToken == "" {
+		t.Fatalf("token response is missing tokens: %v", body)
+	}
+
+	claims, err := s.jwtAuth.ValidateToken(context.Background(), accessToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if claims.ClientID != client.clientID || claims.Scope != application.ScopeProfile {
+		t.Fatalf("access token has client %q and scope %q", claims.ClientID, claims.Scope)
+	}
+
+	// The token only carries the consented scope
+	s.do(http.MethodGet, "/me", accessToken, nil, http.StatusOK, nil)
+	s.do(http.MethodGet, "/users", accessToken, nil, http.StatusForbidden, nil)
+
+	t.Run("code cannot be reused", func(t *testing.T) {
+		body := client.exchange(s, code, client.verifier, http.StatusBadRequest)
+		if body["error"] != application.OAuthErrInvalidGrant {
+			t.Fatalf("error = %v, want %s", body["error"], application.OAuthErrInvalidGrant)
+		}
+	})
+
+	t.Run("refresh token grant", func(t *testing.T) {
+		refreshed := s.token(url.Values{
+			"grant_type":    {domain.GrantRefreshToken},
+			"client_id":     {client.clientID},
+			"refresh_token": {refreshToken},
+		}, "", "", http.StatusOK)
+
+		rotated, _ := refreshed["refresh_token"].(string)
+		if rotated == "" || rotated == refreshToken {
+			t.Fatalf("refresh token was not rotated: %v", refreshed)
+		}
+		accessToken, _ := refreshed["access_token"].(string)
+		claims, err := s.jwtAuth.ValidateToken(context.Background(), accessToken)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if claims.ClientID != client.clientID || claims.Scope != application.ScopeProfile {
+			t.Fatalf("refreshed access token has client %q and scope %q", claims.ClientID, claims.Scope)
+		}
+
+		// The first-party refresh endpoint does not accept a client's token
+		s.do(http.MethodPost, "/token/refresh", "", strings.NewReader(`{"refresh_token":"`+rotated+`"}`), http.StatusUnauthorized, nil)
+
+		// Replaying the rotated token is rejected
+		replayed := s.token(url.Values{
+			"grant_type":    {domain.GrantRefreshToken},
+			"client_id":     {client.clientID},
+			"refresh_token": {refreshToken},
+		}, "", "", http.StatusBadRequest)
+		if replayed["error"] != application.OAuthErrInvalidGrant {
+			t.Fatalf("error = %v, want %s", replayed["error"], application.OAuthErrInvalidGrant)
+		}
+	})
+}
+
+func TestOAuthClientCredentialsGrant(t *testing.T) {
+	s := newOAuthTestServer(t)
+	clientID, secret := s.registerClient(`{"name":"CI","grant_types":["client_credentials"],"scopes":["users:read"]}`)
+	if secret == "" {
+		t.Fatal("confidential client was registered without a secret")
+	}
+
+	form := url.Values{"grant_type": {domain.GrantClientCredentials}}
+
+	body := s.token(form, clientID, "wrong-secret", http.StatusUnauthorized)
+	if body["error"] != application.OAuthErrInvalidClient {
+		t.Fatalf("error = %v, want %s", body["error"], application.OAuthErrInvalidClient)
+	}
+
+	body = s.token(form, clientID, secret, http.StatusOK)
+	if _, ok := body["refresh_token"]; ok {
+		t.Fatalf("client credentials grant issued a refresh token: %v", body)
+	}
+
+	accessToken, _ := body["access_token"].(string)
+	claims, err := s.jwtAuth.ValidateToken(context.Background(), accessToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if claims.Subject != clientID || claims.UserID != "" || claims.Scope != application.ScopeUsersRead {
+		t.Fatalf("access token has subject %q, user %q and scope %q", claims.Subject, claims.UserID, claims.Scope)
+	}
+
+	// The authorization code grant was not registered for the client
+	body = s.token(url.Values{"grant_type": {domain.GrantAuthorizationCode}, "code": {"anything"}}, clientID, secret, http.StatusBadRequest)
+	if body["error"] != application.OAuthErrUnauthorizedClient {
+		t.Fatalf("error = %v, want %s", body["error"], application.OAuthErrUnauthorizedClient)
+	}
+}
+
+func TestOAuthClientRegistrationRequiresUsersWriteScope(t *testing.T) {
+	s := newOAuthTestServer(t)
+	client := newFakeClient(t)
+	client.clientID, _ = s.registerClient(`{"name":"Fake app","public":true,"redirect_uris":["` + client.redirectURI() + `"],"scopes":["profile"]}`)
+
+	body := client.exchange(s, client.authorize(s), client.verifier, http.StatusOK)
+	accessToken, _ := body["access_token"].(string)
+
+	// The administrator's token for the client only carries profile
+	s.do(http.MethodPost, "/oauth/clients", accessToken, strings.NewReader(`{"name":"Rogue","grant_types":["client_credentials"],"scopes":["users:write"]}`), http.StatusForbidden, nil)
+	s.do(http.MethodGet, "/oauth/clients", accessToken, nil, http.StatusForbidden, nil)
+
+	// Nor can it start a session to authorize other clients
+	s.do(http.MethodPost, "/oauth/session", accessToken, nil, http.StatusForbidden, nil)
+}
+
+func TestOAuthAuthorizeNeedsABrowserSession(t *testing.T) {
+	s := newOAuthTestServer(t)
+	client := newFakeClient(t)
+	client.clientID, _ = s.registerClient(`{"name":"Fake app","public":true,"redirect_uris":["` + client.redirectURI() + `"],"scopes":["profile"]}`)
+
+	// A browser without a session is sent to the login page and back
+	resp := s.authorize(http.MethodGet, client.params(), nil, http.StatusFound, nil)
+	location, err := url.Parse(resp.Header.Get("Location"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if location.Scheme+"://"+location.Host+location.Path != testLoginURL || location.Query().Get("return_to") != "/oauth/authorize?"+client.params().Encode() {
+		t.Errorf("redirected to %s, want the login page returning to the request", location)
+	}
+
+	params := client.params()
+	params.Set("decision", "approve")
+	s.authorize(http.MethodPost, params, nil, http.StatusUnauthorized, nil)
+
+	// The Authorization header is not a session
+	req, err := http.NewRequest(http.MethodPost, s.api.URL+"/oauth/authorize", strings.NewReader(params.Encode()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Authorization", "Bearer "+s.adminToken)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	s.send(req, http.StatusUnauthorized, nil)
+
+	// API keys cannot start a session
+	req, err = http.NewRequest(http.MethodPost, s.api.URL+"/oauth/session", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Authorization", "ApiKey "+auth.APIKeyPrefix+"anything")
+	s.send(req, http.StatusUnauthorized, nil)
+}
+
+func TestOAuthClientsBelongToTheirTenant(t *testing.T) {
+	s := newOAuthTestServer(t)
+	client := newFakeClient(t)
+	client.clientID, _ = s.registerClient(`{"name":"Fake app","public":true,"redirect_uris":["` + client.redirectURI() + `"],"scopes":["profile"]}`)
+
+	var listed struct {
+		Data []domain.OAuthClient `json:"data"`
+	}
+	s.do(http.MethodGet, "/oauth/clients", s.otherAdminToken, nil, http.StatusOK, &listed)
+	if len(listed.Data) != 0 {
+		t.Errorf("another tenant lists %d clients, want none", len(listed.Data))
+	}
+
+	// domain.ErrOAuthClientNotFound, reported without redirecting
+	s.authorize(http.MethodGet, client.params(), s.session(s.otherAdminToken), http.StatusBadRequest, nil)
+}
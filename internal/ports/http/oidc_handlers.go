@@ -0,0 +1,35 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/yourusername/userapi/internal/domain"
+	"github.com/yourusername/userapi/pkg/auth"
+)
+
+// OpenIDConfigurationHandler serves the OpenID Connect discovery document.
+// Like the JWKS it is not wrapped in a Response, so OIDC client libraries
+// can read it directly.
+func (h *Handler) OpenIDConfigurationHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Cache-Control", "public, max-age=3600")
+	respondWithJSON(w, http.StatusOK, h.oidcService.Discovery())
+}
+
+// UserInfoHandler returns the standard claims about the token's user
+func (h *Handler) UserInfoHandler(w http.ResponseWriter, r *http.Request) {
+	claims, _ := r.Context().Value("claims").(*auth.Claims)
+
+	info, err := h.oidcService.UserInfo(r.Context(), claims.UserID, claims.Scopes())
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err == domain.ErrUserNotFound {
+			status = http.StatusNotFound
+		} else if err == domain.ErrForbidden {
+			status = http.StatusForbidden
+		}
+		respondWithError(w, status, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, info)
+}
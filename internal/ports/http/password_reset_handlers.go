@@ -0,0 +1,77 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/yourusername/userapi/internal/domain"
+	"github.com/yourusername/userapi/pkg/validation"
+)
+
+// ForgotPasswordHandler starts a password reset. The response is the same
+// whether or not the email belongs to an account.
+func (h *Handler) ForgotPasswordHandler(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		Email string `json:"email" validate:"required,email"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	// Validate input
+	if err := validation.Validate(input); err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.passwordResetService.RequestReset(r.Context(), input.Email); err != nil {
+		respondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusAccepted, Response{
+		Success: true,
+		Data: map[string]string{
+			"message": "If an account exists for this email, a reset link has been sent",
+		},
+	})
+}
+
+// ResetPasswordHandler sets a new password using a reset token
+func (h *Handler) ResetPasswordHandler(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		Token       string `json:"token" validate:"required"`
+		NewPassword string `json:"new_password" validate:"required"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	// Validate input
+	if err := validation.Validate(input); err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	err := h.passwordResetService.ResetPassword(r.Context(), input.Token, input.NewPassword)
+	if err != nil {
+		if respondWithPasswordPolicyError(w, err) {
+			return
+		}
+
+		status := http.StatusInternalServerError
+		if err == domain.ErrInvalidToken {
+			status = http.StatusBadRequest
+		} else if err == domain.ErrPasswordReused {
+			status = http.StatusUnprocessableEntity
+		}
+		respondWithError(w, status, err.Error())
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, Response{Success: true})
+}
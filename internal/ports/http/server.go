@@ -11,6 +11,7 @@ import (
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
+	"github.com/yourusername/userapi/internal/application"
 	"github.com/yourusername/userapi/pkg/auth"
 )
 
@@ -48,19 +49,138 @@ func (s *Server) setupRoutes() {
 	s.router.Use(LoggingMiddleware)
 	s.router.Use(middleware.Recoverer)
 
+	if s.handler.tenantService != nil {
+		s.router.Use(TenantMiddleware(s.handler.tenantService))
+	}
+
 	// Public routes
-	s.router.Post("/register", s.handler.RegisterHandler)
-	s.router.Post("/login", s.handler.LoginHandler)
+	s.router.With(RequireTenant).Post("/register", s.handler.RegisterHandler)
+	s.router.With(RequireTenant).Post("/login", s.handler.LoginHandler)
+	s.router.Post("/token/refresh", s.handler.RefreshTokenHandler)
+	s.router.Get("/.well-known/jwks.json", s.handler.JWKSHandler)
+
+	if s.handler.passwordResetService != nil {
+		s.router.With(RequireTenant).Post("/password/forgot", s.handler.ForgotPasswordHandler)
+		s.router.Post("/password/reset", s.handler.ResetPasswordHandler)
+	}
+
+	if s.handler.verificationService != nil {
+		s.router.Get("/verify-email", s.handler.VerifyEmailHandler)
+	}
+
+	if s.handler.mfaService != nil {
+		s.router.Post("/login/mfa", s.handler.CompleteMFALoginHandler)
+	}
+
+	if s.handler.magicLinkService != nil {
+		s.router.With(RequireTenant).Post("/login/magic", s.handler.RequestMagicLinkHandler)
+		s.router.Post("/login/magic/verify", s.handler.RedeemMagicLinkHandler)
+	}
+
+	if s.handler.invitationService != nil {
+		s.router.Post("/invitations/accept", s.handler.AcceptInvitationHandler)
+	}
+
+	if s.handler.oauthService != nil {
+		s.router.Post("/oauth/token", s.handler.TokenHandler)
+
+		// Browsers are redirected to the authorization endpoint, so it is
+		// authenticated with the session cookie rather than a header
+		session := SessionCookieMiddleware(s.jwtAuth, s.handler.authorizeLoginURL)
+		s.router.With(session, RequireScope(application.ScopeProfile)).Get("/oauth/authorize", s.handler.AuthorizeHandler)
+		s.router.With(session, RequireScope(application.ScopeProfile)).Post("/oauth/authorize", s.handler.AuthorizeHandler)
+
+		// API keys cannot start a session
+		s.router.With(AuthMiddleware(s.jwtAuth, nil), RequireScope(application.ScopeProfile)).Post("/oauth/session", s.handler.CreateOAuthSessionHandler)
+	}
+
+	if s.handler.introspectionService != nil {
+		s.router.Post("/introspect", s.handler.IntrospectionHandler)
+	}
+
+	if s.handler.oidcService != nil {
+		s.router.Get("/.well-known/openid-configuration", s.handler.OpenIDConfigurationHandler)
+	}
+
+	if s.handler.federationService != nil {
+		s.router.Get("/sso/providers", s.handler.ListIdentityProvidersHandler)
+		s.router.Get("/sso/{provider}/login", s.handler.BeginFederatedLoginHandler)
+		s.router.Get("/sso/{provider}/callback", s.handler.FederatedCallbackHandler)
+	}
 
 	// Protected routes
 	s.router.Group(func(r chi.Router) {
-		r.Use(AuthMiddleware(s.jwtAuth))
+		r.Use(AuthMiddleware(s.jwtAuth, s.handler.apiKeyService))
+
+		r.Post("/logout", s.handler.LogoutHandler)
+
+		r.With(RequireScope(application.ScopeProfile)).Get("/me", s.handler.GetMeHandler)
+		r.With(RequireScope(application.ScopeProfile)).Patch("/me", s.handler.UpdateMeHandler)
+		r.With(RequireScope(application.ScopeProfile)).Post("/me/password", s.handler.ChangeMyPasswordHandler)
+		r.With(RequireScope(application.ScopeProfile)).Delete("/me", s.handler.DeleteMeHandler)
+
+		if s.handler.verificationService != nil {
+			r.With(RequireScope(application.ScopeProfile)).Post("/verify-email/resend", s.handler.ResendVerificationHandler)
+		}
+
+		if s.handler.mfaService != nil {
+			r.With(RequireScope(application.ScopeProfile)).Post("/me/mfa/enroll", s.handler.BeginMFAEnrollmentHandler)
+			r.With(RequireScope(application.ScopeProfile)).Post("/me/mfa/confirm", s.handler.ConfirmMFAEnrollmentHandler)
+			r.With(RequireScope(application.ScopeProfile)).Post("/me/mfa/disable", s.handler.DisableMFAHandler)
+			r.With(RequireScope(application.ScopeProfile)).Post("/me/mfa/recovery-codes", s.handler.RegenerateRecoveryCodesHandler)
+		}
+
+		if s.handler.apiKeyService != nil {
+			r.With(RequireScope(application.ScopeProfile)).Post("/me/api-keys", s.handler.CreateAPIKeyHandler)
+			r.With(RequireScope(application.ScopeProfile)).Get("/me/api-keys", s.handler.ListAPIKeysHandler)
+			r.With(RequireScope(application.ScopeProfile)).Delete("/me/api-keys/{id}", s.handler.RevokeAPIKeyHandler)
+		}
 
-		r.Get("/users", s.handler.GetAllUsersHandler)
-		r.Post("/users", s.handler.RegisterHandler) // Create user is same as register
-		r.Get("/users/{id}", s.handler.GetUserHandler)
-		r.Put("/users/{id}", s.handler.UpdateUserHandler)
-		r.Delete("/users/{id}", s.handler.DeleteUserHandler)
+		if s.handler.oauthService != nil {
+			r.With(RequireScope(application.ScopeUsersWrite)).Post("/oauth/clients", s.handler.RegisterOAuthClientHandler)
+			r.With(RequireScope(application.ScopeUsersWrite)).Get("/oauth/clients", s.handler.ListOAuthClientsHandler)
+		}
+
+		if s.handler.oidcService != nil {
+			r.With(RequireScope(application.ScopeOpenID)).Get("/userinfo", s.handler.UserInfoHandler)
+			r.With(RequireScope(application.ScopeOpenID)).Post("/userinfo", s.handler.UserInfoHandler)
+		}
+
+		r.With(RequireScope(application.ScopeUsersRead)).Get("/users", s.handler.GetAllUsersHandler)
+		r.With(RequireScope(application.ScopeUsersWrite)).Post("/users", s.handler.CreateUserHandler)
+		r.With(RequireScope(application.ScopeUsersRead)).Get("/users/{id}", s.handler.GetUserHandler)
+		r.With(RequireScope(application.ScopeUsersWrite)).Put("/users/{id}", s.handler.UpdateUserHandler)
+		r.With(RequireScope(application.ScopeUsersWrite)).Delete("/users/{id}", s.handler.DeleteUserHandler)
+		r.With(RequireScope(application.ScopeUsersRead)).Get("/users/deleted", s.handler.ListDeletedUsersHandler)
+		r.With(RequireScope(application.ScopeUsersWrite)).Post("/users/{id}/restore", s.handler.RestoreUserHandler)
+		r.With(RequireScope(application.ScopeUsersWrite)).Put("/users/{id}/role", s.handler.ChangeRoleHandler)
+		r.With(RequireScopeOrSelf(application.ScopeProfile, application.ScopeUsersWrite)).Post("/users/{id}/sessions/revoke-all", s.handler.RevokeAllSessionsHandler)
+
+		if s.handler.statusService != nil {
+			r.With(RequireScope(application.ScopeUsersWrite)).Post("/users/{id}/suspend", s.handler.SuspendUserHandler)
+			r.With(RequireScope(application.ScopeUsersWrite)).Post("/users/{id}/lock", s.handler.LockUserHandler)
+			r.With(RequireScope(application.ScopeUsersWrite)).Post("/users/{id}/deactivate", s.handler.DeactivateUserHandler)
+			r.With(RequireScope(application.ScopeUsersWrite)).Post("/users/{id}/reactivate", s.handler.ReactivateUserHandler)
+			r.With(RequireScope(application.ScopeUsersRead)).Get("/users/{id}/status-changes", s.handler.ListStatusChangesHandler)
+		}
+
+		if s.handler.groupService != nil {
+			r.With(RequireScope(application.ScopeUsersRead)).Get("/groups", s.handler.ListGroupsHandler)
+			r.With(RequireScope(application.ScopeUsersWrite)).Post("/groups", s.handler.CreateGroupHandler)
+			r.With(RequireScope(application.ScopeUsersRead)).Get("/groups/{id}", s.handler.GetGroupHandler)
+			r.With(RequireScope(application.ScopeUsersWrite)).Put("/groups/{id}", s.handler.UpdateGroupHandler)
+			r.With(RequireScope(application.ScopeUsersWrite)).Delete("/groups/{id}", s.handler.DeleteGroupHandler)
+			r.With(RequireScope(application.ScopeUsersWrite)).Post("/groups/{id}/members", s.handler.AddGroupMemberHandler)
+			r.With(RequireScope(application.ScopeUsersWrite)).Delete("/groups/{id}/members/{userID}", s.handler.RemoveGroupMemberHandler)
+			r.With(RequireScope(application.ScopeUsersRead)).Get("/users/{id}/groups", s.handler.ListUserGroupsHandler)
+		}
+
+		if s.handler.invitationService != nil {
+			r.With(RequireScope(application.ScopeUsersRead)).Get("/invitations", s.handler.ListInvitationsHandler)
+			r.With(RequireScope(application.ScopeUsersWrite)).Post("/invitations", s.handler.InviteHandler)
+			r.With(RequireScope(application.ScopeUsersWrite)).Post("/invitations/{id}/resend", s.handler.ResendInvitationHandler)
+			r.With(RequireScope(application.ScopeUsersWrite)).Delete("/invitations/{id}", s.handler.RevokeInvitationHandler)
+		}
 	})
 }
 
@@ -0,0 +1,522 @@
+package http
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/yourusername/userapi/internal/domain"
+	"github.com/yourusername/userapi/internal/ports/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// In-memory repositories for the handler tests. They keep copies so a test
+// only sees changes that went through the repository.
+
+// userStore is an in-memory UserRepository scoped by the context's tenant
+type userStore struct {
+	mu    sync.Mutex
+	users map[string]*domain.User
+}
+
+func newUserStore() *userStore {
+	return &userStore{users: make(map[string]*domain.User)}
+}
+
+func (s *userStore) visible(ctx context.Context, user *domain.User) bool {
+	if user.IsDeleted() && !repository.IncludesDeleted(ctx) {
+		return false
+	}
+	return s.inTenant(ctx, user)
+}
+
+func (s *userStore) inTenant(ctx context.Context, user *domain.User) bool {
+	if tenantID, ok := repository.TenantFromContext(ctx); ok {
+		return user.TenantID.Hex() == tenantID
+	}
+	return repository.IsAcrossTenants(ctx)
+}
+
+func (s *userStore) Create(ctx context.Context, user *domain.User) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tenantID, ok := repository.TenantFromContext(ctx)
+	if !ok {
+		return domain.ErrTenantRequired
+	}
+	if user.ID.IsZero() {
+		user.ID = primitive.NewObjectID()
+	}
+	user.TenantID, _ = primitive.ObjectIDFromHex(tenantID)
+
+	stored := *user
+	s.users[user.ID.Hex()] = &stored
+	return nil
+}
+
+func (s *userStore) FindByID(ctx context.Context, id string) (*domain.User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	user, ok := s.users[id]
+	if !ok || !s.visible(ctx, user) {
+		return nil, domain.ErrUserNotFound
+	}
+	found := *user
+	return &found, nil
+}
+
+func (s *userStore) FindByEmail(ctx context.Context, email string) (*domain.User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, user := range s.users {
+		if user.Email == email && s.visible(ctx, user) {
+			found := *user
+			return &found, nil
+		}
+	}
+	return nil, domain.ErrUserNotFound
+}
+
+func (s *userStore) FindAll(ctx context.Context) ([]*domain.User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var users []*domain.User
+	for _, user := range s.users {
+		if s.visible(ctx, user) {
+			found := *user
+			users = append(users, &found)
+		}
+	}
+	return users, nil
+}
+
+func (s *userStore) Update(ctx context.Context, user *domain.User) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if stored, ok := s.users[user.ID.Hex()]; !ok || !s.inTenant(ctx, stored) {
+		return domain.ErrUserNotFound
+	}
+	stored := *user
+	s.users[user.ID.Hex()] = &stored
+	return nil
+}
+
+func (s *userStore) Delete(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	user, ok := s.users[id]
+	if !ok || user.IsDeleted() || !s.inTenant(ctx, user) {
+		return domain.ErrUserNotFound
+	}
+	now := time.Now()
+	user.DeletedAt = &now
+	return nil
+}
+
+func (s *userStore) Restore(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	user, ok := s.users[id]
+	if !ok || !user.IsDeleted() || !s.inTenant(ctx, user) {
+		return domain.ErrUserNotFound
+	}
+	user.DeletedAt = nil
+	return nil
+}
+
+func (s *userStore) FindDeletedBefore(ctx context.Context, before time.Time) ([]*domain.User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var users []*domain.User
+	for _, user := range s.users {
+		if user.IsDeleted() && user.DeletedAt.Before(before) {
+			found := *user
+			users = append(users, &found)
+		}
+	}
+	return users, nil
+}
+
+func (s *userStore) Purge(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.users, id)
+	return nil
+}
+
+func (s *userStore) Count(ctx context.Context) (int64, error) {
+	users, _ := s.FindAll(ctx)
+	return int64(len(users)), nil
+}
+
+// tenantStore is an in-memory TenantRepository
+type tenantStore struct {
+	mu      sync.Mutex
+	tenants []*domain.Tenant
+}
+
+func (s *tenantStore) Create(ctx context.Context, tenant *domain.Tenant) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if tenant.ID.IsZero() {
+		tenant.ID = primitive.NewObjectID()
+	}
+	s.tenants = append(s.tenants, tenant)
+	return nil
+}
+
+func (s *tenantStore) FindByID(ctx context.Context, id string) (*domain.Tenant, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, tenant := range s.tenants {
+		if tenant.ID.Hex() == id {
+			return tenant, nil
+		}
+	}
+	return nil, domain.ErrTenantNotFound
+}
+
+func (s *tenantStore) FindBySlug(ctx context.Context, slug string) (*domain.Tenant, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, tenant := range s.tenants {
+		if tenant.Slug == slug {
+			return tenant, nil
+		}
+	}
+	return nil, domain.ErrTenantNotFound
+}
+
+func (s *tenantStore) FindAll(ctx context.Context) ([]*domain.Tenant, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]*domain.Tenant(nil), s.tenants...), nil
+}
+
+// refreshTokenStore is an in-memory RefreshTokenRepository
+type refreshTokenStore struct {
+	mu     sync.Mutex
+	tokens map[string]*domain.RefreshToken
+}
+
+func newRefreshTokenStore() *refreshTokenStore {
+	return &refreshTokenStore{tokens: make(map[string]*domain.RefreshToken)}
+}
+
+func (s *refreshTokenStore) Create(ctx context.Context, token *domain.RefreshToken) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	token.ID = primitive.NewObjectID()
+	stored := *token
+	s.tokens[token.TokenHash] = &stored
+	return nil
+}
+
+func (s *refreshTokenStore) FindByHash(ctx context.Context, tokenHash string) (*domain.RefreshToken, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	token, ok := s.tokens[tokenHash]
+	if !ok {
+		return nil, domain.ErrRefreshTokenNotFound
+	}
+	found := *token
+	return &found, nil
+}
+
+func (s *refreshTokenStore) MarkRotated(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, token := range s.tokens {
+		if token.ID.Hex() == id {
+			if token.RotatedAt != nil || token.RevokedAt != nil {
+				return domain.ErrRefreshTokenReused
+			}
+			now := time.Now()
+			token.RotatedAt = &now
+			return nil
+		}
+	}
+	return domain.ErrRefreshTokenNotFound
+}
+
+func (s *refreshTokenStore) RevokeFamily(ctx context.Context, familyID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	for _, token := range s.tokens {
+		if token.FamilyID == familyID && token.RevokedAt == nil {
+			token.RevokedAt = &now
+		}
+	}
+	return nil
+}
+
+func (s *refreshTokenStore) RevokeAllForUser(ctx context.Context, userID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	for _, token := range s.tokens {
+		if token.UserID.Hex() == userID && token.RevokedAt == nil {
+			token.RevokedAt = &now
+		}
+	}
+	return nil
+}
+
+// oauthClientStore is an in-memory OAuthClientRepository
+type oauthClientStore struct {
+	mu      sync.Mutex
+	clients map[string]*domain.OAuthClient
+}
+
+func newOAuthClientStore() *oauthClientStore {
+	return &oauthClientStore{clients: make(map[string]*domain.OAuthClient)}
+}
+
+func (s *oauthClientStore) Create(ctx context.Context, client *domain.OAuthClient) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tenantID, ok := repository.TenantFromContext(ctx)
+	if !ok {
+		return domain.ErrTenantRequired
+	}
+	client.TenantID, _ = primitive.ObjectIDFromHex(tenantID)
+
+	stored := *client
+	s.clients[client.ClientID] = &stored
+	return nil
+}
+
+func (s *oauthClientStore) FindByClientID(ctx context.Context, clientID string) (*domain.OAuthClient, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	client, ok := s.clients[clientID]
+	if !ok {
+		return nil, domain.ErrOAuthClientNotFound
+	}
+	found := *client
+	return &found, nil
+}
+
+func (s *oauthClientStore) FindAll(ctx context.Context) ([]*domain.OAuthClient, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tenantID, _ := repository.TenantFromContext(ctx)
+
+	var clients []*domain.OAuthClient
+	for _, client := range s.clients {
+		if client.TenantID.Hex() != tenantID {
+			continue
+		}
+		found := *client
+		clients = append(clients, &found)
+	}
+	return clients, nil
+}
+
+// authorizationCodeStore is an in-memory AuthorizationCodeRepository
+type authorizationCodeStore struct {
+	mu    sync.Mutex
+	codes map[string]*domain.AuthorizationCode
+}
+
+func newAuthorizationCodeStore() *authorizationCodeStore {
+	return &authorizationCodeStore{codes: make(map[string]*domain.AuthorizationCode)}
+}
+
+func (s *authorizationCodeStore) Create(ctx context.Context, code *domain.AuthorizationCode) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	code.ID = primitive.NewObjectID()
+	stored := *code
+	s.codes[code.CodeHash] = &stored
+	return nil
+}
+
+func (s *authorizationCodeStore) FindByHash(ctx context.Context, codeHash string) (*domain.AuthorizationCode, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	code, ok := s.codes[codeHash]
+	if !ok {
+		return nil, domain.ErrInvalidToken
+	}
+	found := *code
+	return &found, nil
+}
+
+func (s *authorizationCodeStore) MarkUsed(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, code := range s.codes {
+		if code.ID.Hex() == id {
+			if code.UsedAt != nil {
+				return domain.ErrInvalidToken
+			}
+			now := time.Now()
+			code.UsedAt = &now
+			return nil
+		}
+	}
+	return domain.ErrInvalidToken
+}
+
+// consentStore is an in-memory OAuthConsentRepository
+type consentStore struct {
+	mu       sync.Mutex
+	consents map[string]*domain.OAuthConsent
+}
+
+func newConsentStore() *consentStore {
+	return &consentStore{consents: make(map[string]*domain.OAuthConsent)}
+}
+
+func (s *consentStore) Find(ctx context.Context, userID, clientID string) (*domain.OAuthConsent, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	consent, ok := s.consents[userID+"/"+clientID]
+	if !ok {
+		return nil, domain.ErrConsentNotFound
+	}
+	found := *consent
+	return &found, nil
+}
+
+func (s *consentStore) Save(ctx context.Context, consent *domain.OAuthConsent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored := *consent
+	s.consents[consent.UserID.Hex()+"/"+consent.ClientID] = &stored
+	return nil
+}
+
+// identityStore is an in-memory ExternalIdentityRepository
+type identityStore struct {
+	mu         sync.Mutex
+	identities []*domain.ExternalIdentity
+}
+
+func (s *identityStore) Create(ctx context.Context, identity *domain.ExternalIdentity) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, existing := range s.identities {
+		if existing.Provider == identity.Provider && existing.Subject == identity.Subject {
+			return domain.ErrIdentityConflict
+		}
+	}
+	identity.ID = primitive.NewObjectID()
+	stored := *identity
+	s.identities = append(s.identities, &stored)
+	return nil
+}
+
+func (s *identityStore) FindBySubject(ctx context.Context, provider, subject string) (*domain.ExternalIdentity, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, identity := range s.identities {
+		if identity.Provider == provider && identity.Subject == subject {
+			found := *identity
+			return &found, nil
+		}
+	}
+	return nil, domain.ErrIdentityNotFound
+}
+
+func (s *identityStore) FindByUserID(ctx context.Context, userID string) ([]*domain.ExternalIdentity, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var identities []*domain.ExternalIdentity
+	for _, identity := range s.identities {
+		if identity.UserID.Hex() == userID {
+			found := *identity
+			identities = append(identities, &found)
+		}
+	}
+	return identities, nil
+}
+
+func (s *identityStore) TouchLastLogin(ctx context.Context, id string, at time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, identity := range s.identities {
+		if identity.ID.Hex() == id {
+			identity.LastLoginAt = at
+			return nil
+		}
+	}
+	return domain.ErrIdentityNotFound
+}
+
+func (s *identityStore) DeleteByUserID(ctx context.Context, userID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	kept := s.identities[:0]
+	for _, identity := range s.identities {
+		if identity.UserID.Hex() != userID {
+			kept = append(kept, identity)
+		}
+	}
+	s.identities = kept
+	return nil
+}
+
+// loginStateStore is an in-memory FederatedLoginStateRepository
+type loginStateStore struct {
+	mu     sync.Mutex
+	states map[string]*domain.FederatedLoginState
+}
+
+func newLoginStateStore() *loginStateStore {
+	return &loginStateStore{states: make(map[string]*domain.FederatedLoginState)}
+}
+
+func (s *loginStateStore) Create(ctx context.Context, state *domain.FederatedLoginState) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored := *state
+	s.states[state.StateHash] = &stored
+	return nil
+}
+
+func (s *loginStateStore) Consume(ctx context.Context, stateHash string) (*domain.FederatedLoginState, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state, ok := s.states[stateHash]
+	if !ok {
+		return nil, domain.ErrInvalidToken
+	}
+	delete(s.states, stateHash)
+	return state, nil
+}
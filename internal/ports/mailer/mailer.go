@@ -0,0 +1,15 @@
+package mailer
+
+import "context"
+
+// Message is an outbound plain-text email
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer defines the interface for sending email
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
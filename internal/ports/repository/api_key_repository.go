@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/yourusername/userapi/internal/domain"
+)
+
+// APIKeyRepository defines the interface for API key storage
+type APIKeyRepository interface {
+	Create(ctx context.Context, key *domain.APIKey) error
+	FindByHash(ctx context.Context, keyHash string) (*domain.APIKey, error)
+	// FindByUserID returns every key of a user, newest first
+	FindByUserID(ctx context.Context, userID string) ([]*domain.APIKey, error)
+	// Revoke revokes a key owned by userID. It returns domain.ErrAPIKeyNotFound
+	// if the user has no such key.
+	Revoke(ctx context.Context, userID, id string) error
+	TouchLastUsed(ctx context.Context, id string, at time.Time) error
+}
@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/yourusername/userapi/internal/domain"
+)
+
+// AuthorizationCodeRepository defines the interface for OAuth authorization codes
+type AuthorizationCodeRepository interface {
+	Create(ctx context.Context, code *domain.AuthorizationCode) error
+	FindByHash(ctx context.Context, codeHash string) (*domain.AuthorizationCode, error)
+	// MarkUsed atomically redeems an unused code. It returns
+	// domain.ErrInvalidToken if the code was already used.
+	MarkUsed(ctx context.Context, id string) error
+}
@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/yourusername/userapi/internal/domain"
+)
+
+// EmailVerificationRepository defines the interface for email verification token storage
+type EmailVerificationRepository interface {
+	Create(ctx context.Context, token *domain.EmailVerificationToken) error
+	FindByHash(ctx context.Context, tokenHash string) (*domain.EmailVerificationToken, error)
+	// FindLatestByUserID returns the most recently issued token of a user
+	FindLatestByUserID(ctx context.Context, userID string) (*domain.EmailVerificationToken, error)
+	DeleteByUserID(ctx context.Context, userID string) error
+}
@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/yourusername/userapi/internal/domain"
+)
+
+// ExternalIdentityRepository defines the interface for links to upstream identity providers
+type ExternalIdentityRepository interface {
+	Create(ctx context.Context, identity *domain.ExternalIdentity) error
+	FindBySubject(ctx context.Context, provider, subject string) (*domain.ExternalIdentity, error)
+	FindByUserID(ctx context.Context, userID string) ([]*domain.ExternalIdentity, error)
+	TouchLastLogin(ctx context.Context, id string, at time.Time) error
+	// DeleteByUserID removes every identity linked to a user
+	DeleteByUserID(ctx context.Context, userID string) error
+}
@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/yourusername/userapi/internal/domain"
+)
+
+// FederatedLoginStateRepository defines the interface for pending federated logins
+type FederatedLoginStateRepository interface {
+	Create(ctx context.Context, state *domain.FederatedLoginState) error
+	// Consume finds and removes a state in one step, so it can only be used once
+	Consume(ctx context.Context, stateHash string) (*domain.FederatedLoginState, error)
+}
@@ -0,0 +1,24 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/yourusername/userapi/internal/domain"
+)
+
+// GroupRepository defines the interface for group data access. Like
+// UserRepository, every method is scoped to the tenant set with WithTenant.
+type GroupRepository interface {
+	Create(ctx context.Context, group *domain.Group) error
+	FindByID(ctx context.Context, id string) (*domain.Group, error)
+	FindAll(ctx context.Context) ([]*domain.Group, error)
+	// FindByMember returns the groups a user is a member of
+	FindByMember(ctx context.Context, userID string) ([]*domain.Group, error)
+	// Update saves the group's name and description
+	Update(ctx context.Context, group *domain.Group) error
+	Delete(ctx context.Context, id string) error
+	AddMember(ctx context.Context, groupID, userID string) error
+	RemoveMember(ctx context.Context, groupID, userID string) error
+	// RemoveMemberFromAll removes a user from every group
+	RemoveMemberFromAll(ctx context.Context, userID string) error
+}
@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/yourusername/userapi/internal/domain"
+)
+
+// InvitationRepository defines the interface for invitation storage. Like
+// UserRepository it is scoped to the tenant set with WithTenant.
+type InvitationRepository interface {
+	Create(ctx context.Context, invitation *domain.Invitation) error
+	FindByID(ctx context.Context, id string) (*domain.Invitation, error)
+	// FindPending returns the invitations that were neither accepted nor revoked
+	FindPending(ctx context.Context) ([]*domain.Invitation, error)
+	// FindPendingByEmail returns the pending invitation for an email address
+	FindPendingByEmail(ctx context.Context, email string) (*domain.Invitation, error)
+	// Renew replaces the token of a pending invitation, so links sent before
+	// stop working. It returns domain.ErrInvitationNotFound if the
+	// invitation is no longer pending.
+	Renew(ctx context.Context, id, tokenHash string, expiresAt time.Time) error
+	// MarkAccepted atomically consumes a pending invitation. It returns
+	// domain.ErrInvalidToken if the invitation is no longer pending.
+	MarkAccepted(ctx context.Context, id string, at time.Time) error
+	// Revoke withdraws a pending invitation. It returns
+	// domain.ErrInvitationNotFound if the invitation is no longer pending.
+	Revoke(ctx context.Context, id string, at time.Time) error
+}
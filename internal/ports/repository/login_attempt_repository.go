@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/yourusername/userapi/internal/domain"
+)
+
+// LoginAttemptRepository defines the interface for failed login counters.
+// Keys name an account or a client IP.
+type LoginAttemptRepository interface {
+	// Get returns the failures recorded for key. A key without recent failures has a zero count.
+	Get(ctx context.Context, key string) (*domain.LoginAttempts, error)
+	// RecordFailure atomically adds a failure at now. Failures are forgotten
+	// once ttl has passed without a new one.
+	RecordFailure(ctx context.Context, key string, now time.Time, ttl time.Duration) (*domain.LoginAttempts, error)
+	Reset(ctx context.Context, key string) error
+}
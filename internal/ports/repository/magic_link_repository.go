@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/yourusername/userapi/internal/domain"
+)
+
+// MagicLinkRepository defines the interface for one-time login link storage
+type MagicLinkRepository interface {
+	Create(ctx context.Context, token *domain.MagicLinkToken) error
+	FindByHash(ctx context.Context, tokenHash string) (*domain.MagicLinkToken, error)
+	// MarkUsed atomically consumes an unused token. It returns
+	// domain.ErrInvalidToken if the token was already used.
+	MarkUsed(ctx context.Context, id string) error
+}
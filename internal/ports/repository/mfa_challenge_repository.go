@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/yourusername/userapi/internal/domain"
+)
+
+// MFAChallengeRepository defines the interface for pending MFA login challenges
+type MFAChallengeRepository interface {
+	Create(ctx context.Context, challenge *domain.MFAChallenge) error
+	FindByHash(ctx context.Context, tokenHash string) (*domain.MFAChallenge, error)
+	// IncrementAttempts records a failed code and returns the new attempt count
+	IncrementAttempts(ctx context.Context, id string) (int, error)
+	Delete(ctx context.Context, id string) error
+}
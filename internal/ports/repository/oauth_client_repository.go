@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/yourusername/userapi/internal/domain"
+)
+
+// OAuthClientRepository defines the interface for registered OAuth clients
+type OAuthClientRepository interface {
+	Create(ctx context.Context, client *domain.OAuthClient) error
+	FindByClientID(ctx context.Context, clientID string) (*domain.OAuthClient, error)
+	FindAll(ctx context.Context) ([]*domain.OAuthClient, error)
+}
@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/yourusername/userapi/internal/domain"
+)
+
+// OAuthConsentRepository defines the interface for the scopes users granted to clients
+type OAuthConsentRepository interface {
+	Find(ctx context.Context, userID, clientID string) (*domain.OAuthConsent, error)
+	// Save creates or replaces the consent of a user for a client
+	Save(ctx context.Context, consent *domain.OAuthConsent) error
+}
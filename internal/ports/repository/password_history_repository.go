@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/yourusername/userapi/internal/domain"
+)
+
+// PasswordHistoryRepository defines the interface for previously used password hashes
+type PasswordHistoryRepository interface {
+	Add(ctx context.Context, entry *domain.PasswordHistoryEntry) error
+	// FindRecent returns up to limit entries of a user, newest first
+	FindRecent(ctx context.Context, userID string, limit int) ([]*domain.PasswordHistoryEntry, error)
+	// Prune removes all but the newest keep entries of a user
+	Prune(ctx context.Context, userID string, keep int) error
+	// DeleteByUserID removes every entry of a user
+	DeleteByUserID(ctx context.Context, userID string) error
+}
@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/yourusername/userapi/internal/domain"
+)
+
+// PasswordResetRepository defines the interface for password reset token storage
+type PasswordResetRepository interface {
+	Create(ctx context.Context, token *domain.PasswordResetToken) error
+	FindByHash(ctx context.Context, tokenHash string) (*domain.PasswordResetToken, error)
+	// MarkUsed atomically consumes an unused token. It returns
+	// domain.ErrInvalidToken if the token was already used.
+	MarkUsed(ctx context.Context, id string) error
+	// DeleteByUserID removes every outstanding token of a user
+	DeleteByUserID(ctx context.Context, userID string) error
+}
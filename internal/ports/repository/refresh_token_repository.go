@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/yourusername/userapi/internal/domain"
+)
+
+// RefreshTokenRepository defines the interface for refresh token storage
+type RefreshTokenRepository interface {
+	Create(ctx context.Context, token *domain.RefreshToken) error
+	FindByHash(ctx context.Context, tokenHash string) (*domain.RefreshToken, error)
+	// MarkRotated flags an active token as used. It returns
+	// domain.ErrRefreshTokenReused if the token was already rotated or revoked.
+	MarkRotated(ctx context.Context, id string) error
+	RevokeFamily(ctx context.Context, familyID string) error
+	RevokeAllForUser(ctx context.Context, userID string) error
+}
@@ -0,0 +1,22 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/yourusername/userapi/internal/domain"
+)
+
+// SigningKeyRepository defines the interface for managed signing key storage
+type SigningKeyRepository interface {
+	Create(ctx context.Context, key *domain.SigningKey) error
+	FindByID(ctx context.Context, id string) (*domain.SigningKey, error)
+	// FindUnretired returns every key that is not retired
+	FindUnretired(ctx context.Context) ([]*domain.SigningKey, error)
+	Update(ctx context.Context, key *domain.SigningKey) error
+}
+
+// KeyRotationEventRepository defines the interface for the key rotation audit log
+type KeyRotationEventRepository interface {
+	Create(ctx context.Context, event *domain.KeyRotationEvent) error
+	FindAll(ctx context.Context) ([]*domain.KeyRotationEvent, error)
+}
@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/yourusername/userapi/internal/domain"
+)
+
+// TenantRepository defines the interface for tenant data access
+type TenantRepository interface {
+	Create(ctx context.Context, tenant *domain.Tenant) error
+	FindByID(ctx context.Context, id string) (*domain.Tenant, error)
+	FindBySlug(ctx context.Context, slug string) (*domain.Tenant, error)
+	FindAll(ctx context.Context) ([]*domain.Tenant, error)
+}
+
+// tenantScopeKey is the context key for the tenant UserRepository is scoped to
+type tenantScopeKey struct{}
+
+// tenantScope restricts user operations to one tenant, or lets FindByID
+// look across tenants
+type tenantScope struct {
+	tenantID      string
+	acrossTenants bool
+}
+
+// WithTenant scopes UserRepository operations on the returned context to a
+// tenant. Implementations must fail with domain.ErrTenantRequired when no
+// tenant is set, so a caller cannot read other tenants' users by accident.
+// An empty tenantID removes the scope.
+func WithTenant(ctx context.Context, tenantID string) context.Context {
+	return context.WithValue(ctx, tenantScopeKey{}, tenantScope{tenantID: tenantID})
+}
+
+// AcrossTenants lets UserRepository.FindByID find a user of any tenant. It is
+// for IDs taken from records this service issued, e.g. the user a refresh
+// token belongs to, never for IDs that came with a request.
+func AcrossTenants(ctx context.Context) context.Context {
+	return context.WithValue(ctx, tenantScopeKey{}, tenantScope{acrossTenants: true})
+}
+
+// TenantFromContext returns the tenant ctx is scoped to, if any
+func TenantFromContext(ctx context.Context) (string, bool) {
+	scope, _ := ctx.Value(tenantScopeKey{}).(tenantScope)
+	return scope.tenantID, scope.tenantID != ""
+}
+
+// IsAcrossTenants reports whether ctx was created by AcrossTenants
+func IsAcrossTenants(ctx context.Context) bool {
+	scope, _ := ctx.Value(tenantScopeKey{}).(tenantScope)
+	return scope.acrossTenants
+}
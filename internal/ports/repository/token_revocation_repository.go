@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"context"
+	"time"
+)
+
+// TokenRevocationRepository defines the interface for access token revocation state
+type TokenRevocationRepository interface {
+	// Revoke denylists a single token ID until the token would have expired anyway
+	Revoke(ctx context.Context, tokenID string, expiresAt time.Time) error
+	IsRevoked(ctx context.Context, tokenID string) (bool, error)
+	// RevokeAllBefore invalidates every token issued to the user before the given time
+	RevokeAllBefore(ctx context.Context, userID string, before time.Time) error
+	RevokedBefore(ctx context.Context, userID string) (time.Time, error)
+}
@@ -2,17 +2,44 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/yourusername/userapi/internal/domain"
 )
 
-// UserRepository defines the interface for user data access
+// UserRepository defines the interface for user data access. Every method
+// is scoped to the tenant set on the context with WithTenant, and new users
+// are created in that tenant. Deleted users are hidden from FindByID,
+// FindByEmail, FindAll and Count unless the context comes from IncludeDeleted.
 type UserRepository interface {
 	Create(ctx context.Context, user *domain.User) error
 	FindByID(ctx context.Context, id string) (*domain.User, error)
 	FindByEmail(ctx context.Context, email string) (*domain.User, error)
 	FindAll(ctx context.Context) ([]*domain.User, error)
 	Update(ctx context.Context, user *domain.User) error
+	// Delete marks a user as deleted. The record is kept until it is purged.
 	Delete(ctx context.Context, id string) error
+	// Restore undoes Delete. It returns domain.ErrEmailAlreadyExists if
+	// another user has taken the email address in the meantime.
+	Restore(ctx context.Context, id string) error
+	// FindDeletedBefore returns the users of every tenant deleted before the given time
+	FindDeletedBefore(ctx context.Context, before time.Time) ([]*domain.User, error)
+	// Purge removes a deleted user for good
+	Purge(ctx context.Context, id string) error
 	Count(ctx context.Context) (int64, error)
+}
+
+// includeDeletedKey is the context key set by IncludeDeleted
+type includeDeletedKey struct{}
+
+// IncludeDeleted makes UserRepository lookups on the returned context find
+// deleted users as well, e.g. to restore them
+func IncludeDeleted(ctx context.Context) context.Context {
+	return context.WithValue(ctx, includeDeletedKey{}, true)
+}
+
+// IncludesDeleted reports whether ctx was created by IncludeDeleted
+func IncludesDeleted(ctx context.Context) bool {
+	included, _ := ctx.Value(includeDeletedKey{}).(bool)
+	return included
 }
\ No newline at end of file
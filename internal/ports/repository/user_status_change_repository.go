@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/yourusername/userapi/internal/domain"
+)
+
+// UserStatusChangeRepository defines the interface for the user status audit log
+type UserStatusChangeRepository interface {
+	Create(ctx context.Context, change *domain.UserStatusChange) error
+	// FindByUserID returns a user's status changes, oldest first
+	FindByUserID(ctx context.Context, userID string) ([]*domain.UserStatusChange, error)
+}
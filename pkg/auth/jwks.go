@@ -0,0 +1,137 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"fmt"
+	"math/big"
+)
+
+// JWK is a single JSON Web Key (RFC 7517)
+type JWK struct {
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+}
+
+// JWKS is a JSON Web Key Set
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWK returns the public part of the key. Symmetric keys are never published.
+func (k *SigningKey) JWK() (JWK, bool) {
+	jwk := JWK{
+		Use: "sig",
+		Kid: k.ID,
+		Alg: k.Algorithm(),
+	}
+
+	switch key := k.verifyKey.(type) {
+	case *rsa.PublicKey:
+		jwk.Kty = "RSA"
+		jwk.N = encodeBase64URL(key.N.Bytes())
+		jwk.E = encodeBase64URL(big.NewInt(int64(key.E)).Bytes())
+	case *ecdsa.PublicKey:
+		size := (key.Curve.Params().BitSize + 7) / 8
+		jwk.Kty = "EC"
+		jwk.Crv = key.Curve.Params().Name
+		jwk.X = encodeBase64URL(key.X.FillBytes(make([]byte, size)))
+		jwk.Y = encodeBase64URL(key.Y.FillBytes(make([]byte, size)))
+	case ed25519.PublicKey:
+		jwk.Kty = "OKP"
+		jwk.Crv = "Ed25519"
+		jwk.X = encodeBase64URL(key)
+	default:
+		return JWK{}, false
+	}
+
+	return jwk, true
+}
+
+// SigningKey converts a published key back into a verification-only key.
+// Keys without an "alg" are assumed to use the default algorithm of their type.
+func (jwk JWK) SigningKey() (*SigningKey, error) {
+	var (
+		publicKey crypto.PublicKey
+		alg       string
+	)
+
+	switch jwk.Kty {
+	case "RSA":
+		n, err := decodeBase64URL(jwk.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBase64URL(jwk.E)
+		if err != nil {
+			return nil, err
+		}
+		publicKey = &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}
+		alg = AlgRS256
+	case "EC":
+		if jwk.Crv != elliptic.P256().Params().Name {
+			return nil, fmt.Errorf("unsupported curve: %s", jwk.Crv)
+		}
+		x, err := decodeBase64URL(jwk.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBase64URL(jwk.Y)
+		if err != nil {
+			return nil, err
+		}
+		publicKey = &ecdsa.PublicKey{Curve: elliptic.P256(), X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+		alg = AlgES256
+	case "OKP":
+		if jwk.Crv != "Ed25519" {
+			return nil, fmt.Errorf("unsupported curve: %s", jwk.Crv)
+		}
+		x, err := decodeBase64URL(jwk.X)
+		if err != nil {
+			return nil, err
+		}
+		if len(x) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("invalid Ed25519 key size")
+		}
+		publicKey = ed25519.PublicKey(x)
+		alg = AlgEdDSA
+	default:
+		return nil, fmt.Errorf("unsupported key type: %s", jwk.Kty)
+	}
+
+	if jwk.Alg != "" {
+		alg = jwk.Alg
+	}
+
+	return NewPublicKey(jwk.Kid, alg, publicKey)
+}
+
+// JWKS returns the public keys that can verify tokens issued by this authenticator
+func (j *JWTAuth) JWKS() JWKS {
+	set := JWKS{Keys: []JWK{}}
+	for _, key := range j.keyring.Keys() {
+		if jwk, ok := key.JWK(); ok {
+			set.Keys = append(set.Keys, jwk)
+		}
+	}
+	return set
+}
+
+func encodeBase64URL(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func decodeBase64URL(s string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(s)
+}
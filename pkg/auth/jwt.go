@@ -1,72 +1,291 @@
 package auth
 
 import (
-	"fmt"
+	"context"
+	"errors"
+	"strings"
 	"time"
 
 	"github.com/golang-jwt/jwt/v4"
 )
 
+var (
+	// ErrTokenRevoked is returned when a token has been revoked server-side
+	ErrTokenRevoked = errors.New("token has been revoked")
+	// ErrNoSigningKey is returned when the keyring has no key to sign with
+	ErrNoSigningKey = errors.New("no signing key available")
+	// ErrInvalidIssuer is returned when the "iss" claim does not match
+	ErrInvalidIssuer = errors.New("token issuer is not accepted")
+	// ErrInvalidAudience is returned when the "aud" claim does not name this service
+	ErrInvalidAudience = errors.New("token audience is not accepted")
+)
+
+// RevocationStore is consulted by ValidateToken to reject revoked tokens
+type RevocationStore interface {
+	IsRevoked(ctx context.Context, tokenID string) (bool, error)
+	// RevokedBefore returns the user's watermark. Tokens issued before it are
+	// no longer valid. A zero time means no watermark is set.
+	RevokedBefore(ctx context.Context, userID string) (time.Time, error)
+}
+
+// defaultKeyID is the "kid" of the key created from a shared secret
+const defaultKeyID = "default"
+
 // JWTAuth handles JWT operations
 type JWTAuth struct {
-	secretKey []byte
-	expiry    time.Duration
+	keyring     *Keyring
+	expiry      time.Duration
+	issuer      string
+	audience    []string
+	leeway      time.Duration
+	revocations RevocationStore
+}
+
+// Option configures optional JWTAuth behaviour
+type Option func(*JWTAuth)
+
+// WithRevocationStore makes ValidateToken reject revoked tokens
+func WithRevocationStore(store RevocationStore) Option {
+	return func(j *JWTAuth) {
+		j.revocations = store
+	}
+}
+
+// WithIssuer stamps the "iss" claim on new tokens and requires it on validation
+func WithIssuer(issuer string) Option {
+	return func(j *JWTAuth) {
+		j.issuer = issuer
+	}
 }
 
-// Claims defines the JWT claims
+// WithAudience stamps the "aud" claim on new tokens. Validation requires
+// the token to be addressed to at least one of the audiences.
+func WithAudience(audience ...string) Option {
+	return func(j *JWTAuth) {
+		j.audience = audience
+	}
+}
+
+// WithLeeway allows for clock skew when checking time based claims
+func WithLeeway(leeway time.Duration) Option {
+	return func(j *JWTAuth) {
+		j.leeway = leeway
+	}
+}
+
+// WithVerificationKeys accepts tokens signed by additional keys
+func WithVerificationKeys(keys ...*SigningKey) Option {
+	return func(j *JWTAuth) {
+		for _, key := range keys {
+			j.keyring.AddStatic(key)
+		}
+	}
+}
+
+// Claims defines the JWT claims. The token ID is carried in the
+// registered "jti" claim so individual tokens can be revoked.
 type Claims struct {
 	UserID string `json:"user_id"`
-	Email  string `json:"email"`
+	// TenantID is the organization the user belongs to
+	TenantID string `json:"tenant_id,omitempty"`
+	Email    string `json:"email"`
+	Role     string `json:"role,omitempty"`
+	// Scope is a space-delimited list of granted scopes
+	Scope string `json:"scope,omitempty"`
+	// ClientID names the OAuth client the token was issued to, if any
+	ClientID string `json:"client_id,omitempty"`
 	jwt.RegisteredClaims
 }
 
-// NewJWTAuth creates a new JWT authenticator
-func NewJWTAuth(secretKey string, expiry time.Duration) *JWTAuth {
-	return &JWTAuth{
-		secretKey: []byte(secretKey),
-		expiry:    expiry,
+// subject is the user the token is about, or the client for tokens issued
+// with the client credentials grant
+func (c *Claims) subject() string {
+	if c.UserID == "" {
+		return c.ClientID
+	}
+	return c.UserID
+}
+
+// Scopes returns the granted scopes
+func (c *Claims) Scopes() []string {
+	return strings.Fields(c.Scope)
+}
+
+// HasScope reports whether the given scope was granted
+func (c *Claims) HasScope(scope string) bool {
+	for _, granted := range c.Scopes() {
+		if granted == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// NewJWTAuth creates a new JWT authenticator that signs with HS256
+func NewJWTAuth(secretKey string, expiry time.Duration, opts ...Option) *JWTAuth {
+	return NewJWTAuthWithKey(NewHMACKey(defaultKeyID, []byte(secretKey)), expiry, opts...)
+}
+
+// NewJWTAuthWithKey creates a new JWT authenticator that signs with the given key
+func NewJWTAuthWithKey(signingKey *SigningKey, expiry time.Duration, opts ...Option) *JWTAuth {
+	return NewJWTAuthWithKeyring(NewKeyring(signingKey), expiry, opts...)
+}
+
+// NewJWTAuthWithKeyring creates a new JWT authenticator backed by a rotatable keyring
+func NewJWTAuthWithKeyring(keyring *Keyring, expiry time.Duration, opts ...Option) *JWTAuth {
+	j := &JWTAuth{
+		keyring: keyring,
+		expiry:  expiry,
+	}
+
+	for _, opt := range opts {
+		opt(j)
+	}
+
+	return j
+}
+
+// Keyring returns the keys used to sign and verify tokens
+func (j *JWTAuth) Keyring() *Keyring {
+	return j.keyring
+}
+
+// Issuer returns the "iss" claim stamped on new tokens
+func (j *JWTAuth) Issuer() string {
+	return j.issuer
+}
+
+// Expiry returns the lifetime of the tokens this authenticator issues
+func (j *JWTAuth) Expiry() time.Duration {
+	return j.expiry
+}
+
+// GenerateToken signs a new JWT token. The caller fills in the user and scope
+// claims, the registered claims are set here.
+func (j *JWTAuth) GenerateToken(claims *Claims) (string, error) {
+	registered, err := j.registeredClaims(claims.subject(), j.audience)
+	if err != nil {
+		return "", err
+	}
+	claims.RegisteredClaims = registered
+
+	return j.sign(claims)
+}
+
+// registeredClaims returns fresh registered claims for a new token
+func (j *JWTAuth) registeredClaims(subject string, audience []string) (jwt.RegisteredClaims, error) {
+	tokenID, err := newTokenID()
+	if err != nil {
+		return jwt.RegisteredClaims{}, err
 	}
+
+	now := time.Now()
+	return jwt.RegisteredClaims{
+		ID:        tokenID,
+		Issuer:    j.issuer,
+		Subject:   subject,
+		Audience:  audience,
+		ExpiresAt: jwt.NewNumericDate(now.Add(j.expiry)),
+		IssuedAt:  jwt.NewNumericDate(now),
+	}, nil
 }
 
-// GenerateToken creates a new JWT token for a user
-func (j *JWTAuth) GenerateToken(userID, email string) (string, error) {
-	claims := &Claims{
-		UserID: userID,
-		Email:  email,
-		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(j.expiry)),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
-		},
+// sign signs claims with the keyring's current signing key
+func (j *JWTAuth) sign(claims jwt.Claims) (string, error) {
+	signingKey := j.keyring.SigningKey()
+	if signingKey == nil || !signingKey.CanSign() {
+		return "", ErrNoSigningKey
 	}
-	
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tokenString, err := token.SignedString(j.secretKey)
+
+	token := jwt.NewWithClaims(signingKey.Method, claims)
+	token.Header["kid"] = signingKey.ID
+	tokenString, err := token.SignedString(signingKey.signKey)
 	if err != nil {
 		return "", err
 	}
-	
+
 	return tokenString, nil
 }
 
 // ValidateToken validates a JWT token and returns the claims
-func (j *JWTAuth) ValidateToken(tokenString string) (*Claims, error) {
+func (j *JWTAuth) ValidateToken(ctx context.Context, tokenString string) (*Claims, error) {
 	claims := &Claims{}
-	
-	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
-		// Validate the signing method
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-		}
-		return j.secretKey, nil
-	})
-	
-	if err != nil {
+
+	// Time based claims are checked by validateClaims to allow for leeway
+	if err := j.keyring.Parse(tokenString, claims); err != nil {
 		return nil, err
 	}
-	
-	if !token.Valid {
-		return nil, fmt.Errorf("invalid token")
+
+	if err := j.validateClaims(claims); err != nil {
+		return nil, err
 	}
-	
+
+	if err := j.checkRevocation(ctx, claims); err != nil {
+		return nil, err
+	}
+
 	return claims, nil
 }
+
+// validateClaims checks the registered claims, allowing for clock skew
+func (j *JWTAuth) validateClaims(claims *Claims) error {
+	now := time.Now()
+
+	if !claims.VerifyExpiresAt(now.Add(-j.leeway), true) {
+		return errors.New("token is expired")
+	}
+	if !claims.VerifyIssuedAt(now.Add(j.leeway), false) {
+		return errors.New("token used before issued")
+	}
+	if !claims.VerifyNotBefore(now.Add(j.leeway), false) {
+		return errors.New("token is not valid yet")
+	}
+
+	if j.issuer != "" && !claims.VerifyIssuer(j.issuer, true) {
+		return ErrInvalidIssuer
+	}
+
+	if len(j.audience) > 0 {
+		accepted := false
+		for _, aud := range j.audience {
+			if claims.VerifyAudience(aud, true) {
+				accepted = true
+				break
+			}
+		}
+		if !accepted {
+			return ErrInvalidAudience
+		}
+	}
+
+	return nil
+}
+
+// checkRevocation rejects tokens revoked by ID or issued before the user's watermark
+func (j *JWTAuth) checkRevocation(ctx context.Context, claims *Claims) error {
+	if j.revocations == nil {
+		return nil
+	}
+
+	if claims.ID != "" {
+		revoked, err := j.revocations.IsRevoked(ctx, claims.ID)
+		if err != nil {
+			return err
+		}
+		if revoked {
+			return ErrTokenRevoked
+		}
+	}
+
+	before, err := j.revocations.RevokedBefore(ctx, claims.UserID)
+	if err != nil {
+		return err
+	}
+
+	// "iat" only has second precision, so compare at that granularity
+	if !before.IsZero() && (claims.IssuedAt == nil || claims.IssuedAt.Time.Before(before.Truncate(time.Second))) {
+		return ErrTokenRevoked
+	}
+
+	return nil
+}
@@ -0,0 +1,258 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// Supported signing algorithms
+const (
+	AlgHS256 = "HS256"
+	AlgRS256 = "RS256"
+	AlgES256 = "ES256"
+	AlgEdDSA = "EdDSA"
+)
+
+// SigningKey is a key used to sign and verify tokens.
+// Keys loaded from a public key only can verify but not sign.
+type SigningKey struct {
+	ID        string
+	Method    jwt.SigningMethod
+	signKey   interface{}
+	verifyKey interface{}
+}
+
+// NewHMACKey creates a symmetric HS256 key from a shared secret
+func NewHMACKey(kid string, secret []byte) *SigningKey {
+	return &SigningKey{
+		ID:        kid,
+		Method:    jwt.SigningMethodHS256,
+		signKey:   secret,
+		verifyKey: secret,
+	}
+}
+
+// NewPrivateKey creates an asymmetric signing key for the given algorithm.
+// If kid is empty it is derived from the public key.
+func NewPrivateKey(kid, alg string, privateKey crypto.Signer) (*SigningKey, error) {
+	method, err := asymmetricMethod(alg)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkKeyType(alg, privateKey.Public()); err != nil {
+		return nil, err
+	}
+
+	if kid == "" {
+		if kid, err = thumbprint(privateKey.Public()); err != nil {
+			return nil, err
+		}
+	}
+
+	return &SigningKey{
+		ID:        kid,
+		Method:    method,
+		signKey:   privateKey,
+		verifyKey: privateKey.Public(),
+	}, nil
+}
+
+// NewPublicKey creates a verification-only key for the given algorithm
+func NewPublicKey(kid, alg string, publicKey crypto.PublicKey) (*SigningKey, error) {
+	method, err := asymmetricMethod(alg)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkKeyType(alg, publicKey); err != nil {
+		return nil, err
+	}
+
+	if kid == "" {
+		if kid, err = thumbprint(publicKey); err != nil {
+			return nil, err
+		}
+	}
+
+	return &SigningKey{
+		ID:        kid,
+		Method:    method,
+		verifyKey: publicKey,
+	}, nil
+}
+
+// ParsePrivateKeyPEM parses a PEM encoded private key for the given algorithm
+func ParsePrivateKeyPEM(kid, alg string, pemBytes []byte) (*SigningKey, error) {
+	var (
+		privateKey crypto.Signer
+		err        error
+	)
+
+	switch alg {
+	case AlgRS256:
+		privateKey, err = jwt.ParseRSAPrivateKeyFromPEM(pemBytes)
+	case AlgES256:
+		privateKey, err = jwt.ParseECPrivateKeyFromPEM(pemBytes)
+	case AlgEdDSA:
+		var key crypto.PrivateKey
+		key, err = jwt.ParseEdPrivateKeyFromPEM(pemBytes)
+		if err == nil {
+			privateKey, _ = key.(crypto.Signer)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported signing algorithm: %s", alg)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return NewPrivateKey(kid, alg, privateKey)
+}
+
+// ParsePublicKeyPEM parses a PEM encoded public key for the given algorithm
+func ParsePublicKeyPEM(kid, alg string, pemBytes []byte) (*SigningKey, error) {
+	var (
+		publicKey crypto.PublicKey
+		err       error
+	)
+
+	switch alg {
+	case AlgRS256:
+		publicKey, err = jwt.ParseRSAPublicKeyFromPEM(pemBytes)
+	case AlgES256:
+		publicKey, err = jwt.ParseECPublicKeyFromPEM(pemBytes)
+	case AlgEdDSA:
+		publicKey, err = jwt.ParseEdPublicKeyFromPEM(pemBytes)
+	default:
+		return nil, fmt.Errorf("unsupported signing algorithm: %s", alg)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return NewPublicKey(kid, alg, publicKey)
+}
+
+// LoadPrivateKeyFile reads a PEM encoded private key from disk
+func LoadPrivateKeyFile(kid, alg, path string) (*SigningKey, error) {
+	pemBytes, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParsePrivateKeyPEM(kid, alg, pemBytes)
+}
+
+// GenerateSigningKey creates a fresh asymmetric key for the given algorithm.
+// Its ID is derived from the public key.
+func GenerateSigningKey(alg string) (*SigningKey, error) {
+	var (
+		privateKey crypto.Signer
+		err        error
+	)
+
+	switch alg {
+	case AlgRS256:
+		privateKey, err = rsa.GenerateKey(rand.Reader, 2048)
+	case AlgES256:
+		privateKey, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	case AlgEdDSA:
+		_, privateKey, err = ed25519.GenerateKey(rand.Reader)
+	default:
+		return nil, fmt.Errorf("unsupported signing algorithm: %s", alg)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return NewPrivateKey("", alg, privateKey)
+}
+
+// PrivateKeyPEM encodes the private key as PKCS #8 PEM
+func (k *SigningKey) PrivateKeyPEM() ([]byte, error) {
+	if !k.CanSign() || k.IsSymmetric() {
+		return nil, errors.New("key has no asymmetric private part")
+	}
+
+	der, err := x509.MarshalPKCS8PrivateKey(k.signKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), nil
+}
+
+// Algorithm returns the JWS "alg" value of the key
+func (k *SigningKey) Algorithm() string {
+	return k.Method.Alg()
+}
+
+// CanSign reports whether the key holds private material
+func (k *SigningKey) CanSign() bool {
+	return k.signKey != nil
+}
+
+// IsSymmetric reports whether the key is a shared secret
+func (k *SigningKey) IsSymmetric() bool {
+	_, ok := k.Method.(*jwt.SigningMethodHMAC)
+	return ok
+}
+
+// asymmetricMethod maps an algorithm name to its signing method
+func asymmetricMethod(alg string) (jwt.SigningMethod, error) {
+	switch alg {
+	case AlgRS256:
+		return jwt.SigningMethodRS256, nil
+	case AlgES256:
+		return jwt.SigningMethodES256, nil
+	case AlgEdDSA:
+		return jwt.SigningMethodEdDSA, nil
+	}
+	return nil, fmt.Errorf("unsupported signing algorithm: %s", alg)
+}
+
+// checkKeyType ensures the key matches the algorithm it will be used with
+func checkKeyType(alg string, publicKey crypto.PublicKey) error {
+	ok := false
+	switch key := publicKey.(type) {
+	case *rsa.PublicKey:
+		ok = alg == AlgRS256 && key.N.BitLen() >= 2048
+	case *ecdsa.PublicKey:
+		ok = alg == AlgES256 && key.Curve == elliptic.P256()
+	case ed25519.PublicKey:
+		ok = alg == AlgEdDSA
+	}
+
+	if !ok {
+		return fmt.Errorf("key of type %T is not valid for %s", publicKey, alg)
+	}
+	return nil
+}
+
+// thumbprint derives a key ID from the SHA-256 digest of the public key
+func thumbprint(publicKey crypto.PublicKey) (string, error) {
+	der, err := x509.MarshalPKIXPublicKey(publicKey)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(der)
+	return base64.RawURLEncoding.EncodeToString(sum[:]), nil
+}
@@ -0,0 +1,37 @@
+package auth
+
+import (
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// IDTokenClaims defines the claims of an OpenID Connect ID token. The
+// profile and email claims are only set when the matching scope was granted.
+type IDTokenClaims struct {
+	Name          string `json:"name,omitempty"`
+	Email         string `json:"email,omitempty"`
+	EmailVerified *bool  `json:"email_verified,omitempty"`
+	// Nonce echoes the nonce of the authorization request
+	Nonce string `json:"nonce,omitempty"`
+	jwt.RegisteredClaims
+}
+
+// GenerateIDToken signs an ID token about subject for the given client. ID
+// tokens are addressed to the client rather than to this service's audience.
+func (j *JWTAuth) GenerateIDToken(subject, clientID string, claims *IDTokenClaims) (string, error) {
+	registered, err := j.registeredClaims(subject, jwt.ClaimStrings{clientID})
+	if err != nil {
+		return "", err
+	}
+	claims.RegisteredClaims = registered
+
+	return j.sign(claims)
+}
+
+// SigningAlgorithm returns the "alg" new tokens are signed with
+func (j *JWTAuth) SigningAlgorithm() string {
+	signingKey := j.keyring.SigningKey()
+	if signingKey == nil {
+		return ""
+	}
+	return signingKey.Algorithm()
+}
@@ -0,0 +1,25 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+)
+
+// PKCEMethodS256 is the only PKCE code challenge method accepted (RFC 7636)
+const PKCEMethodS256 = "S256"
+
+// PKCEChallenge derives the S256 code challenge of a code verifier
+func PKCEChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// VerifyPKCE reports whether verifier matches an S256 code challenge
+func VerifyPKCE(verifier, challenge string) bool {
+	// RFC 7636 requires 43 to 128 characters
+	if len(verifier) < 43 || len(verifier) > 128 {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(PKCEChallenge(verifier)), []byte(challenge)) == 1
+}
@@ -0,0 +1,64 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+)
+
+const (
+	// opaqueTokenBytes is the amount of randomness in an opaque token
+	opaqueTokenBytes = 32
+	// tokenIDBytes is the amount of randomness in a JWT "jti" claim
+	tokenIDBytes = 16
+	// apiKeyIDBytes is the amount of randomness in the identifying part of an API key
+	apiKeyIDBytes = 6
+)
+
+// APIKeyPrefix starts every API key so it can be recognised, e.g. by secret scanners
+const APIKeyPrefix = "uak_"
+
+// GenerateOpaqueToken creates a random, URL-safe token that carries no claims
+func GenerateOpaqueToken() (string, error) {
+	b := make([]byte, opaqueTokenBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// HashOpaqueToken returns the hex-encoded SHA-256 digest of an opaque token.
+// Only the digest should be persisted.
+func HashOpaqueToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// GenerateAPIKey creates a new API key of the form "uak_<id>_<secret>".
+// prefix is the "uak_<id>" part, which may be shown to identify the key.
+func GenerateAPIKey() (key, prefix string, err error) {
+	b := make([]byte, apiKeyIDBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", err
+	}
+
+	secret, err := GenerateOpaqueToken()
+	if err != nil {
+		return "", "", err
+	}
+
+	prefix = APIKeyPrefix + hex.EncodeToString(b)
+	return prefix + "_" + secret, prefix, nil
+}
+
+// newTokenID creates a random identifier for the "jti" claim
+func newTokenID() (string, error) {
+	b := make([]byte, tokenIDBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,68 @@
+package auth
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// rfc6238Secret is the SHA1 key of the RFC 6238 test vectors, "12345678901234567890"
+const rfc6238Secret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestValidateTOTP(t *testing.T) {
+	tests := []struct {
+		name     string
+		secret   string
+		code     string
+		at       int64
+		wantStep int64
+		wantOK   bool
+	}{
+		// The last six digits of the RFC 6238 appendix B values
+		{name: "rfc vector 59", secret: rfc6238Secret, code: "287082", at: 59, wantStep: 1, wantOK: true},
+		{name: "rfc vector 1111111109", secret: rfc6238Secret, code: "081804", at: 1111111109, wantStep: 37037036, wantOK: true},
+		{name: "rfc vector 1234567890", secret: rfc6238Secret, code: "005924", at: 1234567890, wantStep: 41152263, wantOK: true},
+		{name: "rfc vector 2000000000", secret: rfc6238Secret, code: "279037", at: 2000000000, wantStep: 66666666, wantOK: true},
+		{name: "previous period", secret: rfc6238Secret, code: "081804", at: 1111111109 + 30, wantStep: 37037036, wantOK: true},
+		{name: "next period", secret: rfc6238Secret, code: "081804", at: 1111111109 - 30, wantStep: 37037036, wantOK: true},
+		{name: "two periods late", secret: rfc6238Secret, code: "081804", at: 1111111109 + 60},
+		{name: "lowercase secret with spaces", secret: " " + strings.ToLower(rfc6238Secret) + " ", code: "287082", at: 59, wantStep: 1, wantOK: true},
+		{name: "wrong code", secret: rfc6238Secret, code: "287083", at: 59},
+		{name: "wrong length", secret: rfc6238Secret, code: "94287082", at: 59},
+		{name: "invalid secret", secret: "not base32!", code: "287082", at: 59},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			step, ok := ValidateTOTP(tt.secret, tt.code, time.Unix(tt.at, 0))
+			if ok != tt.wantOK || step != tt.wantStep {
+				t.Errorf("ValidateTOTP() = %d, %v, want %d, %v", step, ok, tt.wantStep, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestGenerateTOTPSecret(t *testing.T) {
+	secret, err := GenerateTOTPSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := GenerateTOTPSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if secret == other {
+		t.Error("two secrets are equal")
+	}
+
+	key, err := totpEncoding.DecodeString(secret)
+	if err != nil || len(key) != totpSecretBytes {
+		t.Fatalf("secret %q decodes to %d bytes, %v", secret, len(key), err)
+	}
+
+	now := time.Now()
+	code := totpCode(key, now.Unix()/int64(totpPeriod.Seconds()))
+	if _, ok := ValidateTOTP(secret, code, now); !ok {
+		t.Errorf("the current code %s of a generated secret is rejected", code)
+	}
+}
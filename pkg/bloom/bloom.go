@@ -0,0 +1,68 @@
+package bloom
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"math"
+	"math/bits"
+)
+
+// Filter is a bloom filter over strings. Test never returns a false negative,
+// and returns a false positive at about the rate the filter was sized for.
+type Filter struct {
+	bits []uint64
+	m    uint64
+	k    uint64
+}
+
+// New creates a filter sized for n items at false positive rate p. The number
+// of bits is rounded up to a power of two, which lowers the rate further.
+func New(n int, p float64) *Filter {
+	if n < 1 {
+		n = 1
+	}
+	if p <= 0 || p >= 1 {
+		p = 0.001
+	}
+
+	m := uint64(math.Ceil(-float64(n) * math.Log(p) / (math.Ln2 * math.Ln2)))
+	// With a power of two, every odd step is coprime with m
+	m = 1 << bits.Len64(m-1)
+	k := uint64(math.Max(1, math.Round(float64(m)/float64(n)*math.Ln2)))
+
+	return &Filter{
+		bits: make([]uint64, (m+63)/64),
+		m:    m,
+		k:    k,
+	}
+}
+
+// Add adds an item to the filter
+func (f *Filter) Add(item string) {
+	h1, h2 := hashes(item)
+	for i := uint64(0); i < f.k; i++ {
+		bit := (h1 + i*h2) % f.m
+		f.bits[bit/64] |= 1 << (bit % 64)
+	}
+}
+
+// Test reports whether the item may have been added
+func (f *Filter) Test(item string) bool {
+	h1, h2 := hashes(item)
+	for i := uint64(0); i < f.k; i++ {
+		bit := (h1 + i*h2) % f.m
+		if f.bits[bit/64]&(1<<(bit%64)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// hashes derives the two base hashes used for double hashing
+func hashes(item string) (uint64, uint64) {
+	sum := sha256.Sum256([]byte(item))
+	h1 := binary.BigEndian.Uint64(sum[0:8])
+	// An odd step visits every bit position before repeating, as m is a power of two
+	h2 := binary.BigEndian.Uint64(sum[8:16]) | 1
+	return h1, h2
+}
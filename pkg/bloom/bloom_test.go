@@ -0,0 +1,49 @@
+package bloom
+
+import (
+	"strconv"
+	"testing"
+)
+
+func TestFilter(t *testing.T) {
+	tests := []struct {
+		name string
+		n    int
+		p    float64
+	}{
+		{"small", 10, 0.01},
+		{"large", 10000, 0.001},
+		{"invalid rate falls back to the default", 1000, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := New(tt.n, tt.p)
+			for i := 0; i < tt.n; i++ {
+				f.Add("added-" + strconv.Itoa(i))
+			}
+
+			for i := 0; i < tt.n; i++ {
+				if !f.Test("added-" + strconv.Itoa(i)) {
+					t.Fatalf("false negative for added-%d", i)
+				}
+			}
+
+			// The rate is an estimate, allow for some noise
+			const probes = 20000
+			p := tt.p
+			if p <= 0 || p >= 1 {
+				p = 0.001
+			}
+			falsePositives := 0
+			for i := 0; i < probes; i++ {
+				if f.Test("missing-" + strconv.Itoa(i)) {
+					falsePositives++
+				}
+			}
+			if rate := float64(falsePositives) / probes; rate > 3*p {
+				t.Errorf("false positive rate %.4f, want at most about %.4f", rate, p)
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package breached
+
+import (
+	"bufio"
+	_ "embed"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/yourusername/userapi/pkg/bloom"
+)
+
+// falsePositiveRate is the chance of rejecting a password that is not on the list
+const falsePositiveRate = 0.0001
+
+//go:embed common_passwords.txt
+var bundled string
+
+var (
+	defaultOnce sync.Once
+	defaultList *List
+)
+
+// List is a breached password list held as a bloom filter.
+// Passwords are compared case-insensitively.
+type List struct {
+	filter *bloom.Filter
+}
+
+// Default returns the list of common passwords bundled with the binary
+func Default() *List {
+	defaultOnce.Do(func() {
+		defaultList, _ = Load(strings.NewReader(bundled))
+	})
+	return defaultList
+}
+
+// LoadFile loads a list with one password per line from a local file
+func LoadFile(path string) (*List, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return Load(f)
+}
+
+// Load reads a list with one password per line. Blank lines and lines
+// starting with "#" are skipped.
+func Load(r io.Reader) (*List, error) {
+	var passwords []string
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		passwords = append(passwords, normalize(line))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	filter := bloom.New(len(passwords), falsePositiveRate)
+	for _, password := range passwords {
+		filter.Add(password)
+	}
+
+	return &List{filter: filter}, nil
+}
+
+// Contains reports whether password is, most likely, on the list
+func (l *List) Contains(password string) bool {
+	return l.filter.Test(normalize(password))
+}
+
+func normalize(password string) string {
+	return strings.ToLower(password)
+}
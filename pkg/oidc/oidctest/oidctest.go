@@ -0,0 +1,223 @@
+// Package oidctest runs an in-process OpenID Connect provider for tests.
+//
+// The provider serves discovery, JWKS and token endpoints. Tests skip the
+// browser: Authorize takes the URL a relying party would redirect to and
+// returns the code the provider would send back.
+package oidctest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/yourusername/userapi/pkg/auth"
+)
+
+// Client credentials the provider accepts
+const (
+	ClientID     = "test-client"
+	ClientSecret = "test-client-secret"
+)
+
+// User is the account signed in at the provider. The override fields make
+// the ID token wrong in one way.
+type User struct {
+	Subject       string
+	Name          string
+	Email         string
+	EmailVerified bool
+
+	// Nonce replaces the nonce of the authorization request
+	Nonce string
+	// Issuer replaces the provider's issuer
+	Issuer string
+	// Audience replaces the client ID
+	Audience string
+}
+
+// grant is an issued authorization code
+type grant struct {
+	user          User
+	redirectURI   string
+	nonce         string
+	codeChallenge string
+}
+
+// Provider is a fake OpenID Connect provider
+type Provider struct {
+	*httptest.Server
+	t *testing.T
+
+	mu     sync.Mutex
+	key    *auth.SigningKey
+	codes  map[string]grant
+	issued int
+	// jwksRequests counts how often the key set was fetched
+	jwksRequests int
+}
+
+// NewProvider starts a provider that is stopped when the test ends
+func NewProvider(t *testing.T) *Provider {
+	t.Helper()
+
+	p := &Provider{t: t, codes: make(map[string]grant)}
+	p.RotateKey()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", p.discovery)
+	mux.HandleFunc("/jwks", p.jwks)
+	mux.HandleFunc("/token", p.token)
+	p.Server = httptest.NewServer(mux)
+	t.Cleanup(p.Close)
+
+	return p
+}
+
+// Issuer returns the provider's issuer URL
+func (p *Provider) Issuer() string {
+	return p.URL
+}
+
+// JWKSRequests returns how often the key set was fetched
+func (p *Provider) JWKSRequests() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.jwksRequests
+}
+
+// RotateKey replaces the signing key. The old key is no longer published.
+func (p *Provider) RotateKey() {
+	p.t.Helper()
+
+	key, err := auth.GenerateSigningKey(auth.AlgES256)
+	if err != nil {
+		p.t.Fatal(err)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.key = key
+}
+
+// Authorize signs user in for the authorization request in authURL and
+// returns the authorization code
+func (p *Provider) Authorize(authURL string, user User) string {
+	p.t.Helper()
+
+	u, err := url.Parse(authURL)
+	if err != nil {
+		p.t.Fatal(err)
+	}
+	query := u.Query()
+	if query.Get("client_id") != ClientID || query.Get("code_challenge_method") != auth.PKCEMethodS256 {
+		p.t.Fatalf("unexpected authorization request: %s", authURL)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.issued++
+	code := "code-" + strconv.Itoa(p.issued)
+	p.codes[code] = grant{
+		user:          user,
+		redirectURI:   query.Get("redirect_uri"),
+		nonce:         query.Get("nonce"),
+		codeChallenge: query.Get("code_challenge"),
+	}
+	return code
+}
+
+func (p *Provider) discovery(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{
+		"issuer":                 p.URL,
+		"authorization_endpoint": p.URL + "/authorize",
+		"token_endpoint":         p.URL + "/token",
+		"jwks_uri":               p.URL + "/jwks",
+	})
+}
+
+func (p *Provider) jwks(w http.ResponseWriter, r *http.Request) {
+	p.mu.Lock()
+	p.jwksRequests++
+	signer := p.signer(p.URL)
+	p.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, signer.JWKS())
+}
+
+func (p *Provider) token(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_request"})
+		return
+	}
+
+	clientID, clientSecret, _ := r.BasicAuth()
+	if clientID != ClientID || clientSecret != ClientSecret {
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid_client"})
+		return
+	}
+
+	p.mu.Lock()
+	code := r.PostForm.Get("code")
+	g, ok := p.codes[code]
+	delete(p.codes, code)
+	p.mu.Unlock()
+
+	if !ok || g.redirectURI != r.PostForm.Get("redirect_uri") || !auth.VerifyPKCE(r.PostForm.Get("code_verifier"), g.codeChallenge) {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant"})
+		return
+	}
+
+	idToken := p.idToken(g)
+	writeJSON(w, http.StatusOK, map[string]string{
+		"access_token": "opaque",
+		"token_type":   "Bearer",
+		"id_token":     idToken,
+	})
+}
+
+// idToken signs the ID token for a grant
+func (p *Provider) idToken(g grant) string {
+	issuer, audience, nonce := p.URL, ClientID, g.nonce
+	if g.user.Issuer != "" {
+		issuer = g.user.Issuer
+	}
+	if g.user.Audience != "" {
+		audience = g.user.Audience
+	}
+	if g.user.Nonce != "" {
+		nonce = g.user.Nonce
+	}
+
+	p.mu.Lock()
+	signer := p.signer(issuer)
+	p.mu.Unlock()
+
+	emailVerified := g.user.EmailVerified
+	idToken, err := signer.GenerateIDToken(g.user.Subject, audience, &auth.IDTokenClaims{
+		Name:          g.user.Name,
+		Email:         g.user.Email,
+		EmailVerified: &emailVerified,
+		Nonce:         nonce,
+	})
+	if err != nil {
+		p.t.Errorf("signing ID token: %v", err)
+	}
+	return idToken
+}
+
+// signer issues tokens with the current key. p.mu must be held.
+func (p *Provider) signer(issuer string) *auth.JWTAuth {
+	return auth.NewJWTAuthWithKey(p.key, time.Minute, auth.WithIssuer(issuer))
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,318 @@
+package oidc
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/yourusername/userapi/pkg/auth"
+)
+
+var (
+	// ErrInvalidIDToken is returned when an ID token fails verification
+	ErrInvalidIDToken = errors.New("invalid ID token")
+	// ErrTokenExchange is returned when the provider refuses an authorization code
+	ErrTokenExchange = errors.New("identity provider rejected the authorization code")
+)
+
+// discoveryPath is where providers publish their metadata, relative to the issuer
+const discoveryPath = "/.well-known/openid-configuration"
+
+// minKeyRefresh limits how often the JWKS is fetched again for an unknown key
+const minKeyRefresh = time.Minute
+
+// Config describes an upstream OpenID Connect provider
+type Config struct {
+	// Name identifies the provider in URLs and stored identities, e.g. "corp"
+	Name string
+	// Issuer is the provider's issuer URL, its discovery document is read from there
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	// RedirectURL is this service's callback URL registered with the provider
+	RedirectURL string
+	// Scopes defaults to openid, email and profile
+	Scopes []string
+}
+
+// Metadata is the part of a provider's discovery document a relying party needs
+type Metadata struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// Provider signs users in with an upstream OpenID Connect provider using the
+// authorization code flow with PKCE. Metadata and keys are fetched on first
+// use and cached.
+type Provider struct {
+	config     Config
+	httpClient *http.Client
+	leeway     time.Duration
+
+	mu            sync.Mutex
+	metadata      *Metadata
+	keys          *auth.Keyring
+	keysFetchedAt time.Time
+}
+
+// Option configures optional Provider behaviour
+type Option func(*Provider)
+
+// WithHTTPClient replaces the client used to talk to the provider
+func WithHTTPClient(client *http.Client) Option {
+	return func(p *Provider) {
+		p.httpClient = client
+	}
+}
+
+// WithLeeway allows for clock skew when checking time based claims
+func WithLeeway(leeway time.Duration) Option {
+	return func(p *Provider) {
+		p.leeway = leeway
+	}
+}
+
+// NewProvider creates a new upstream provider
+func NewProvider(config Config, opts ...Option) *Provider {
+	if len(config.Scopes) == 0 {
+		config.Scopes = []string{"openid", "email", "profile"}
+	}
+
+	p := &Provider{
+		config:     config,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+		leeway:     time.Minute,
+	}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	return p
+}
+
+// Name returns the name the provider is configured under
+func (p *Provider) Name() string {
+	return p.config.Name
+}
+
+// AuthCodeURL returns the provider URL to send the user to. state and nonce
+// must be unguessable, codeChallenge is the S256 PKCE challenge.
+func (p *Provider) AuthCodeURL(ctx context.Context, state, nonce, codeChallenge string) (string, error) {
+	metadata, err := p.discover(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	u, err := url.Parse(metadata.AuthorizationEndpoint)
+	if err != nil {
+		return "", err
+	}
+
+	query := u.Query()
+	query.Set("response_type", "code")
+	query.Set("client_id", p.config.ClientID)
+	query.Set("redirect_uri", p.config.RedirectURL)
+	query.Set("scope", strings.Join(p.config.Scopes, " "))
+	query.Set("state", state)
+	query.Set("nonce", nonce)
+	query.Set("code_challenge", codeChallenge)
+	query.Set("code_challenge_method", auth.PKCEMethodS256)
+	u.RawQuery = query.Encode()
+
+	return u.String(), nil
+}
+
+// Exchange redeems an authorization code and returns the raw ID token
+func (p *Provider) Exchange(ctx context.Context, code, codeVerifier string) (string, error) {
+	metadata, err := p.discover(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {p.config.RedirectURL},
+		"code_verifier": {codeVerifier},
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, metadata.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	req.SetBasicAuth(url.QueryEscape(p.config.ClientID), url.QueryEscape(p.config.ClientSecret))
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	var body struct {
+		IDToken          string `json:"id_token"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", fmt.Errorf("decoding token response: %v", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		if resp.StatusCode == http.StatusBadRequest || resp.StatusCode == http.StatusUnauthorized {
+			return "", ErrTokenExchange
+		}
+		return "", fmt.Errorf("token endpoint returned %d: %s %s", resp.StatusCode, body.Error, body.ErrorDescription)
+	}
+
+	if body.IDToken == "" {
+		return "", ErrInvalidIDToken
+	}
+
+	return body.IDToken, nil
+}
+
+// VerifyIDToken checks an ID token's signature, issuer, audience, expiry
+// and nonce and returns its claims
+func (p *Provider) VerifyIDToken(ctx context.Context, rawIDToken, nonce string) (*auth.IDTokenClaims, error) {
+	keys, err := p.keyring(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+
+	claims := &auth.IDTokenClaims{}
+	if err := keys.Parse(rawIDToken, claims); err != nil {
+		// The provider may have rotated its keys since they were fetched
+		keys, err = p.keyring(ctx, true)
+		if err != nil {
+			return nil, err
+		}
+		claims = &auth.IDTokenClaims{}
+		if err := keys.Parse(rawIDToken, claims); err != nil {
+			return nil, ErrInvalidIDToken
+		}
+	}
+
+	now := time.Now()
+	if !claims.VerifyIssuer(p.config.Issuer, true) || !claims.VerifyAudience(p.config.ClientID, true) {
+		return nil, ErrInvalidIDToken
+	}
+	if !claims.VerifyExpiresAt(now.Add(-p.leeway), true) || !claims.VerifyIssuedAt(now.Add(p.leeway), false) {
+		return nil, ErrInvalidIDToken
+	}
+	if claims.Subject == "" {
+		return nil, ErrInvalidIDToken
+	}
+
+	// The nonce ties the token to the login this service started
+	if subtle.ConstantTimeCompare([]byte(claims.Nonce), []byte(nonce)) != 1 {
+		return nil, ErrInvalidIDToken
+	}
+
+	return claims, nil
+}
+
+// discover returns the provider metadata, fetching it on first use
+func (p *Provider) discover(ctx context.Context) (*Metadata, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.metadata != nil {
+		return p.metadata, nil
+	}
+
+	var metadata Metadata
+	if err := p.getJSON(ctx, strings.TrimSuffix(p.config.Issuer, "/")+discoveryPath, &metadata); err != nil {
+		return nil, err
+	}
+
+	// The issuer must match exactly, or tokens could be accepted from another provider
+	if metadata.Issuer != p.config.Issuer {
+		return nil, fmt.Errorf("provider %s reports issuer %q, expected %q", p.config.Name, metadata.Issuer, p.config.Issuer)
+	}
+
+	p.metadata = &metadata
+	return p.metadata, nil
+}
+
+// keyring returns the provider's signing keys. With refresh set they are
+// fetched again, unless that was done very recently.
+func (p *Provider) keyring(ctx context.Context, refresh bool) (*auth.Keyring, error) {
+	metadata, err := p.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.keys != nil && (!refresh || time.Since(p.keysFetchedAt) < minKeyRefresh) {
+		return p.keys, nil
+	}
+
+	var set auth.JWKS
+	if err := p.getJSON(ctx, metadata.JWKSURI, &set); err != nil {
+		return nil, err
+	}
+
+	var verificationKeys []*auth.SigningKey
+	for _, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		// Skip keys of types we cannot verify with
+		key, err := jwk.SigningKey()
+		if err != nil {
+			continue
+		}
+		verificationKeys = append(verificationKeys, key)
+	}
+
+	// A provider with a single key may leave out the "kid" header, the
+	// keyring checks such tokens against its bootstrap key
+	var keys *auth.Keyring
+	if len(verificationKeys) == 1 {
+		keys = auth.NewKeyring(verificationKeys[0])
+	} else {
+		keys = auth.NewKeyring(nil)
+		for _, key := range verificationKeys {
+			keys.AddStatic(key)
+		}
+	}
+
+	p.keys = keys
+	p.keysFetchedAt = time.Now()
+	return p.keys, nil
+}
+
+// getJSON fetches and decodes a JSON document
+func (p *Provider) getJSON(ctx context.Context, url string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s returned %d", url, resp.StatusCode)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(v)
+}
@@ -0,0 +1,116 @@
+package oidc
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yourusername/userapi/pkg/auth"
+	"github.com/yourusername/userapi/pkg/oidc/oidctest"
+)
+
+const testNonce = "test-nonce"
+
+var testUser = oidctest.User{
+	Subject:       "upstream-1",
+	Name:          "Grace Hopper",
+	Email:         "grace@corp.example",
+	EmailVerified: true,
+}
+
+func newTestProvider(idp *oidctest.Provider) *Provider {
+	return NewProvider(Config{
+		Name:         "corp",
+		Issuer:       idp.Issuer(),
+		ClientID:     oidctest.ClientID,
+		ClientSecret: oidctest.ClientSecret,
+		RedirectURL:  "https://app.example/sso/corp/callback",
+	})
+}
+
+// signIn runs the authorization code flow and returns the raw ID token
+func signIn(t *testing.T, idp *oidctest.Provider, p *Provider, user oidctest.User) string {
+	t.Helper()
+
+	verifier := strings.Repeat("test-code-verifier-", 3)
+	authURL, err := p.AuthCodeURL(context.Background(), "state", testNonce, auth.PKCEChallenge(verifier))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	idToken, err := p.Exchange(context.Background(), idp.Authorize(authURL, user), verifier)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return idToken
+}
+
+func TestVerifyIDToken(t *testing.T) {
+	idp := oidctest.NewProvider(t)
+	p := newTestProvider(idp)
+
+	claims, err := p.VerifyIDToken(context.Background(), signIn(t, idp, p, testUser), testNonce)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if claims.Subject != testUser.Subject || claims.Email != testUser.Email || claims.EmailVerified == nil || !*claims.EmailVerified {
+		t.Errorf("unexpected claims: %+v", claims)
+	}
+}
+
+func TestVerifyIDTokenRejectsWrongClaims(t *testing.T) {
+	tests := []struct {
+		name   string
+		change func(user *oidctest.User)
+	}{
+		{"nonce mismatch", func(user *oidctest.User) { user.Nonce = "another-login" }},
+		{"wrong issuer", func(user *oidctest.User) { user.Issuer = "https://evil.example" }},
+		{"wrong audience", func(user *oidctest.User) { user.Audience = "another-client" }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			idp := oidctest.NewProvider(t)
+			p := newTestProvider(idp)
+
+			user := testUser
+			tt.change(&user)
+
+			if _, err := p.VerifyIDToken(context.Background(), signIn(t, idp, p, user), testNonce); err != ErrInvalidIDToken {
+				t.Errorf("got %v, want ErrInvalidIDToken", err)
+			}
+		})
+	}
+}
+
+func TestVerifyIDTokenRefetchesKeysAfterRotation(t *testing.T) {
+	idp := oidctest.NewProvider(t)
+	p := newTestProvider(idp)
+
+	if _, err := p.VerifyIDToken(context.Background(), signIn(t, idp, p, testUser), testNonce); err != nil {
+		t.Fatal(err)
+	}
+
+	idp.RotateKey()
+	rotated := signIn(t, idp, p, testUser)
+
+	// Keys fetched moments ago are not fetched again
+	if _, err := p.VerifyIDToken(context.Background(), rotated, testNonce); err != ErrInvalidIDToken {
+		t.Fatalf("got %v, want ErrInvalidIDToken within minKeyRefresh", err)
+	}
+	if idp.JWKSRequests() != 1 {
+		t.Fatalf("JWKS fetched %d times, want 1", idp.JWKSRequests())
+	}
+
+	p.mu.Lock()
+	p.keysFetchedAt = time.Now().Add(-minKeyRefresh)
+	p.mu.Unlock()
+
+	if _, err := p.VerifyIDToken(context.Background(), rotated, testNonce); err != nil {
+		t.Fatalf("token signed with the rotated key: %v", err)
+	}
+	if idp.JWKSRequests() != 2 {
+		t.Errorf("JWKS fetched %d times, want 2", idp.JWKSRequests())
+	}
+}
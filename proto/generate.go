@@ -0,0 +1,5 @@
+// Package proto holds the gRPC API definition and the code generated from it
+// with protoc-gen-go v1.36.5 and protoc-gen-go-grpc v1.5.1.
+package proto
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative user.proto